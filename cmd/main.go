@@ -2,6 +2,7 @@ package main
 
 import (
 	"content-maestro/internal/api"
+	"content-maestro/internal/imageurl"
 	"content-maestro/internal/logger"
 	"content-maestro/internal/middleware"
 	"content-maestro/internal/models"
@@ -11,8 +12,9 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	// Embedded so cron timezones resolve on images without a zoneinfo database.
+	_ "time/tzdata"
 
-	"github.com/go-co-op/gocron"
 	"github.com/joho/godotenv"
 )
 
@@ -92,31 +94,43 @@ func main() {
 		log.Errorf("Error initializing default settings: %v", err)
 		return
 	}
-	schedulers := map[string]*gocron.Scheduler{
-		"collect": schedule.CollectCron(storeInstance),
-		"message": schedule.MessageCron(storeInstance),
-	}
-
 	jobs := schedule.InitJobs(storeInstance)
+	schedulers := schedule.InitSchedulers(storeInstance, jobs)
+
+	retryQueue := schedule.RetryQueueCron(storeInstance)
+	defer retryQueue.Stop()
+
+	imageCache := schedule.ImageCacheCron()
+	defer imageCache.Stop()
 
 	cronAPI := server.NewCronAPI(storeInstance, schedulers, jobs)
 
 	mux := http.NewServeMux()
 
-	mux.Handle("/api/crons", middleware.LoggingMiddleware(middleware.CorsMiddleware(middleware.AuthMiddleware(http.HandlerFunc(cronAPI.GetCrons)))))
-	mux.Handle("/api/crons/collect/schedule", middleware.LoggingMiddleware(middleware.CorsMiddleware(middleware.AuthMiddleware(http.HandlerFunc(cronAPI.UpdateSchedule)))))
-	mux.Handle("/api/crons/message/schedule", middleware.LoggingMiddleware(middleware.CorsMiddleware(middleware.AuthMiddleware(http.HandlerFunc(cronAPI.UpdateSchedule)))))
-	mux.Handle("/api/crons/collect/status", middleware.LoggingMiddleware(middleware.CorsMiddleware(middleware.AuthMiddleware(http.HandlerFunc(cronAPI.UpdateStatus)))))
-	mux.Handle("/api/crons/message/status", middleware.LoggingMiddleware(middleware.CorsMiddleware(middleware.AuthMiddleware(http.HandlerFunc(cronAPI.UpdateStatus)))))
-	mux.Handle("/api/collect-settings", middleware.LoggingMiddleware(middleware.CorsMiddleware(middleware.AuthMiddleware(http.HandlerFunc(cronAPI.HandleCollectSettings)))))
-	mux.Handle("/api/prompt-settings", middleware.LoggingMiddleware(middleware.CorsMiddleware(middleware.AuthMiddleware(http.HandlerFunc(cronAPI.HandlePromptSettings)))))
-	mux.Handle("/api/cron-history", middleware.LoggingMiddleware(middleware.CorsMiddleware(middleware.AuthMiddleware(http.HandlerFunc(cronAPI.GetCronHistory)))))
-	mux.Handle("/api/message/retry", middleware.LoggingMiddleware(middleware.CorsMiddleware(middleware.AuthMiddleware(http.HandlerFunc(cronAPI.RetryMessagePost)))))
-	mux.Handle("/api/api-configs", middleware.LoggingMiddleware(middleware.CorsMiddleware(middleware.AuthMiddleware(http.HandlerFunc(cronAPI.HandleAPIConfigs)))))
-	mux.Handle("/api/api-configs/", middleware.LoggingMiddleware(middleware.CorsMiddleware(middleware.AuthMiddleware(http.HandlerFunc(cronAPI.HandleAPIConfig)))))
-
-	fs := http.FileServer(http.Dir("./tmp/gh_project_img"))
-	mux.Handle("/images/", http.StripPrefix("/images/", fs))
+	mux.Handle("/api/crons", middleware.LoggingMiddleware(middleware.CorsMiddleware(middleware.AuthMiddleware(storeInstance, models.ScopeCrons, http.HandlerFunc(cronAPI.HandleCrons)))))
+	mux.Handle("/api/crons/", middleware.LoggingMiddleware(middleware.CorsMiddleware(middleware.AuthMiddleware(storeInstance, models.ScopeCrons, http.HandlerFunc(cronAPI.HandleCron)))))
+	mux.Handle("/api/collect-settings", middleware.LoggingMiddleware(middleware.CorsMiddleware(middleware.AuthMiddleware(storeInstance, models.ScopeSettings, http.HandlerFunc(cronAPI.HandleCollectSettings)))))
+	mux.Handle("/api/collect-profiles", middleware.LoggingMiddleware(middleware.CorsMiddleware(middleware.AuthMiddleware(storeInstance, models.ScopeCrons, http.HandlerFunc(cronAPI.HandleCollectProfiles)))))
+	mux.Handle("/api/collect-profiles/", middleware.LoggingMiddleware(middleware.CorsMiddleware(middleware.AuthMiddleware(storeInstance, models.ScopeCrons, http.HandlerFunc(cronAPI.HandleCollectProfile)))))
+	mux.Handle("/api/prompt-settings", middleware.LoggingMiddleware(middleware.CorsMiddleware(middleware.AuthMiddleware(storeInstance, models.ScopeSettings, http.HandlerFunc(cronAPI.HandlePromptSettings)))))
+	mux.Handle("/api/prompt-settings/history", middleware.LoggingMiddleware(middleware.CorsMiddleware(middleware.AuthMiddleware(storeInstance, models.ScopeSettings, http.HandlerFunc(cronAPI.GetPromptRevisions)))))
+	mux.Handle("/api/prompt-settings/rollback/", middleware.LoggingMiddleware(middleware.CorsMiddleware(middleware.AuthMiddleware(storeInstance, models.ScopeSettings, http.HandlerFunc(cronAPI.RollbackPromptSettings)))))
+	mux.Handle("/api/prompt-versions", middleware.LoggingMiddleware(middleware.CorsMiddleware(middleware.AuthMiddleware(storeInstance, models.ScopeSettings, http.HandlerFunc(cronAPI.HandlePromptVersions)))))
+	mux.Handle("/api/prompt-versions/", middleware.LoggingMiddleware(middleware.CorsMiddleware(middleware.AuthMiddleware(storeInstance, models.ScopeSettings, http.HandlerFunc(cronAPI.HandlePromptVersion)))))
+	mux.Handle("/api/image-settings", middleware.LoggingMiddleware(middleware.CorsMiddleware(middleware.AuthMiddleware(storeInstance, models.ScopeSettings, http.HandlerFunc(cronAPI.HandleImageSettings)))))
+	mux.Handle("/api/cron-history", middleware.LoggingMiddleware(middleware.CorsMiddleware(middleware.AuthMiddleware(storeInstance, models.ScopeCrons, http.HandlerFunc(cronAPI.GetCronHistory)))))
+	mux.Handle("/api/deliveries", middleware.LoggingMiddleware(middleware.CorsMiddleware(middleware.AuthMiddleware(storeInstance, models.ScopeRetry, http.HandlerFunc(cronAPI.GetDeliveries)))))
+	mux.Handle("/api/message/retry", middleware.LoggingMiddleware(middleware.CorsMiddleware(middleware.AuthMiddleware(storeInstance, models.ScopeRetry, http.HandlerFunc(cronAPI.RetryMessagePost)))))
+	mux.Handle("/api/message/preview", middleware.LoggingMiddleware(middleware.CorsMiddleware(middleware.AuthMiddleware(storeInstance, models.ScopeRetry, http.HandlerFunc(cronAPI.PreviewMessage)))))
+	mux.Handle("/api/audit-log", middleware.LoggingMiddleware(middleware.CorsMiddleware(middleware.AuthMiddleware(storeInstance, models.ScopeReadOnly, http.HandlerFunc(cronAPI.GetAuditLog)))))
+	mux.Handle("/api/api-configs", middleware.LoggingMiddleware(middleware.CorsMiddleware(middleware.AuthMiddleware(storeInstance, models.ScopeConnectors, http.HandlerFunc(cronAPI.HandleAPIConfigs)))))
+	mux.Handle("/api/api-configs/", middleware.LoggingMiddleware(middleware.CorsMiddleware(middleware.AuthMiddleware(storeInstance, models.ScopeConnectors, http.HandlerFunc(cronAPI.HandleAPIConfig)))))
+	mux.Handle("/api/blackouts", middleware.LoggingMiddleware(middleware.CorsMiddleware(middleware.AuthMiddleware(storeInstance, models.ScopeCrons, http.HandlerFunc(cronAPI.HandleBlackouts)))))
+	mux.Handle("/api/blackouts/", middleware.LoggingMiddleware(middleware.CorsMiddleware(middleware.AuthMiddleware(storeInstance, models.ScopeCrons, http.HandlerFunc(cronAPI.HandleBlackout)))))
+	mux.Handle("/api/tokens", middleware.LoggingMiddleware(middleware.CorsMiddleware(middleware.AuthMiddleware(storeInstance, models.ScopeAdmin, http.HandlerFunc(cronAPI.HandleAPITokens)))))
+	mux.Handle("/api/tokens/", middleware.LoggingMiddleware(middleware.CorsMiddleware(middleware.AuthMiddleware(storeInstance, models.ScopeAdmin, http.HandlerFunc(cronAPI.HandleAPIToken)))))
+
+	mux.Handle("/images/", http.StripPrefix("/images/", imageurl.Handler("./tmp/gh_project_img")))
 
 	port := os.Getenv("API_PORT")
 	if port == "" {
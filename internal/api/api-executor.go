@@ -3,6 +3,7 @@ package api
 import (
 	"bytes"
 	"content-maestro/internal/logger"
+	"content-maestro/internal/models"
 	"content-maestro/internal/store"
 	"encoding/json"
 	"fmt"
@@ -12,6 +13,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -38,6 +40,25 @@ type APIEndpoint struct {
 	TextLanguage    string            `yaml:"text_language"`
 	SocialifyImage  bool              `yaml:"socialify_image"`
 	DefaultJSONBody map[string]string `yaml:"default_json_body"`
+	// RetryMaxAttempts is the automatic retry budget after a failed delivery;
+	// zero disables automatic retries for the connector.
+	RetryMaxAttempts int `yaml:"retry_max_attempts"`
+	// SuccessCodes, when set, replaces SuccessCode as the accepted statuses.
+	SuccessCodes []int `yaml:"success_codes"`
+	// SuccessField and SuccessValue check the parsed response body after the
+	// status matched; see checkSuccessRule.
+	SuccessField string `yaml:"success_field"`
+	SuccessValue string `yaml:"success_value"`
+	// PostIDField and PostURLField point at the created post in a successful
+	// response, so a delivery can link to it.
+	PostIDField  string `yaml:"post_id_field"`
+	PostURLField string `yaml:"post_url_field"`
+	// BodyTemplate, when set, renders the request payload instead of the
+	// built-in text/url fields; see RenderBodyTemplate.
+	BodyTemplate string `yaml:"body_template"`
+	// ImageSettings overrides the global image settings for this connector's
+	// image.
+	ImageSettings *models.ImageOverrides `yaml:"-"`
 }
 
 type RequestConfig struct {
@@ -56,6 +77,8 @@ type APIResponse struct {
 	Body         []byte        `json:"-"`
 	JSONResponse any           `json:"response,omitempty"`
 	Error        string        `json:"error,omitempty"`
+	PostID       string        `json:"post_id,omitempty"`
+	PostURL      string        `json:"post_url,omitempty"`
 	ResponseTime time.Duration `json:"response_time"`
 	APIName      string        `json:"api_name"`
 	Timestamp    time.Time     `json:"timestamp"`
@@ -91,20 +114,36 @@ func LoadAPIConfigs(s store.StoreInterface) error {
 			}
 		}
 
+		var headers map[string]string
+		if config.Headers != "" {
+			if err := json.Unmarshal([]byte(config.Headers), &headers); err != nil {
+				return fmt.Errorf("failed to parse headers for %s: %w", config.Name, err)
+			}
+		}
+
 		newConfig.APIs[config.Name] = APIEndpoint{
-			URL:             config.URL,
-			Method:          config.Method,
-			AuthType:        config.AuthType,
-			TokenEnvVar:     config.TokenEnvVar,
-			TokenHeader:     config.TokenHeader,
-			ContentType:     config.ContentType,
-			Timeout:         config.Timeout,
-			SuccessCode:     config.SuccessCode,
-			Enabled:         config.Enabled,
-			ResponseType:    config.ResponseType,
-			TextLanguage:    config.TextLanguage,
-			SocialifyImage:  config.SocialifyImage,
-			DefaultJSONBody: defaultJSONBody,
+			URL:              config.URL,
+			Method:           config.Method,
+			Headers:          headers,
+			AuthType:         config.AuthType,
+			TokenEnvVar:      config.TokenEnvVar,
+			TokenHeader:      config.TokenHeader,
+			ContentType:      config.ContentType,
+			Timeout:          config.Timeout,
+			SuccessCode:      config.SuccessCode,
+			Enabled:          config.Enabled,
+			ResponseType:     config.ResponseType,
+			TextLanguage:     config.TextLanguage,
+			SocialifyImage:   config.SocialifyImage,
+			DefaultJSONBody:  defaultJSONBody,
+			RetryMaxAttempts: config.RetryMaxAttempts,
+			BodyTemplate:     config.BodyTemplate,
+			SuccessCodes:     config.SuccessCodes,
+			SuccessField:     config.SuccessField,
+			SuccessValue:     config.SuccessValue,
+			PostIDField:      config.PostIDField,
+			PostURLField:     config.PostURLField,
+			ImageSettings:    config.ImageSettings,
 		}
 	}
 
@@ -120,27 +159,27 @@ func ReloadAPIConfigs(s store.StoreInterface) error {
 	return LoadAPIConfigs(s)
 }
 
-func ExecuteRequest(reqConfig RequestConfig) (*APIResponse, error) {
+// buildRequest turns a request config into the HTTP request ExecuteRequest
+// sends, so a preview shows exactly what a real send would.
+func buildRequest(reqConfig RequestConfig) (*http.Request, APIEndpoint, error) {
 	// Safely read the API endpoint config under lock
 	apiConfigMu.RLock()
 	if apiConfig == nil {
 		apiConfigMu.RUnlock()
-		return nil, fmt.Errorf("API configuration not loaded, call LoadAPIConfigs first")
+		return nil, APIEndpoint{}, fmt.Errorf("API configuration not loaded, call LoadAPIConfigs first")
 	}
 
 	apiEndpoint, exists := apiConfig.APIs[reqConfig.APIName]
 	apiConfigMu.RUnlock()
 
 	if !exists {
-		return nil, fmt.Errorf("API endpoint '%s' not found in configuration", reqConfig.APIName)
+		return nil, APIEndpoint{}, fmt.Errorf("API endpoint '%s' not found in configuration", reqConfig.APIName)
 	}
 
 	if !apiEndpoint.Enabled {
-		return nil, fmt.Errorf("API endpoint '%s' is disabled", reqConfig.APIName)
+		return nil, APIEndpoint{}, fmt.Errorf("API endpoint '%s' is disabled", reqConfig.APIName)
 	}
 
-	startTime := time.Now()
-
 	url := apiEndpoint.URL
 	if reqConfig.URLParams != nil {
 		for key, value := range reqConfig.URLParams {
@@ -174,7 +213,7 @@ func ExecuteRequest(reqConfig RequestConfig) (*APIResponse, error) {
 		if len(mergedJSONBody) > 0 {
 			jsonData, err := json.Marshal(mergedJSONBody)
 			if err != nil {
-				return nil, fmt.Errorf("failed to marshal JSON body: %w", err)
+				return nil, APIEndpoint{}, fmt.Errorf("failed to marshal JSON body: %w", err)
 			}
 			body = bytes.NewBuffer(jsonData)
 			contentType = "application/json"
@@ -185,29 +224,29 @@ func ExecuteRequest(reqConfig RequestConfig) (*APIResponse, error) {
 
 		for key, value := range reqConfig.FormFields {
 			if err := writer.WriteField(key, value); err != nil {
-				return nil, fmt.Errorf("failed to write form field '%s': %w", key, err)
+				return nil, APIEndpoint{}, fmt.Errorf("failed to write form field '%s': %w", key, err)
 			}
 		}
 
 		for fieldName, filePath := range reqConfig.FileFields {
 			file, err := os.Open(filePath)
 			if err != nil {
-				return nil, fmt.Errorf("failed to open file '%s': %w", filePath, err)
+				return nil, APIEndpoint{}, fmt.Errorf("failed to open file '%s': %w", filePath, err)
 			}
 			defer file.Close()
 
 			part, err := writer.CreateFormFile(fieldName, filepath.Base(filePath))
 			if err != nil {
-				return nil, fmt.Errorf("failed to create form file '%s': %w", fieldName, err)
+				return nil, APIEndpoint{}, fmt.Errorf("failed to create form file '%s': %w", fieldName, err)
 			}
 
 			if _, err = io.Copy(part, file); err != nil {
-				return nil, fmt.Errorf("failed to copy file contents: %w", err)
+				return nil, APIEndpoint{}, fmt.Errorf("failed to copy file contents: %w", err)
 			}
 		}
 
 		if err := writer.Close(); err != nil {
-			return nil, fmt.Errorf("failed to close multipart writer: %w", err)
+			return nil, APIEndpoint{}, fmt.Errorf("failed to close multipart writer: %w", err)
 		}
 
 		body = bodyBuf
@@ -216,7 +255,7 @@ func ExecuteRequest(reqConfig RequestConfig) (*APIResponse, error) {
 
 	req, err := http.NewRequest(apiEndpoint.Method, url, body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, APIEndpoint{}, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	if contentType != "" {
@@ -231,17 +270,28 @@ func ExecuteRequest(reqConfig RequestConfig) (*APIResponse, error) {
 	case "bearer":
 		token := os.Getenv(apiEndpoint.TokenEnvVar)
 		if token == "" {
-			return nil, fmt.Errorf("bearer token not found in environment variable '%s'", apiEndpoint.TokenEnvVar)
+			return nil, APIEndpoint{}, fmt.Errorf("bearer token not found in environment variable '%s'", apiEndpoint.TokenEnvVar)
 		}
 		req.Header.Set("Authorization", "Bearer "+token)
 	case "api_key":
 		token := os.Getenv(apiEndpoint.TokenEnvVar)
 		if token == "" {
-			return nil, fmt.Errorf("API key not found in environment variable '%s'", apiEndpoint.TokenEnvVar)
+			return nil, APIEndpoint{}, fmt.Errorf("API key not found in environment variable '%s'", apiEndpoint.TokenEnvVar)
 		}
 		req.Header.Set(apiEndpoint.TokenHeader, token)
 	}
 
+	return req, apiEndpoint, nil
+}
+
+func ExecuteRequest(reqConfig RequestConfig) (*APIResponse, error) {
+	startTime := time.Now()
+
+	req, apiEndpoint, err := buildRequest(reqConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	timeout := time.Duration(apiEndpoint.Timeout) * time.Second
 	if timeout == 0 {
 		timeout = 30 * time.Second
@@ -261,7 +311,7 @@ func ExecuteRequest(reqConfig RequestConfig) (*APIResponse, error) {
 
 	responseTime := time.Since(startTime)
 
-	success := resp.StatusCode == apiEndpoint.SuccessCode
+	success := apiEndpoint.acceptsStatus(resp.StatusCode)
 
 	apiResp := &APIResponse{
 		Success:      success,
@@ -272,7 +322,8 @@ func ExecuteRequest(reqConfig RequestConfig) (*APIResponse, error) {
 		Timestamp:    time.Now(),
 	}
 
-	if success && apiEndpoint.ResponseType == "json" {
+	if success && (apiEndpoint.ResponseType == "json" || apiEndpoint.SuccessField != "" ||
+		apiEndpoint.PostIDField != "" || apiEndpoint.PostURLField != "") {
 		var jsonResponse any
 		if err := json.Unmarshal(respBody, &jsonResponse); err != nil {
 			log.Debugf("Warning: Failed to parse JSON response: %v", err)
@@ -281,9 +332,80 @@ func ExecuteRequest(reqConfig RequestConfig) (*APIResponse, error) {
 		}
 	}
 
+	if success && apiEndpoint.SuccessField != "" {
+		if err := apiEndpoint.checkSuccessRule(apiResp.JSONResponse); err != nil {
+			apiResp.Success = false
+			apiResp.Error = err.Error()
+		}
+	}
+
+	if apiResp.Success {
+		apiResp.PostID = extractResponseField(apiResp.JSONResponse, apiEndpoint.PostIDField, reqConfig.APIName)
+		apiResp.PostURL = extractResponseField(apiResp.JSONResponse, apiEndpoint.PostURLField, reqConfig.APIName)
+	}
+
 	return apiResp, nil
 }
 
+// extractResponseField reads an optional value out of a successful response.
+// The post was created either way, so a missing field is only logged.
+func extractResponseField(jsonResponse any, path, apiName string) string {
+	if path == "" || jsonResponse == nil {
+		return ""
+	}
+
+	steps, err := parseJSONPath(path)
+	if err != nil {
+		log.Errorf("Invalid response field path %q for %s: %v", path, apiName, err)
+		return ""
+	}
+
+	value, ok := lookupJSONPath(jsonResponse, steps)
+	if !ok || value == nil {
+		log.Debugf("%s response has no %s field", apiName, path)
+		return ""
+	}
+	return jsonValueString(value)
+}
+
+func (e APIEndpoint) acceptsStatus(statusCode int) bool {
+	if len(e.SuccessCodes) == 0 {
+		return statusCode == e.SuccessCode
+	}
+	return slices.Contains(e.SuccessCodes, statusCode)
+}
+
+// checkSuccessRule catches connectors that answer 200 with an error in the body,
+// e.g. {"status": "error"}. Without an expected value the field only has to be
+// present and truthy.
+func (e APIEndpoint) checkSuccessRule(jsonResponse any) error {
+	if jsonResponse == nil {
+		return fmt.Errorf("response is not JSON, cannot check %s", e.SuccessField)
+	}
+
+	steps, err := parseJSONPath(e.SuccessField)
+	if err != nil {
+		return err
+	}
+
+	value, ok := lookupJSONPath(jsonResponse, steps)
+	if !ok {
+		return fmt.Errorf("response has no %s field", e.SuccessField)
+	}
+
+	if e.SuccessValue == "" {
+		if !truthy(value) {
+			return fmt.Errorf("response field %s is %s", e.SuccessField, jsonValueString(value))
+		}
+		return nil
+	}
+
+	if got := jsonValueString(value); got != e.SuccessValue {
+		return fmt.Errorf("response field %s is %q, want %q", e.SuccessField, got, e.SuccessValue)
+	}
+	return nil
+}
+
 func extractEnvVarsFromString(input string) []string {
 	var envVars []string
 	start := 0
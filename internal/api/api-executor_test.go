@@ -172,3 +172,135 @@ func TestExtractEnvVarsFromString(t *testing.T) {
 		}
 	}
 }
+
+func TestExecuteRequestSendsCustomHeaders(t *testing.T) {
+	var got http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	apiConfig = &APIConfig{
+		APIs: map[string]APIEndpoint{
+			"slack": {
+				URL:         server.URL,
+				Method:      "POST",
+				ContentType: "json",
+				SuccessCode: 200,
+				Enabled:     true,
+				Headers: map[string]string{
+					"X-Source":      "content-maestro",
+					"X-Workspace":   "{env.SLACK_WORKSPACE}",
+					"Authorization": "Token static",
+				},
+			},
+		},
+	}
+	t.Setenv("SLACK_WORKSPACE", "think-root")
+
+	if _, err := ExecuteRequest(RequestConfig{APIName: "slack", JSONBody: map[string]any{"text": "hi"}}); err != nil {
+		t.Fatalf("ExecuteRequest() error = %v", err)
+	}
+
+	want := map[string]string{
+		"X-Source":      "content-maestro",
+		"X-Workspace":   "think-root",
+		"Authorization": "Token static",
+		"Content-Type":  "application/json",
+	}
+	for name, value := range want {
+		if got.Get(name) != value {
+			t.Errorf("header %s = %q, want %q", name, got.Get(name), value)
+		}
+	}
+}
+
+func TestExecuteRequestAppliesSuccessRule(t *testing.T) {
+	var status int
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	apiConfig = &APIConfig{
+		APIs: map[string]APIEndpoint{
+			"telegram": {
+				URL:          server.URL,
+				Method:       "POST",
+				ContentType:  "json",
+				SuccessCode:  200,
+				SuccessCodes: []int{200, 201},
+				SuccessField: "$.status",
+				SuccessValue: "ok",
+				Enabled:      true,
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		wantSuccess bool
+		wantError   bool
+	}{
+		{name: "accepted status and field", status: 201, body: `{"status": "ok"}`, wantSuccess: true},
+		{name: "error in a 200 body", status: 200, body: `{"status": "error"}`, wantError: true},
+		{name: "missing field", status: 200, body: `{"message": "sent"}`, wantError: true},
+		{name: "body is not JSON", status: 200, body: `sent`, wantError: true},
+		{name: "status not accepted", status: 202, body: `{"status": "ok"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body = tt.status, tt.body
+
+			resp, err := ExecuteRequest(RequestConfig{APIName: "telegram", JSONBody: map[string]any{"text": "hi"}})
+			if err != nil {
+				t.Fatalf("ExecuteRequest() error = %v", err)
+			}
+			if resp.Success != tt.wantSuccess {
+				t.Errorf("Success = %v, want %v", resp.Success, tt.wantSuccess)
+			}
+			if (resp.Error != "") != tt.wantError {
+				t.Errorf("Error = %q, want an error: %v", resp.Error, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestExecuteRequestExtractsPostReference(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status":"ok","result":{"message_id":1327,"link":"https://t.me/think_root/1327"}}`))
+	}))
+	defer server.Close()
+
+	apiConfig = &APIConfig{
+		APIs: map[string]APIEndpoint{
+			"telegram": {
+				URL:          server.URL,
+				Method:       "POST",
+				ContentType:  "json",
+				SuccessCode:  200,
+				Enabled:      true,
+				PostIDField:  "$.result.message_id",
+				PostURLField: "result.link",
+			},
+		},
+	}
+
+	resp, err := ExecuteRequest(RequestConfig{APIName: "telegram", JSONBody: map[string]any{"text": "hi"}})
+	if err != nil {
+		t.Fatalf("ExecuteRequest() error = %v", err)
+	}
+	if resp.PostID != "1327" {
+		t.Errorf("PostID = %q, want 1327", resp.PostID)
+	}
+	if resp.PostURL != "https://t.me/think_root/1327" {
+		t.Errorf("PostURL = %q, want the permalink", resp.PostURL)
+	}
+}
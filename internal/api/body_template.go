@@ -0,0 +1,112 @@
+package api
+
+import (
+	"bytes"
+	"content-maestro/internal/repository"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// TemplateData is what a connector's body template can reference, e.g.
+// {{json .Item.Text}} or {{.RunTime.Format "2006-01-02"}}.
+type TemplateData struct {
+	Item     repository.Item
+	APIName  string
+	ImageURL string
+	Language string
+	RunTime  time.Time
+}
+
+var templateFuncs = template.FuncMap{
+	// json quotes a value for embedding in the payload. Post texts carry quotes
+	// and newlines, so a bare {{.Item.Text}} inside a JSON string breaks it.
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	},
+	// truncate cuts a string to at most n characters, for targets with a length
+	// limit.
+	"truncate": func(n int, s string) string {
+		runes := []rune(s)
+		if n < 0 || len(runes) <= n {
+			return s
+		}
+		return string(runes[:n])
+	},
+}
+
+// sampleTemplateData is rendered when a template is saved. The text contains a
+// quote and a newline so an unescaped field fails validation instead of the
+// first real post.
+var sampleTemplateData = TemplateData{
+	Item: repository.Item{
+		ID:        1,
+		URL:       "https://github.com/owner/repo",
+		Text:      "A \"sample\" post\nwith a second line",
+		DateAdded: "2024-03-15T10:00:00Z",
+	},
+	APIName:  "sample",
+	ImageURL: "https://example.com/images/image.png",
+	Language: "en",
+	RunTime:  time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC),
+}
+
+func parseBodyTemplate(text string) (*template.Template, error) {
+	return template.New("body").Option("missingkey=error").Funcs(templateFuncs).Parse(text)
+}
+
+// RenderBodyTemplate executes a body template and decodes the result, which must
+// be a JSON object.
+func RenderBodyTemplate(text string, data TemplateData) (map[string]any, error) {
+	tmpl, err := parseBodyTemplate(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse body template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to execute body template: %w", err)
+	}
+
+	var body map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &body); err != nil {
+		return nil, fmt.Errorf("body template must render a JSON object (quote values with {{json ...}}): %w", err)
+	}
+	return body, nil
+}
+
+// ValidateBodyTemplate checks that a template parses and renders a JSON object
+// for a sample repository.
+func ValidateBodyTemplate(text string) error {
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+
+	_, err := RenderBodyTemplate(text, sampleTemplateData)
+	return err
+}
+
+// FormFieldsFromBody flattens a rendered body into multipart form fields.
+// Strings are sent as they are; anything else is sent as its JSON encoding.
+func FormFieldsFromBody(body map[string]any) (map[string]string, error) {
+	fields := make(map[string]string, len(body))
+	for key, value := range body {
+		if s, ok := value.(string); ok {
+			fields[key] = s
+			continue
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode form field '%s': %w", key, err)
+		}
+		fields[key] = string(data)
+	}
+	return fields, nil
+}
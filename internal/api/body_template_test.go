@@ -0,0 +1,96 @@
+package api
+
+import (
+	"content-maestro/internal/repository"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRenderBodyTemplate(t *testing.T) {
+	data := TemplateData{
+		Item:     repository.Item{ID: 7, URL: "https://github.com/owner/repo", Text: "Line \"one\"\nline two"},
+		APIName:  "discord",
+		ImageURL: "https://example.com/images/image.png",
+		Language: "uk",
+		RunTime:  time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		want     map[string]any
+		wantErr  bool
+	}{
+		{
+			name:     "discord webhook",
+			template: `{"content": {{json .Item.Text}}, "embeds": [{"url": {{json .Item.URL}}, "image": {"url": {{json .ImageURL}}}}]}`,
+			want: map[string]any{
+				"content": "Line \"one\"\nline two",
+				"embeds": []any{map[string]any{
+					"url":   "https://github.com/owner/repo",
+					"image": map[string]any{"url": "https://example.com/images/image.png"},
+				}},
+			},
+		},
+		{
+			name:     "language, run time and truncate",
+			template: `{"lang": {{json .Language}}, "day": {{json (.RunTime.Format "2006-01-02")}}, "status": {{json (truncate 4 .Item.Text)}}}`,
+			want:     map[string]any{"lang": "uk", "day": "2024-03-15", "status": "Line"},
+		},
+		{
+			name:     "unquoted text is not valid JSON",
+			template: `{"text": "{{.Item.Text}}"}`,
+			wantErr:  true,
+		},
+		{
+			name:     "not an object",
+			template: `[{{json .Item.URL}}]`,
+			wantErr:  true,
+		},
+		{
+			name:     "unknown field",
+			template: `{"text": {{json .Item.Title}}}`,
+			wantErr:  true,
+		},
+		{
+			name:     "parse error",
+			template: `{"text": {{json .Item.Text}`,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderBodyTemplate(tt.template, data)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("RenderBodyTemplate() = %v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RenderBodyTemplate() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RenderBodyTemplate() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormFieldsFromBody(t *testing.T) {
+	got, err := FormFieldsFromBody(map[string]any{
+		"status":     "hello",
+		"sensitive":  false,
+		"media_tags": []any{"go"},
+	})
+	if err != nil {
+		t.Fatalf("FormFieldsFromBody() error = %v", err)
+	}
+
+	want := map[string]string{"status": "hello", "sensitive": "false", "media_tags": `["go"]`}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FormFieldsFromBody() = %v, want %v", got, want)
+	}
+}
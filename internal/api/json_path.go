@@ -0,0 +1,119 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// pathStep is one step of a parsed JSON path: an object key, or an array index
+// when isIndex is set.
+type pathStep struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// parseJSONPath parses the JSONPath subset used by success rules: dotted keys
+// and numeric indexes, with an optional leading "$", e.g. "$.data.ok",
+// "result.messages[0].id" or "$[0].status".
+func parseJSONPath(path string) ([]pathStep, error) {
+	rest := strings.TrimSpace(path)
+	rest = strings.TrimPrefix(rest, "$")
+	rest = strings.TrimPrefix(rest, ".")
+	if rest == "" {
+		return nil, fmt.Errorf("path %q selects no field", path)
+	}
+
+	var steps []pathStep
+	for _, segment := range strings.Split(rest, ".") {
+		key, indexes, hasIndex := strings.Cut(segment, "[")
+		if key == "" && !hasIndex {
+			return nil, fmt.Errorf("path %q has an empty segment", path)
+		}
+		if key != "" {
+			steps = append(steps, pathStep{key: key})
+		}
+		if !hasIndex {
+			continue
+		}
+
+		// indexes is what followed the first "[", e.g. "0]" or "0][1]".
+		for _, part := range strings.Split(indexes, "[") {
+			digits, ok := strings.CutSuffix(part, "]")
+			if !ok {
+				return nil, fmt.Errorf("path %q has an unterminated index", path)
+			}
+			index, err := strconv.Atoi(digits)
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("path %q has an invalid index %q", path, digits)
+			}
+			steps = append(steps, pathStep{index: index, isIndex: true})
+		}
+	}
+
+	return steps, nil
+}
+
+// lookupJSONPath walks a decoded JSON document. The boolean is false when the
+// path does not exist in it.
+func lookupJSONPath(doc any, steps []pathStep) (any, bool) {
+	current := doc
+	for _, step := range steps {
+		if step.isIndex {
+			array, ok := current.([]any)
+			if !ok || step.index >= len(array) {
+				return nil, false
+			}
+			current = array[step.index]
+			continue
+		}
+
+		object, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		current, ok = object[step.key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// ValidateJSONPath reports whether a path is usable in a success rule.
+func ValidateJSONPath(path string) error {
+	_, err := parseJSONPath(path)
+	return err
+}
+
+// jsonValueString renders a decoded JSON value for comparison with a configured
+// expected value: strings as they are, everything else as its JSON encoding,
+// so 1, true and null compare as "1", "true" and "null".
+func jsonValueString(value any) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// truthy is the check used when a rule names a field without an expected value.
+func truthy(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	case float64:
+		return v != 0
+	default:
+		return true
+	}
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLookupJSONPath(t *testing.T) {
+	var doc any
+	if err := json.Unmarshal([]byte(`{"status": "ok", "ok": true, "result": {"messages": [{"id": 42}, {"id": 43}]}, "count": 0}`), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path  string
+		want  string
+		found bool
+	}{
+		{path: "status", want: "ok", found: true},
+		{path: "$.status", want: "ok", found: true},
+		{path: "$.ok", want: "true", found: true},
+		{path: "result.messages[1].id", want: "43", found: true},
+		{path: "$.count", want: "0", found: true},
+		{path: "result.messages[2].id"},
+		{path: "status.code"},
+		{path: "missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			steps, err := parseJSONPath(tt.path)
+			if err != nil {
+				t.Fatalf("parseJSONPath(%q) error = %v", tt.path, err)
+			}
+			value, found := lookupJSONPath(doc, steps)
+			if found != tt.found {
+				t.Fatalf("lookupJSONPath(%q) found = %v, want %v", tt.path, found, tt.found)
+			}
+			if found && jsonValueString(value) != tt.want {
+				t.Errorf("lookupJSONPath(%q) = %s, want %s", tt.path, jsonValueString(value), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseJSONPathRejectsMalformedPaths(t *testing.T) {
+	for _, path := range []string{"", "$", "a..b", "a[", "a[x]", "a[-1]", "a[0]b"} {
+		if _, err := parseJSONPath(path); err == nil {
+			t.Errorf("parseJSONPath(%q) = nil error, want an error", path)
+		}
+	}
+
+	if _, err := parseJSONPath("$[0].items[1][2]"); err != nil {
+		t.Errorf("parseJSONPath() error = %v for a root index path", err)
+	}
+}
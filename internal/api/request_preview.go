@@ -0,0 +1,238 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maskedValue replaces header values that carry secrets in a preview.
+const maskedValue = "********"
+
+// RequestPreview is the request ExecuteRequest would send, with secrets masked.
+type RequestPreview struct {
+	APIName string            `json:"api_name"`
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+	// Body is the decoded JSON body of a json connector.
+	Body any `json:"body,omitempty"`
+	// FormFields and Files describe the body of a multipart connector.
+	FormFields map[string]string `json:"form_fields,omitempty"`
+	Files      []PreviewFile     `json:"files,omitempty"`
+}
+
+// PreviewFile is a file attached to a multipart request.
+type PreviewFile struct {
+	Field    string `json:"field"`
+	Filename string `json:"filename"`
+	Size     int64  `json:"size"`
+}
+
+// PreviewRequest builds the request for reqConfig exactly as ExecuteRequest
+// would, but returns a description of it instead of sending it.
+func PreviewRequest(reqConfig RequestConfig) (*RequestPreview, error) {
+	req, apiEndpoint, err := buildRequest(reqConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &RequestPreview{
+		APIName: reqConfig.APIName,
+		Method:  req.Method,
+		URL:     req.URL.String(),
+		Headers: previewHeaders(req.Header, apiEndpoint),
+	}
+
+	if req.Body == nil {
+		return preview, nil
+	}
+
+	switch apiEndpoint.ContentType {
+	case "json":
+		data, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		if err := json.Unmarshal(data, &preview.Body); err != nil {
+			preview.Body = string(data)
+		}
+	case "multipart":
+		if err := req.ParseMultipartForm(32 << 20); err != nil {
+			return nil, fmt.Errorf("failed to read multipart body: %w", err)
+		}
+		defer req.MultipartForm.RemoveAll()
+
+		preview.FormFields = map[string]string{}
+		for key, values := range req.MultipartForm.Value {
+			preview.FormFields[key] = strings.Join(values, ", ")
+		}
+		for field, headers := range req.MultipartForm.File {
+			for _, header := range headers {
+				preview.Files = append(preview.Files, PreviewFile{Field: field, Filename: header.Filename, Size: header.Size})
+			}
+		}
+	}
+
+	return preview, nil
+}
+
+// previewHeaders flattens the request headers, masking the auth header and the
+// custom headers the way MaskHeaders does: a literal value may be a credential
+// and is masked, a value from the environment is shown as the reference it
+// was configured with rather than what it resolved to.
+func previewHeaders(header http.Header, apiEndpoint APIEndpoint) map[string]string {
+	secret := map[string]bool{}
+	switch apiEndpoint.AuthType {
+	case "bearer":
+		secret["Authorization"] = true
+	case "api_key":
+		secret[http.CanonicalHeaderKey(apiEndpoint.TokenHeader)] = true
+	}
+	custom := make(map[string]string, len(apiEndpoint.Headers))
+	for key, value := range apiEndpoint.Headers {
+		custom[http.CanonicalHeaderKey(key)] = maskLiteral(value)
+	}
+
+	headers := make(map[string]string, len(header))
+	for key := range header {
+		value := header.Get(key)
+		if configured, ok := custom[key]; ok {
+			value = configured
+		}
+		if secret[key] {
+			value = maskedValue
+			if key == "Authorization" && apiEndpoint.AuthType == "bearer" {
+				value = "Bearer " + maskedValue
+			}
+		}
+		headers[key] = value
+	}
+	return headers
+}
+
+// MaskHeaders masks the values of a connector config's headers, a JSON object
+// as stored, for records kept outside the config. A literal value may be a
+// credential; a value that references the environment only names one and is
+// kept. Headers that are not a JSON object are masked as a whole.
+func MaskHeaders(headers string) string {
+	if headers == "" {
+		return ""
+	}
+
+	var values map[string]string
+	if err := json.Unmarshal([]byte(headers), &values); err != nil {
+		return maskedValue
+	}
+	for key, value := range values {
+		if !strings.Contains(value, "{env.") {
+			values[key] = maskedValue
+		}
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return maskedValue
+	}
+	return string(data)
+}
+
+// MaskURL masks the literal path segments and query values of a connector URL,
+// where services like Telegram carry the token. The scheme and host are kept,
+// and so is every part that references the environment.
+func MaskURL(rawURL string) string {
+	if rawURL == "" {
+		return ""
+	}
+
+	rest, fragment, hasFragment := strings.Cut(rawURL, "#")
+	rest, query, hasQuery := strings.Cut(rest, "?")
+	scheme, rest, hasScheme := strings.Cut(rest, "://")
+	if !hasScheme {
+		return maskedValue
+	}
+	host, path, hasPath := strings.Cut(rest, "/")
+	if _, hostname, ok := strings.Cut(host, "@"); ok && !strings.Contains(host, "{env.") {
+		host = maskedValue + "@" + hostname
+	}
+
+	masked := scheme + "://" + host
+	if hasPath {
+		segments := strings.Split(path, "/")
+		for i, segment := range segments {
+			segments[i] = maskLiteral(segment)
+		}
+		masked += "/" + strings.Join(segments, "/")
+	}
+	if hasQuery {
+		params := strings.Split(query, "&")
+		for i, param := range params {
+			if key, value, ok := strings.Cut(param, "="); ok {
+				params[i] = key + "=" + maskLiteral(value)
+			}
+		}
+		masked += "?" + strings.Join(params, "&")
+	}
+	if hasFragment {
+		masked += "#" + maskLiteral(fragment)
+	}
+	return masked
+}
+
+// MaskJSONBody masks the string values of a connector's default JSON body, at
+// any depth, like MaskHeaders does header values. Numbers, booleans and values
+// that reference the environment are kept. A body that is not JSON is masked as
+// a whole.
+func MaskJSONBody(body string) string {
+	if body == "" {
+		return ""
+	}
+
+	var value any
+	if err := json.Unmarshal([]byte(body), &value); err != nil {
+		return maskedValue
+	}
+	data, err := json.Marshal(maskJSONValue(value))
+	if err != nil {
+		return maskedValue
+	}
+	return string(data)
+}
+
+func maskJSONValue(value any) any {
+	switch v := value.(type) {
+	case string:
+		if strings.Contains(v, "{env.") {
+			return v
+		}
+		return maskedValue
+	case map[string]any:
+		for key, item := range v {
+			v[key] = maskJSONValue(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = maskJSONValue(item)
+		}
+	}
+	return value
+}
+
+// MaskBodyTemplate masks a connector's body template. A template is free text
+// that may embed a credential anywhere, so it is masked as a whole.
+func MaskBodyTemplate(template string) string {
+	if template == "" {
+		return ""
+	}
+	return maskedValue
+}
+
+// maskLiteral masks a non-empty value unless it references the environment.
+func maskLiteral(value string) string {
+	if value == "" || strings.Contains(value, "{env.") {
+		return value
+	}
+	return maskedValue
+}
@@ -0,0 +1,75 @@
+package imageprovider
+
+import (
+	"content-maestro/internal/logger"
+	"content-maestro/internal/models"
+	"fmt"
+	"time"
+)
+
+var log = logger.NewLogger()
+
+// ImageProvider writes the image for a repository, given as "owner/name", into
+// outputPath. Generate makes a single attempt; retries are the caller's, through
+// the package-level Generate.
+type ImageProvider interface {
+	Name() string
+	Generate(usernameRepo, outputPath string, settings models.ImageSettings) error
+}
+
+type RetryConfig struct {
+	MaxRetries    int
+	RetryInterval time.Duration
+}
+
+// DefaultRetryConfig is the budget scheduled posts get: providers that depend
+// on a remote service often recover within a minute or two.
+var DefaultRetryConfig = RetryConfig{
+	MaxRetries:    5,
+	RetryInterval: 20 * time.Second,
+}
+
+// Generate runs p until it succeeds or config.MaxRetries attempts have failed,
+// returning the last error.
+func Generate(p ImageProvider, usernameRepo, outputPath string, settings models.ImageSettings, config RetryConfig) error {
+	log.Debugf("Starting %s image generation", p.Name())
+
+	if config.MaxRetries < 1 {
+		config.MaxRetries = 1
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= config.MaxRetries; attempt++ {
+		err := p.Generate(usernameRepo, outputPath, settings)
+		if err == nil {
+			log.Debugf("%s image generation finished", p.Name())
+			return nil
+		}
+
+		lastErr = err
+		if attempt < config.MaxRetries {
+			log.Errorf("Attempt %d failed: %v. Retrying in %s...", attempt, err, config.RetryInterval)
+			time.Sleep(config.RetryInterval)
+		}
+	}
+
+	log.Debugf("All %d attempts failed. Last error: %v", config.MaxRetries, lastErr)
+	return lastErr
+}
+
+// ForSettings returns the provider settings.Provider names. An empty name is
+// socialify, the provider images came from before there was a choice.
+func ForSettings(settings models.ImageSettings) (ImageProvider, error) {
+	switch settings.Provider {
+	case models.ImageProviderSocialify, "":
+		return Socialify{BaseURL: settings.SocialifyURL}, nil
+	case models.ImageProviderLocal:
+		return Local{}, nil
+	case models.ImageProviderOpenGraph:
+		return OpenGraph{}, nil
+	case models.ImageProviderStatic:
+		return StaticFile{Path: settings.StaticFile}, nil
+	default:
+		return nil, fmt.Errorf("unknown image provider: %s", settings.Provider)
+	}
+}
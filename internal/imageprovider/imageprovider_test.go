@@ -0,0 +1,181 @@
+package imageprovider
+
+import (
+	"bytes"
+	"content-maestro/internal/models"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// stubProvider fails its first failures attempts and then writes a fixed file.
+type stubProvider struct {
+	failures int
+	calls    int
+}
+
+func (s *stubProvider) Name() string { return "stub" }
+
+func (s *stubProvider) Generate(_, outputPath string, _ models.ImageSettings) error {
+	s.calls++
+	if s.calls <= s.failures {
+		return errors.New("stub failure")
+	}
+	return os.WriteFile(outputPath, []byte("image"), 0o644)
+}
+
+type mockTransport struct {
+	status int
+	body   []byte
+	path   string
+}
+
+func (m *mockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	m.path = req.URL.Path
+	return &http.Response{
+		StatusCode: m.status,
+		Body:       io.NopCloser(bytes.NewReader(m.body)),
+	}, nil
+}
+
+var fastRetries = RetryConfig{MaxRetries: 3, RetryInterval: time.Millisecond}
+
+func TestGenerateRetries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "image.png")
+	stub := &stubProvider{failures: 2}
+
+	if err := Generate(stub, "test/repo", path, models.DefaultImageSettings(), fastRetries); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if stub.calls != 3 {
+		t.Errorf("provider called %d times, want 3", stub.calls)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("image was not written: %v", err)
+	}
+}
+
+func TestGenerateGivesUp(t *testing.T) {
+	stub := &stubProvider{failures: 10}
+
+	err := Generate(stub, "test/repo", filepath.Join(t.TempDir(), "image.png"), models.DefaultImageSettings(), fastRetries)
+	if err == nil {
+		t.Fatal("expected an error once every attempt failed")
+	}
+	if stub.calls != fastRetries.MaxRetries {
+		t.Errorf("provider called %d times, want %d", stub.calls, fastRetries.MaxRetries)
+	}
+}
+
+func TestForSettings(t *testing.T) {
+	tests := []struct {
+		settings models.ImageSettings
+		want     ImageProvider
+	}{
+		{models.ImageSettings{}, Socialify{}},
+		{models.ImageSettings{Provider: models.ImageProviderSocialify, SocialifyURL: "https://socialify.example.com"}, Socialify{BaseURL: "https://socialify.example.com"}},
+		{models.ImageSettings{Provider: models.ImageProviderLocal}, Local{}},
+		{models.ImageSettings{Provider: models.ImageProviderOpenGraph}, OpenGraph{}},
+		{models.ImageSettings{Provider: models.ImageProviderStatic, StaticFile: "assets/banner.jpg"}, StaticFile{Path: "assets/banner.jpg"}},
+	}
+	for _, tt := range tests {
+		got, err := ForSettings(tt.settings)
+		if err != nil {
+			t.Errorf("ForSettings(%+v) error = %v", tt.settings, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ForSettings(%+v) = %#v, want %#v", tt.settings, got, tt.want)
+		}
+	}
+
+	if _, err := ForSettings(models.ImageSettings{Provider: "unknown"}); err == nil {
+		t.Error("expected an error for an unknown provider")
+	}
+}
+
+func TestOpenGraph(t *testing.T) {
+	var served bytes.Buffer
+	if err := png.Encode(&served, image.NewRGBA(image.Rect(0, 0, 1200, 600))); err != nil {
+		t.Fatal(err)
+	}
+	transport := &mockTransport{status: http.StatusOK, body: served.Bytes()}
+	oldClient := OpenGraphHTTPClient
+	OpenGraphHTTPClient = &http.Client{Transport: transport}
+	defer func() { OpenGraphHTTPClient = oldClient }()
+
+	settings := models.DefaultImageSettings()
+	settings.Format = models.ImageFormatJPEG
+	path := filepath.Join(t.TempDir(), "image.jpg")
+	if err := (OpenGraph{}).Generate("think-root/content-maestro", path, settings); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if transport.path != "/1/think-root/content-maestro" {
+		t.Errorf("requested %s", transport.path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	config, err := jpeg.DecodeConfig(file)
+	if err != nil {
+		t.Fatalf("image is not a JPEG: %v", err)
+	}
+	if config.Width != 1280 || config.Height != 640 {
+		t.Errorf("image is %dx%d, want 1280x640", config.Width, config.Height)
+	}
+
+	transport.status = http.StatusNotFound
+	if err := (OpenGraph{}).Generate("think-root/missing", path, settings); err == nil {
+		t.Error("expected an error for a non-OK status")
+	}
+}
+
+func TestStaticFile(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "banner.png")
+	file, err := os.Create(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(file, image.NewRGBA(image.Rect(0, 0, 800, 400))); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+
+	settings := models.DefaultImageSettings()
+	settings.Width = 640
+	path := filepath.Join(dir, "image.png")
+	if err := (StaticFile{Path: source}).Generate("test/repo", path, settings); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	out, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	config, err := png.DecodeConfig(out)
+	if err != nil {
+		t.Fatalf("image is not a PNG: %v", err)
+	}
+	if config.Width != 640 || config.Height != 320 {
+		t.Errorf("image is %dx%d, want 640x320", config.Width, config.Height)
+	}
+
+	if err := (StaticFile{}).Generate("test/repo", path, settings); err == nil {
+		t.Error("expected an error without a file")
+	}
+	if err := (StaticFile{Path: filepath.Join(dir, "missing.png")}).Generate("test/repo", path, settings); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
@@ -0,0 +1,97 @@
+package imageprovider
+
+import (
+	"content-maestro/internal/models"
+	"content-maestro/internal/render"
+	"content-maestro/internal/socialify"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const openGraphURL = "https://opengraph.githubassets.com/1/"
+
+var OpenGraphHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// Socialify fetches the card from socialify, the public instance unless
+// BaseURL points at a self-hosted one.
+type Socialify struct {
+	BaseURL string
+}
+
+func (Socialify) Name() string { return models.ImageProviderSocialify }
+
+func (p Socialify) Generate(usernameRepo, outputPath string, settings models.ImageSettings) error {
+	return socialify.Fetch(p.BaseURL, usernameRepo, outputPath, settings)
+}
+
+// Local renders the card itself. It is also the fallback for every other
+// provider.
+type Local struct{}
+
+func (Local) Name() string { return models.ImageProviderLocal }
+
+func (Local) Generate(usernameRepo, outputPath string, settings models.ImageSettings) error {
+	return render.RepoImage(usernameRepo, outputPath, settings)
+}
+
+// OpenGraph uses the preview image GitHub shows when a repository link is
+// shared. Only the format and width settings apply to it.
+type OpenGraph struct{}
+
+func (OpenGraph) Name() string { return models.ImageProviderOpenGraph }
+
+func (OpenGraph) Generate(usernameRepo, outputPath string, settings models.ImageSettings) error {
+	req, err := http.NewRequest("GET", openGraphURL+strings.Trim(usernameRepo, "/"), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "Content-Maestro/1.0")
+
+	response, err := OpenGraphHTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("github opengraph returned status %d for %s", response.StatusCode, usernameRepo)
+	}
+
+	img, _, err := image.Decode(response.Body)
+	if err != nil {
+		return fmt.Errorf("failed to decode opengraph image: %w", err)
+	}
+	return render.Save(img, outputPath, settings.Format, settings.Width)
+}
+
+// StaticFile sends the same image, read from Path, with every post.
+type StaticFile struct {
+	Path string
+}
+
+func (StaticFile) Name() string { return models.ImageProviderStatic }
+
+func (p StaticFile) Generate(_, outputPath string, settings models.ImageSettings) error {
+	if p.Path == "" {
+		return errors.New("static image provider has no file configured")
+	}
+
+	file, err := os.Open(p.Path)
+	if err != nil {
+		return fmt.Errorf("failed to open static image: %w", err)
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return fmt.Errorf("failed to decode static image %s: %w", p.Path, err)
+	}
+	return render.Save(img, outputPath, settings.Format, settings.Width)
+}
@@ -0,0 +1,130 @@
+package imageurl
+
+import (
+	"content-maestro/internal/logger"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var log = logger.NewLogger()
+
+const defaultTTL = time.Hour
+
+// TTL is how long a signed image URL stays valid, from IMAGE_URL_TTL in
+// seconds. Some connectors hand the URL to a platform that fetches the image
+// minutes later, so it cannot be too short.
+func TTL() time.Duration {
+	ttlStr := os.Getenv("IMAGE_URL_TTL")
+	if ttlStr == "" {
+		return defaultTTL
+	}
+
+	ttlSeconds, err := strconv.Atoi(ttlStr)
+	if err != nil || ttlSeconds < 1 {
+		log.Errorf("Invalid IMAGE_URL_TTL value: %s, using default %s", ttlStr, defaultTTL)
+		return defaultTTL
+	}
+
+	return time.Duration(ttlSeconds) * time.Second
+}
+
+var (
+	generatedKey     []byte
+	generatedKeyOnce sync.Once
+)
+
+// key is IMAGE_URL_SECRET, or a random key when it is not set. A random key
+// only lives as long as the process, so links handed out before a restart stop
+// working; set the secret to keep them valid.
+func key() []byte {
+	if secret := os.Getenv("IMAGE_URL_SECRET"); secret != "" {
+		return []byte(secret)
+	}
+
+	generatedKeyOnce.Do(func() {
+		generatedKey = make([]byte, 32)
+		if _, err := rand.Read(generatedKey); err != nil {
+			panic(fmt.Sprintf("failed to generate image URL key: %v", err))
+		}
+		log.Debug("IMAGE_URL_SECRET not set, signing image URLs with a key generated at startup")
+	})
+	return generatedKey
+}
+
+// cleanPath normalises an image path relative to the image root, so a URL
+// with "./" or doubled slashes signs and verifies like the plain one.
+func cleanPath(imagePath string) string {
+	return path.Clean("/" + imagePath)[1:]
+}
+
+func signature(imagePath string, expires int64) string {
+	mac := hmac.New(sha256.New, key())
+	fmt.Fprintf(mac, "%s\n%d", imagePath, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Sign returns the URL under which the image at imagePath, relative to the
+// image root, can be fetched until expires. publicURL is the base the service
+// is reachable at.
+func Sign(publicURL, imagePath string, expires time.Time) string {
+	imagePath = cleanPath(imagePath)
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires.Unix(), 10))
+	query.Set("signature", signature(imagePath, expires.Unix()))
+	return fmt.Sprintf("%s/images/%s?%s", strings.TrimRight(publicURL, "/"), imagePath, query.Encode())
+}
+
+// Verify reports whether signatureHex signs imagePath until expires and the
+// link has not expired at now.
+func Verify(imagePath, expires, signatureHex string, now time.Time) bool {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || now.Unix() > expiresAt {
+		return false
+	}
+
+	want, err := hex.DecodeString(signature(cleanPath(imagePath), expiresAt))
+	if err != nil {
+		return false
+	}
+	got, err := hex.DecodeString(signatureHex)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(got, want)
+}
+
+// Handler serves the images under dir to requests that carry a valid
+// signature, and answers everything else - unsigned, tampered or expired
+// links, and directories - with 404, so the images cannot be enumerated. It is
+// mounted with the /images/ prefix stripped.
+func Handler(dir string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		imagePath := strings.TrimPrefix(r.URL.Path, "/")
+		query := r.URL.Query()
+		if imagePath == "" || !Verify(imagePath, query.Get("expires"), query.Get("signature"), time.Now()) {
+			http.NotFound(w, r)
+			return
+		}
+
+		fullPath := filepath.Join(dir, filepath.FromSlash(cleanPath(imagePath)))
+		info, err := os.Stat(fullPath)
+		if err != nil || !info.Mode().IsRegular() {
+			http.NotFound(w, r)
+			return
+		}
+
+		http.ServeFile(w, r, fullPath)
+	})
+}
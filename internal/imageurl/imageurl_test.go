@@ -0,0 +1,117 @@
+package imageurl
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	t.Setenv("IMAGE_URL_SECRET", "test-secret")
+	now := time.Now()
+
+	signed, err := url.Parse(Sign("https://maestro.example.com/", "cache/abc.png", now.Add(time.Hour)))
+	if err != nil {
+		t.Fatalf("Sign() is not a URL: %v", err)
+	}
+	if signed.Host != "maestro.example.com" || signed.Path != "/images/cache/abc.png" {
+		t.Fatalf("Sign() = %s, want maestro.example.com/images/cache/abc.png", signed)
+	}
+
+	expires := signed.Query().Get("expires")
+	signature := signed.Query().Get("signature")
+
+	tests := []struct {
+		name      string
+		path      string
+		expires   string
+		signature string
+		now       time.Time
+		want      bool
+	}{
+		{name: "valid", path: "cache/abc.png", expires: expires, signature: signature, now: now, want: true},
+		{name: "equivalent path", path: "./cache//abc.png", expires: expires, signature: signature, now: now, want: true},
+		{name: "expired", path: "cache/abc.png", expires: expires, signature: signature, now: now.Add(2 * time.Hour)},
+		{name: "other image", path: "cache/abd.png", expires: expires, signature: signature, now: now},
+		{name: "extended expiry", path: "cache/abc.png", expires: "99999999999", signature: signature, now: now},
+		{name: "tampered signature", path: "cache/abc.png", expires: expires, signature: strings.Repeat("0", len(signature)), now: now},
+		{name: "no signature", path: "cache/abc.png", expires: expires, now: now},
+		{name: "malformed expiry", path: "cache/abc.png", expires: "soon", signature: signature, now: now},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Verify(tt.path, tt.expires, tt.signature, tt.now); got != tt.want {
+				t.Errorf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Setenv("IMAGE_URL_SECRET", "rotated-secret")
+	if Verify("cache/abc.png", expires, signature, now) {
+		t.Error("a link signed with another secret should not verify")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	t.Setenv("IMAGE_URL_SECRET", "test-secret")
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "cache"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "cache", "abc.png"), []byte("image"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	handler := http.StripPrefix("/images/", Handler(dir))
+
+	get := func(target string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+		return recorder
+	}
+	pathOf := func(signed string) string {
+		parsed, err := url.Parse(signed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed.RequestURI()
+	}
+
+	response := get(pathOf(Sign("http://localhost", "cache/abc.png", time.Now().Add(time.Minute))))
+	if response.Code != http.StatusOK || response.Body.String() != "image" {
+		t.Errorf("signed link: status %d, body %q", response.Code, response.Body.String())
+	}
+
+	for name, target := range map[string]string{
+		"unsigned":  "/images/cache/abc.png",
+		"expired":   pathOf(Sign("http://localhost", "cache/abc.png", time.Now().Add(-time.Minute))),
+		"directory": pathOf(Sign("http://localhost", "cache", time.Now().Add(time.Minute))),
+		"missing":   pathOf(Sign("http://localhost", "cache/missing.png", time.Now().Add(time.Minute))),
+		"root":      "/images/",
+	} {
+		if response := get(target); response.Code != http.StatusNotFound {
+			t.Errorf("%s: status %d, want 404", name, response.Code)
+		}
+	}
+}
+
+func TestTTL(t *testing.T) {
+	t.Setenv("IMAGE_URL_TTL", "")
+	if got := TTL(); got != defaultTTL {
+		t.Errorf("TTL() = %s, want %s", got, defaultTTL)
+	}
+
+	t.Setenv("IMAGE_URL_TTL", "600")
+	if got := TTL(); got != 10*time.Minute {
+		t.Errorf("TTL() = %s, want 10m", got)
+	}
+
+	t.Setenv("IMAGE_URL_TTL", "-5")
+	if got := TTL(); got != defaultTTL {
+		t.Errorf("TTL() = %s for an invalid value, want %s", got, defaultTTL)
+	}
+}
@@ -1,19 +1,87 @@
 package middleware
 
 import (
+	"content-maestro/internal/logger"
+	"content-maestro/internal/models"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
 	"net/http"
 	"os"
+	"slices"
 	"strings"
 )
 
-func AuthMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		apiToken := os.Getenv("API_TOKEN")
-		if apiToken == "" {
-			http.Error(w, "API token not configured", http.StatusInternalServerError)
-			return
-		}
+// TokenStore looks up the named tokens AuthMiddleware accepts besides
+// API_TOKEN.
+type TokenStore interface {
+	GetAPITokenByHash(hash string) (*models.APIToken, error)
+}
+
+type actorKey struct{}
+
+// actorSlot carries the actor out to LoggingMiddleware, which wraps
+// AuthMiddleware and so never sees the request it passes on.
+type actorSlot struct {
+	actor string
+}
+
+type actorSlotKey struct{}
+
+// Actor returns who made a request that passed AuthMiddleware, for the records
+// of what it changed. It is empty for requests that did not.
+func Actor(r *http.Request) string {
+	actor, _ := r.Context().Value(actorKey{}).(string)
+	return actor
+}
+
+func withActor(r *http.Request, actor string) *http.Request {
+	if slot, ok := r.Context().Value(actorSlotKey{}).(*actorSlot); ok {
+		slot.actor = actor
+	}
+	return r.WithContext(context.WithValue(r.Context(), actorKey{}, actor))
+}
+
+// HashToken returns the hash a named token is stored and looked up by.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
 
+// NewToken generates a random token for a named token, and its hash.
+func NewToken() (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("failed to generate token: %v", err)
+	}
+	token = "cm_" + hex.EncodeToString(b)
+	return token, HashToken(token), nil
+}
+
+// HasScope reports whether a token with scopes may make a request with method
+// to a route that requires scope. ScopeReadOnly allows reading any route but
+// the token and connector ones: connector configs hold the connectors' secrets.
+func HasScope(scopes []string, scope, method string) bool {
+	if slices.Contains(scopes, scope) {
+		return true
+	}
+	if scope == models.ScopeAdmin || scope == models.ScopeConnectors {
+		return false
+	}
+	readOnly := method == http.MethodGet || method == http.MethodHead
+	return readOnly && slices.Contains(scopes, models.ScopeReadOnly)
+}
+
+// AuthMiddleware lets through requests made with API_TOKEN, which may do
+// anything, and those made with a named token that has the scope the route
+// requires.
+func AuthMiddleware(tokens TokenStore, scope string, next http.Handler) http.Handler {
+	log := logger.NewLogger()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
 			http.Error(w, "Authorization header is required", http.StatusUnauthorized)
@@ -26,11 +94,33 @@ func AuthMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		if token != apiToken {
+		apiToken := os.Getenv("API_TOKEN")
+		if apiToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(apiToken)) == 1 {
+			next.ServeHTTP(w, withActor(r, models.APITokenActor))
+			return
+		}
+
+		named, err := tokens.GetAPITokenByHash(HashToken(token))
+		if err != nil {
+			// The caller is not authenticated yet, so the store error stays in the log.
+			log.Errorf("Failed to look up API token: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		if named == nil {
+			if apiToken == "" {
+				http.Error(w, "API token not configured", http.StatusInternalServerError)
+				return
+			}
 			http.Error(w, "Invalid token", http.StatusUnauthorized)
 			return
 		}
 
-		next.ServeHTTP(w, r)
+		if !HasScope(named.Scopes, scope, r.Method) {
+			http.Error(w, fmt.Sprintf("Token '%s' does not have the %s scope", named.Name, scope), http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, withActor(r, named.Name))
 	})
 }
@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"content-maestro/internal/models"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type tokenStore map[string]*models.APIToken
+
+func (s tokenStore) GetAPITokenByHash(hash string) (*models.APIToken, error) {
+	return s[hash], nil
+}
+
+type failingTokenStore struct{}
+
+func (failingTokenStore) GetAPITokenByHash(string) (*models.APIToken, error) {
+	return nil, errors.New("failed to get api token: database is locked")
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	t.Setenv("API_TOKEN", "admin-secret")
+
+	tokens := tokenStore{
+		HashToken("dashboard-secret"):  {Name: "dashboard", Scopes: []string{models.ScopeReadOnly}},
+		HashToken("ops-secret"):        {Name: "ops-bot", Scopes: []string{models.ScopeCrons}},
+		HashToken("connectors-secret"): {Name: "integrations", Scopes: []string{models.ScopeConnectors}},
+	}
+
+	tests := []struct {
+		name       string
+		token      string
+		method     string
+		scope      string
+		wantStatus int
+		wantActor  string
+	}{
+		{name: "API_TOKEN", token: "admin-secret", method: http.MethodPut, scope: models.ScopeSettings, wantStatus: http.StatusOK, wantActor: models.APITokenActor},
+		{name: "API_TOKEN on admin route", token: "admin-secret", method: http.MethodPost, scope: models.ScopeAdmin, wantStatus: http.StatusOK, wantActor: models.APITokenActor},
+		{name: "scope matches", token: "ops-secret", method: http.MethodPut, scope: models.ScopeCrons, wantStatus: http.StatusOK, wantActor: "ops-bot"},
+		{name: "own scope reads", token: "ops-secret", method: http.MethodGet, scope: models.ScopeCrons, wantStatus: http.StatusOK, wantActor: "ops-bot"},
+		{name: "other scope", token: "ops-secret", method: http.MethodGet, scope: models.ScopeConnectors, wantStatus: http.StatusForbidden},
+		{name: "read-only reads", token: "dashboard-secret", method: http.MethodGet, scope: models.ScopeSettings, wantStatus: http.StatusOK, wantActor: "dashboard"},
+		{name: "read-only writes", token: "dashboard-secret", method: http.MethodPut, scope: models.ScopeSettings, wantStatus: http.StatusForbidden},
+		{name: "read-only on connector route", token: "dashboard-secret", method: http.MethodGet, scope: models.ScopeConnectors, wantStatus: http.StatusForbidden},
+		{name: "connectors reads", token: "connectors-secret", method: http.MethodGet, scope: models.ScopeConnectors, wantStatus: http.StatusOK, wantActor: "integrations"},
+		{name: "read-only on admin route", token: "dashboard-secret", method: http.MethodGet, scope: models.ScopeAdmin, wantStatus: http.StatusForbidden},
+		{name: "unknown token", token: "guess", method: http.MethodGet, scope: models.ScopeCrons, wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var actor string
+			handler := AuthMiddleware(tokens, tt.scope, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				actor = Actor(r)
+			}))
+
+			req := httptest.NewRequest(tt.method, "/api/crons", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if actor != tt.wantActor {
+				t.Errorf("expected actor %q, got %q", tt.wantActor, actor)
+			}
+		})
+	}
+}
+
+func TestAuthMiddlewareWithoutAPIToken(t *testing.T) {
+	t.Setenv("API_TOKEN", "")
+
+	tokens := tokenStore{HashToken("ops-secret"): {Name: "ops-bot", Scopes: []string{models.ScopeCrons}}}
+	handler := AuthMiddleware(tokens, models.ScopeCrons, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for token, wantStatus := range map[string]int{
+		"ops-secret": http.StatusOK,
+		"":           http.StatusInternalServerError,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/crons", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != wantStatus {
+			t.Errorf("token %q: expected status %d, got %d", token, wantStatus, rec.Code)
+		}
+	}
+}
+
+func TestAuthMiddlewareHidesStoreErrors(t *testing.T) {
+	t.Setenv("API_TOKEN", "admin-secret")
+
+	handler := AuthMiddleware(failingTokenStore{}, models.ScopeCrons, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/crons", nil)
+	req.Header.Set("Authorization", "Bearer guess")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, rec.Code)
+	}
+	if body := rec.Body.String(); strings.Contains(body, "database") {
+		t.Errorf("store error leaked to the caller: %s", body)
+	}
+}
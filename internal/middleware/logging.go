@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"content-maestro/internal/logger"
+	"context"
 	"net/http"
 	"time"
 )
@@ -13,12 +14,14 @@ func LoggingMiddleware(next http.Handler) http.Handler {
 		start := time.Now()
 
 		lrw := newLoggingResponseWriter(w)
-		next.ServeHTTP(lrw, r)
+		slot := &actorSlot{}
+		next.ServeHTTP(lrw, r.WithContext(context.WithValue(r.Context(), actorSlotKey{}, slot)))
 
 		log.Infow("HTTP Request",
 			"method", r.Method,
 			"path", r.URL.Path,
 			"status", lrw.statusCode,
+			"actor", slot.actor,
 			"duration", time.Since(start),
 			"ip", r.RemoteAddr,
 			"user_agent", r.UserAgent(),
@@ -2,6 +2,10 @@ package models
 
 import "time"
 
+// DefaultRetryMaxAttempts is how many automatic retries a connector gets when
+// its config does not say otherwise.
+const DefaultRetryMaxAttempts = 3
+
 type APIConfigModel struct {
 	ID               int       `json:"id"`
 	Name             string    `json:"name"`
@@ -18,7 +22,17 @@ type APIConfigModel struct {
 	TextLanguage     string    `json:"text_language"`
 	SocialifyImage   bool      `json:"socialify_image"`
 	DefaultJSONBody  string    `json:"default_json_body"`
+	Headers          string    `json:"headers"`
+	SuccessCodes     []int     `json:"success_codes"`
+	SuccessField     string    `json:"success_field"`
+	SuccessValue     string    `json:"success_value"`
+	PostIDField      string    `json:"post_id_field"`
+	PostURLField     string    `json:"post_url_field"`
+	RetryMaxAttempts int       `json:"retry_max_attempts"`
+	BodyTemplate     string    `json:"body_template"`
 	UpdatedAt        time.Time `json:"updated_at"`
+	// ImageSettings overrides the global image settings for this connector.
+	ImageSettings *ImageOverrides `json:"image_settings,omitempty"`
 }
 
 type CreateAPIConfigRequest struct {
@@ -36,20 +50,51 @@ type CreateAPIConfigRequest struct {
 	TextLanguage    string `json:"text_language"`
 	SocialifyImage  bool   `json:"socialify_image"`
 	DefaultJSONBody string `json:"default_json_body"`
+	// Headers is a JSON object of extra request headers, like DefaultJSONBody.
+	Headers string `json:"headers"`
+	// SuccessCodes, when not empty, are the accepted statuses instead of
+	// SuccessCode.
+	SuccessCodes []int `json:"success_codes,omitempty"`
+	// SuccessField is a JSON path into the response body that must equal
+	// SuccessValue, or be truthy when SuccessValue is empty.
+	SuccessField string `json:"success_field"`
+	SuccessValue string `json:"success_value"`
+	// PostIDField and PostURLField are JSON paths to the created post's ID and
+	// permalink in a successful response.
+	PostIDField  string `json:"post_id_field"`
+	PostURLField string `json:"post_url_field"`
+	// RetryMaxAttempts is optional so that omitting it means the default budget
+	// rather than disabling automatic retries.
+	RetryMaxAttempts *int `json:"retry_max_attempts,omitempty"`
+	// BodyTemplate replaces the default {"text", "url"} payload with a Go
+	// text/template rendering a JSON object.
+	BodyTemplate string `json:"body_template"`
+	// ImageSettings overrides the global image settings for this connector.
+	ImageSettings *ImageOverrides `json:"image_settings,omitempty"`
 }
 
 type UpdateAPIConfigRequest struct {
-	URL             *string `json:"url,omitempty"`
-	Method          *string `json:"method,omitempty"`
-	AuthType        *string `json:"auth_type,omitempty"`
-	TokenEnvVar     *string `json:"token_env_var,omitempty"`
-	TokenHeader     *string `json:"token_header,omitempty"`
-	ContentType     *string `json:"content_type,omitempty"`
-	Timeout         *int    `json:"timeout,omitempty"`
-	SuccessCode     *int    `json:"success_code,omitempty"`
-	Enabled         *bool   `json:"enabled,omitempty"`
-	ResponseType    *string `json:"response_type,omitempty"`
-	TextLanguage    *string `json:"text_language,omitempty"`
-	SocialifyImage  *bool   `json:"socialify_image,omitempty"`
-	DefaultJSONBody *string `json:"default_json_body,omitempty"`
+	URL              *string `json:"url,omitempty"`
+	Method           *string `json:"method,omitempty"`
+	AuthType         *string `json:"auth_type,omitempty"`
+	TokenEnvVar      *string `json:"token_env_var,omitempty"`
+	TokenHeader      *string `json:"token_header,omitempty"`
+	ContentType      *string `json:"content_type,omitempty"`
+	Timeout          *int    `json:"timeout,omitempty"`
+	SuccessCode      *int    `json:"success_code,omitempty"`
+	Enabled          *bool   `json:"enabled,omitempty"`
+	ResponseType     *string `json:"response_type,omitempty"`
+	TextLanguage     *string `json:"text_language,omitempty"`
+	SocialifyImage   *bool   `json:"socialify_image,omitempty"`
+	DefaultJSONBody  *string `json:"default_json_body,omitempty"`
+	Headers          *string `json:"headers,omitempty"`
+	SuccessCodes     *[]int  `json:"success_codes,omitempty"`
+	SuccessField     *string `json:"success_field,omitempty"`
+	SuccessValue     *string `json:"success_value,omitempty"`
+	PostIDField      *string `json:"post_id_field,omitempty"`
+	PostURLField     *string `json:"post_url_field,omitempty"`
+	RetryMaxAttempts *int    `json:"retry_max_attempts,omitempty"`
+	BodyTemplate     *string `json:"body_template,omitempty"`
+	// ImageSettings replaces the connector's whole override; {} removes it.
+	ImageSettings *ImageOverrides `json:"image_settings,omitempty"`
 }
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// Scopes of named API tokens. A route requires one of them: any request with
+// it, or a GET request with ScopeReadOnly unless it is ScopeConnectors or
+// ScopeAdmin.
+const (
+	ScopeReadOnly   = "read-only"
+	ScopeCrons      = "crons"
+	ScopeSettings   = "settings"
+	ScopeConnectors = "connectors"
+	ScopeRetry      = "retry"
+	// ScopeAdmin guards the token endpoints. It cannot be granted: only
+	// API_TOKEN has it.
+	ScopeAdmin = "admin"
+)
+
+// APITokenActor is who requests made with API_TOKEN are recorded as. Requests
+// made with a named token are recorded under the token's name.
+const APITokenActor = "api_token"
+
+// TokenScopes are the scopes a named token can be granted.
+var TokenScopes = []string{ScopeReadOnly, ScopeCrons, ScopeSettings, ScopeConnectors, ScopeRetry}
+
+// APIToken is a named token for the API. The token itself is only shown when
+// it is created; the store keeps its hash.
+type APIToken struct {
+	Name      string    `json:"name"`
+	Scopes    []string  `json:"scopes"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CreateAPITokenRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+// CreateAPITokenResponse is the created token, with the token to use as the
+// Bearer token.
+type CreateAPITokenResponse struct {
+	APIToken
+	Token string `json:"token"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditEntry records one configuration change made through the API.
+type AuditEntry struct {
+	ID int64 `json:"id"`
+	// Actor is who made the change, as identified by AuthMiddleware.
+	Actor    string `json:"actor"`
+	Method   string `json:"method"`
+	Endpoint string `json:"endpoint"`
+	// Before and After are what changed, as the API returns it, before and after
+	// the change. Before is null for something created, After for something
+	// deleted.
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type PaginatedAuditLogResponse struct {
+	Data       []AuditEntry       `json:"data"`
+	Pagination PaginationMetadata `json:"pagination"`
+}
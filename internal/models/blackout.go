@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// Kinds of blackout window.
+const (
+	// BlackoutKindRange blocks a span of dates or times, e.g. an incident or a
+	// one-off holiday.
+	BlackoutKindRange = "range"
+	// BlackoutKindWeekly blocks whole days of the week.
+	BlackoutKindWeekly = "weekly"
+	// BlackoutKindYearly blocks the same days every year, e.g. 12-24 to 12-26.
+	BlackoutKindYearly = "yearly"
+)
+
+// Layouts of the start and end of a blackout window. A range takes either
+// dates, which block whole days, or RFC 3339 times; a yearly window takes
+// month and day.
+const (
+	BlackoutDateLayout     = "2006-01-02"
+	BlackoutMonthDayLayout = "01-02"
+)
+
+// BlackoutWindow is a period in which message schedules do not publish. Runs
+// that fall into one are recorded in the cron history instead of consuming an
+// item.
+type BlackoutWindow struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	// Start and End are inclusive, except for RFC 3339 times where End is the
+	// first moment publishing resumes.
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+	// Weekdays are "mon" to "sun", for weekly windows.
+	Weekdays []string `json:"weekdays,omitempty"`
+	// Timezone is the IANA zone dates and weekdays are read in.
+	Timezone  string    `json:"timezone"`
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// Active reports whether the window blocks publishing right now. It is not
+	// stored.
+	Active bool `json:"active"`
+}
+
+// BlackoutWindowRequest creates a blackout window or replaces one.
+type BlackoutWindowRequest struct {
+	Name     string   `json:"name"`
+	Kind     string   `json:"kind"`
+	Start    string   `json:"start"`
+	End      string   `json:"end"`
+	Weekdays []string `json:"weekdays"`
+	Timezone string   `json:"timezone"`
+	// Enabled defaults to true.
+	Enabled *bool `json:"enabled"`
+}
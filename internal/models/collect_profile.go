@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// CollectProfile is a named slice of GitHub trending to collect, such as
+// "go-weekly" or "rust-daily". Each profile is run by the cron of the same
+// name, which holds its schedule; Cron carries that setting in responses.
+type CollectProfile struct {
+	Name               string `json:"name"`
+	MaxRepos           int    `json:"max_repos"`
+	Resource           string `json:"resource"`
+	Since              string `json:"since"`
+	SpokenLanguageCode string `json:"spoken_language_code"`
+	Period             string `json:"period"`
+	Language           string `json:"language"`
+	// Prompt overrides the prompt settings for this profile's runs; fields left
+	// out follow the global settings.
+	Prompt *UpdatePromptSettingsRequest `json:"prompt,omitempty"`
+	// PromptVersions are the prompt versions the profile's runs choose from;
+	// the chosen one is applied after Prompt. Empty uses no version.
+	PromptVersions []PromptVersionWeight `json:"prompt_versions,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at"`
+	Cron           *CronSetting          `json:"cron,omitempty"`
+}
+
+// CreateCollectProfileRequest adds a profile together with its cron.
+type CreateCollectProfileRequest struct {
+	Name     string `json:"name"`
+	Schedule string `json:"schedule"`
+	IsActive bool   `json:"is_active"`
+	Timezone string `json:"timezone"`
+	// JitterSeconds is optional, see CronSetting.
+	JitterSeconds      int                          `json:"jitter_seconds"`
+	MaxRepos           int                          `json:"max_repos"`
+	Resource           string                       `json:"resource"`
+	Since              string                       `json:"since"`
+	SpokenLanguageCode string                       `json:"spoken_language_code"`
+	Period             string                       `json:"period"`
+	Language           string                       `json:"language"`
+	Prompt             *UpdatePromptSettingsRequest `json:"prompt,omitempty"`
+	PromptVersions     []PromptVersionWeight        `json:"prompt_versions,omitempty"`
+}
+
+// UpdateCollectProfileRequest changes the collect fields that are set. Prompt,
+// when set, replaces the profile's prompt override; {} removes it.
+// PromptVersions, when set, replaces the assigned versions; [] removes them. The
+// schedule is changed through the profile's cron.
+type UpdateCollectProfileRequest struct {
+	MaxRepos           *int                         `json:"max_repos,omitempty"`
+	Resource           *string                      `json:"resource,omitempty"`
+	Since              *string                      `json:"since,omitempty"`
+	SpokenLanguageCode *string                      `json:"spoken_language_code,omitempty"`
+	Period             *string                      `json:"period,omitempty"`
+	Language           *string                      `json:"language,omitempty"`
+	Prompt             *UpdatePromptSettingsRequest `json:"prompt,omitempty"`
+	PromptVersions     *[]PromptVersionWeight       `json:"prompt_versions,omitempty"`
+}
@@ -7,6 +7,55 @@ type CronSetting struct {
 	Schedule  string    `json:"schedule"`
 	IsActive  bool      `json:"is_active"`
 	UpdatedAt time.Time `json:"updated_at"`
+	// Job is what the cron runs: JobCollect or JobMessage.
+	Job string `json:"job"`
+	// APIs limits a message schedule to these connectors. Empty means every
+	// enabled connector.
+	APIs []string `json:"apis"`
+	// Timezone is the IANA zone the schedule is read in.
+	Timezone string `json:"timezone"`
+	// PausedUntil holds an active cron off its schedule until that time, when it
+	// resumes by itself. PauseReason says why.
+	PausedUntil *time.Time `json:"paused_until,omitempty"`
+	PauseReason string     `json:"pause_reason,omitempty"`
+	// JitterSeconds moves each run by a random offset of up to this many seconds
+	// either side of its scheduled time. Zero runs exactly on schedule.
+	JitterSeconds int `json:"jitter_seconds"`
+	// NextRun is when the live scheduler fires next, in Timezone, and NextRuns
+	// the upcoming fire times starting with it. Neither is stored; both are
+	// absent while the cron is inactive.
+	NextRun  *time.Time  `json:"next_run,omitempty"`
+	NextRuns []time.Time `json:"next_runs,omitempty"`
+	// LastRun is the latest run in the cron history, skipped runs aside.
+	LastRun *CronRunSummary `json:"last_run,omitempty"`
+	// Running reports whether a run is in progress.
+	Running bool `json:"running"`
+}
+
+// CronRunSummary is one run from the cron history, without its output.
+type CronRunSummary struct {
+	RunID     string    `json:"run_id,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Status    int       `json:"status"`
+	Manual    bool      `json:"manual,omitempty"`
+}
+
+// DefaultTimezone is the zone of crons that never had one set.
+const DefaultTimezone = "UTC"
+
+// CreateCronRequest adds a named message schedule bound to a set of connectors.
+type CreateCronRequest struct {
+	Name     string   `json:"name"`
+	Schedule string   `json:"schedule"`
+	IsActive bool     `json:"is_active"`
+	APIs     []string `json:"apis"`
+	Timezone string   `json:"timezone"`
+	// JitterSeconds is optional, see CronSetting.
+	JitterSeconds int `json:"jitter_seconds"`
+}
+
+type UpdateCronAPIsRequest struct {
+	APIs []string `json:"apis"`
 }
 
 type CronResponse struct {
@@ -16,6 +65,16 @@ type CronResponse struct {
 
 type UpdateScheduleRequest struct {
 	Schedule string `json:"schedule"`
+	// Timezone, when set, changes the zone the schedule is read in.
+	Timezone *string `json:"timezone,omitempty"`
+	// JitterSeconds, when set, changes the jitter window.
+	JitterSeconds *int `json:"jitter_seconds,omitempty"`
+}
+
+// PauseCronRequest holds a cron off its schedule until the given time.
+type PauseCronRequest struct {
+	Until  time.Time `json:"until"`
+	Reason string    `json:"reason"`
 }
 
 type UpdateStatusRequest struct {
@@ -27,4 +86,6 @@ type UpdateStatusRequest struct {
 type RetryMessageRequest struct {
 	APIs []string `json:"apis"`
 	URL  string   `json:"url"`
+	// DryRun returns the requests the retry would send instead of sending them.
+	DryRun bool `json:"dry_run"`
 }
@@ -2,12 +2,37 @@ package models
 
 import "time"
 
+// Statuses of a cron history entry.
+const (
+	CronStatusFailure = 0
+	CronStatusSuccess = 1
+	CronStatusPartial = 2
+	// CronStatusSkipped is a run that did not start because another run of the
+	// same job was still in progress.
+	CronStatusSkipped = 3
+	// CronStatusBlackout is a scheduled message run that fell into a blackout
+	// window and published nothing.
+	CronStatusBlackout = 4
+)
+
 type CronHistory struct {
 	Name      string             `json:"name"`
 	Timestamp time.Time          `json:"timestamp"`
 	Success   int                `json:"status"`
 	Output    string             `json:"output,omitempty"`
 	Details   *MessageRunDetails `json:"details,omitempty"`
+	// RunID identifies the run; Manual marks runs started through the API
+	// rather than by the schedule.
+	RunID  string `json:"run_id,omitempty"`
+	Manual bool   `json:"manual,omitempty"`
+	// ScheduledAt is the time a jittered run was due by its schedule; FiredAt is
+	// when the run actually started.
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty"`
+	FiredAt     *time.Time `json:"fired_at,omitempty"`
+	// Profile is the collect profile a collect run harvested, and
+	// PromptVersion the prompt version it was given.
+	Profile       string `json:"profile,omitempty"`
+	PromptVersion string `json:"prompt_version,omitempty"`
 }
 
 // MessageRunDetails records which item a message run published and where it
@@ -18,6 +43,17 @@ type MessageRunDetails struct {
 	Sent   []string `json:"sent,omitempty"`
 	Failed []string `json:"failed,omitempty"`
 	Manual bool     `json:"manual,omitempty"`
+	// Automatic marks entries written by the background retry queue.
+	Automatic bool `json:"automatic,omitempty"`
+	// Posts links to what the run created on each connector that reported it.
+	Posts []PublishedPost `json:"posts,omitempty"`
+}
+
+// PublishedPost is the post a connector created for an item.
+type PublishedPost struct {
+	APIName string `json:"api_name"`
+	PostID  string `json:"post_id,omitempty"`
+	PostURL string `json:"post_url,omitempty"`
 }
 
 type PaginationMetadata struct {
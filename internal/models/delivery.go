@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+const (
+	DeliveryStatusDelivered = "delivered"
+	DeliveryStatusFailed    = "failed"
+	// DeliveryStatusAbandoned marks a failed delivery the retry queue gave up on.
+	DeliveryStatusAbandoned = "abandoned"
+)
+
+// Delivery is the ledger entry for one repository and one connector. The run
+// history only says what a single run did; the ledger answers "has this
+// connector ever received this repository" without parsing history rows.
+type Delivery struct {
+	URL            string     `json:"url"`
+	APIName        string     `json:"api_name"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	LastError      string     `json:"last_error,omitempty"`
+	FirstAttemptAt time.Time  `json:"first_attempt_at"`
+	LastAttemptAt  time.Time  `json:"last_attempt_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	// NextAttemptAt is set while a failed delivery waits in the retry queue.
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	// ManualAttempts counts the attempts made by manual retries. They are part
+	// of Attempts but do not spend the automatic retry budget.
+	ManualAttempts int `json:"manual_attempts"`
+	// PostID and PostURL identify the post the connector created, when its
+	// config says where to find them in the response.
+	PostID  string `json:"post_id,omitempty"`
+	PostURL string `json:"post_url,omitempty"`
+}
+
+// DeliveryAttempt is the outcome of one try at sending a repository to a
+// connector.
+type DeliveryAttempt struct {
+	URL     string
+	APIName string
+	Success bool
+	Error   string
+	PostID  string
+	PostURL string
+	// Manual marks an attempt made by a manual retry.
+	Manual bool
+}
+
+type PaginatedDeliveriesResponse struct {
+	Data       []Delivery         `json:"data"`
+	Pagination PaginationMetadata `json:"pagination"`
+}
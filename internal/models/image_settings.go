@@ -0,0 +1,124 @@
+package models
+
+import "time"
+
+// Formats an image can be written in.
+const (
+	ImageFormatPNG  = "png"
+	ImageFormatJPEG = "jpeg"
+)
+
+// Image providers. Socialify is the public service unless
+// ImageSettings.SocialifyURL points at a self-hosted instance.
+const (
+	ImageProviderSocialify = "socialify"
+	ImageProviderLocal     = "local"
+	ImageProviderOpenGraph = "github_opengraph"
+	ImageProviderStatic    = "static"
+)
+
+// Card fields socialify can show. ImageSettings.Fields lists the ones a card
+// shows; the rest are left off.
+var ImageFields = []string{"owner", "name", "description", "language", "stargazers", "forks", "issues", "pulls"}
+
+// ImageSettings shape the repository card attached to posts. One set applies
+// to every connector; a connector can override any of it through
+// APIConfigModel.ImageSettings.
+type ImageSettings struct {
+	// Provider generates the image; see the ImageProvider constants.
+	Provider string `json:"provider"`
+	// SocialifyURL is the base URL of a self-hosted socialify, or empty for
+	// the public one.
+	SocialifyURL string `json:"socialify_url"`
+	// StaticFile is the image the static provider sends, relative to the
+	// working directory.
+	StaticFile string `json:"static_file"`
+	Theme      string `json:"theme"`
+	Font       string `json:"font"`
+	// Patterns are the background patterns to pick from; each image gets one
+	// of them at random.
+	Patterns []string `json:"patterns"`
+	// Logo is the URL of an image shown on the card instead of the owner's
+	// avatar, or empty for none.
+	Logo   string   `json:"logo"`
+	Fields []string `json:"fields"`
+	Format string   `json:"format"`
+	// Width is the image width in pixels. Cards are twice as wide as they are
+	// high.
+	Width     int       `json:"width"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DefaultImageSettings are the settings cards were generated with before they
+// could be changed.
+func DefaultImageSettings() ImageSettings {
+	return ImageSettings{
+		Provider: ImageProviderSocialify,
+		Theme:    "Light",
+		Font:     "Jost",
+		Patterns: []string{"Diagonal Stripes", "Charlie Brown", "Brick Wall", "Circuit Board", "Formal Invitation"},
+		Fields:   []string{"owner", "name", "language", "stargazers", "forks", "issues", "pulls"},
+		Format:   ImageFormatPNG,
+		Width:    1280,
+	}
+}
+
+// ImageOverrides changes some image settings and leaves the rest as they are.
+// It is both the body of PUT /api/image-settings and a connector's override of
+// the global settings.
+type ImageOverrides struct {
+	Provider     *string   `json:"provider,omitempty"`
+	SocialifyURL *string   `json:"socialify_url,omitempty"`
+	StaticFile   *string   `json:"static_file,omitempty"`
+	Theme        *string   `json:"theme,omitempty"`
+	Font         *string   `json:"font,omitempty"`
+	Patterns     *[]string `json:"patterns,omitempty"`
+	Logo         *string   `json:"logo,omitempty"`
+	Fields       *[]string `json:"fields,omitempty"`
+	Format       *string   `json:"format,omitempty"`
+	Width        *int      `json:"width,omitempty"`
+}
+
+// IsEmpty reports whether the overrides change nothing.
+func (o *ImageOverrides) IsEmpty() bool {
+	return o == nil || *o == ImageOverrides{}
+}
+
+// Apply returns the settings with the overrides applied. A nil receiver
+// changes nothing.
+func (o *ImageOverrides) Apply(settings ImageSettings) ImageSettings {
+	if o == nil {
+		return settings
+	}
+	if o.Provider != nil {
+		settings.Provider = *o.Provider
+	}
+	if o.SocialifyURL != nil {
+		settings.SocialifyURL = *o.SocialifyURL
+	}
+	if o.StaticFile != nil {
+		settings.StaticFile = *o.StaticFile
+	}
+	if o.Theme != nil {
+		settings.Theme = *o.Theme
+	}
+	if o.Font != nil {
+		settings.Font = *o.Font
+	}
+	if o.Patterns != nil {
+		settings.Patterns = *o.Patterns
+	}
+	if o.Logo != nil {
+		settings.Logo = *o.Logo
+	}
+	if o.Fields != nil {
+		settings.Fields = *o.Fields
+	}
+	if o.Format != nil {
+		settings.Format = *o.Format
+	}
+	if o.Width != nil {
+		settings.Width = *o.Width
+	}
+	return settings
+}
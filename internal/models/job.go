@@ -1,7 +1,43 @@
 package models
 
-import "github.com/go-co-op/gocron"
+import (
+	"time"
 
-type JobFunc func(*gocron.Scheduler)
+	"github.com/go-co-op/gocron"
+)
+
+// Jobs a cron setting can run.
+const (
+	JobCollect = "collect"
+	JobMessage = "message"
+)
+
+// JobRun identifies one execution of a job. Scheduled runs start with a zero
+// JobRun and get an ID and fire time when they begin.
+type JobRun struct {
+	ID     string `json:"run_id"`
+	Manual bool   `json:"manual"`
+	// ScheduledAt is set on jittered runs: the time the schedule named, before
+	// the jitter moved it.
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty"`
+	FiredAt     *time.Time `json:"fired_at,omitempty"`
+}
+
+type JobFunc func(*gocron.Scheduler, JobRun)
 
 type JobRegistry map[string]JobFunc
+
+// RunJobRequest is the optional body of a manual run.
+type RunJobRequest struct {
+	// DryRun returns what a message schedule's run would send instead of
+	// starting it.
+	DryRun bool `json:"dry_run"`
+}
+
+// RunJobResponse is returned when a job is started by hand.
+type RunJobResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Name    string `json:"name"`
+	RunID   string `json:"run_id"`
+}
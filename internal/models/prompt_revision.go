@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// PromptRevision is one change of the prompt settings. Revisions are only ever
+// added: a rollback is a new revision that restores an older one's settings.
+type PromptRevision struct {
+	Rev       int64     `json:"rev"`
+	Actor     string    `json:"actor"`
+	CreatedAt time.Time `json:"created_at"`
+	// Settings are the prompt settings as the change left them.
+	Settings PromptSettings `json:"settings"`
+	// Changes lists the fields the revision changed. The first revision, which
+	// records the settings as they were before any tracked change, has none.
+	Changes []PromptChange `json:"changes"`
+	// RolledBackFrom is the revision a rollback restored.
+	RolledBackFrom *int64 `json:"rolled_back_from,omitempty"`
+}
+
+// PromptChange is one field of the prompt settings a revision changed.
+type PromptChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+type PaginatedPromptRevisionsResponse struct {
+	Data       []PromptRevision   `json:"data"`
+	Pagination PaginationMetadata `json:"pagination"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// PromptVersion is a named system prompt that collect profiles can use instead
+// of the global one. A version is never edited - a changed prompt is a new
+// version - so the history entries that name it keep meaning what they did.
+type PromptVersion struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+	// Model and Temperature, when set, replace those of the prompt settings the
+	// version is applied to.
+	Model       string    `json:"model,omitempty"`
+	Temperature *float64  `json:"temperature,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// CreatePromptVersionRequest adds a prompt version.
+type CreatePromptVersionRequest struct {
+	Name        string   `json:"name"`
+	Content     string   `json:"content"`
+	Model       string   `json:"model"`
+	Temperature *float64 `json:"temperature"`
+}
+
+// PromptVersionWeight assigns a prompt version to a collect profile. A profile
+// with several picks one for each run, in proportion to the weights.
+type PromptVersionWeight struct {
+	Name string `json:"name"`
+	// Weight is the version's share of the runs; zero counts as one.
+	Weight int `json:"weight,omitempty"`
+}
@@ -45,6 +45,8 @@ func NotifyCronResult(cronName string, status int, logMessage string) {
 		statusLabel = "Failed"
 	case 2:
 		statusLabel = "Partial"
+	case 3:
+		statusLabel = "Skipped"
 	default:
 		statusLabel = fmt.Sprintf("Unknown(%d)", status)
 	}
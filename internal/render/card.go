@@ -0,0 +1,270 @@
+package render
+
+import (
+	"content-maestro/internal/logger"
+	"content-maestro/internal/models"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+var log = logger.NewLogger()
+
+// Cards have the size and aspect of the socialify images they stand in for.
+const (
+	CardWidth  = 1280
+	CardHeight = 640
+
+	cardMargin          = 96
+	maxDescriptionLines = 3
+)
+
+var (
+	cardBackground = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	cardText       = color.RGBA{0x1f, 0x23, 0x28, 0xff}
+	cardMuted      = color.RGBA{0x59, 0x63, 0x6e, 0xff}
+	cardRule       = color.RGBA{0xd0, 0xd7, 0xde, 0xff}
+	defaultAccent  = color.RGBA{0x6e, 0x77, 0x81, 0xff}
+)
+
+// languageColors follows the colours GitHub shows next to a repository's main
+// language. Languages not listed here get defaultAccent.
+var languageColors = map[string]color.RGBA{
+	"C":          {0x55, 0x55, 0x55, 0xff},
+	"C#":         {0x17, 0x86, 0x00, 0xff},
+	"C++":        {0xf3, 0x4b, 0x7d, 0xff},
+	"Dart":       {0x00, 0xb4, 0xab, 0xff},
+	"Go":         {0x00, 0xad, 0xd8, 0xff},
+	"Java":       {0xb0, 0x72, 0x19, 0xff},
+	"JavaScript": {0xf1, 0xe0, 0x5a, 0xff},
+	"Kotlin":     {0xa9, 0x7b, 0xff, 0xff},
+	"PHP":        {0x4f, 0x5d, 0x95, 0xff},
+	"Python":     {0x35, 0x72, 0xa5, 0xff},
+	"Ruby":       {0x70, 0x15, 0x16, 0xff},
+	"Rust":       {0xde, 0xa5, 0x84, 0xff},
+	"Shell":      {0x89, 0xe0, 0x51, 0xff},
+	"Swift":      {0xf0, 0x51, 0x38, 0xff},
+	"TypeScript": {0x31, 0x78, 0xc6, 0xff},
+	"Zig":        {0xec, 0x91, 0x5c, 0xff},
+}
+
+// RepoCard is what a rendered card shows. Only Owner and Name are required;
+// empty fields are left off the card.
+type RepoCard struct {
+	Owner       string
+	Name        string
+	Description string
+	Language    string
+	Stars       int
+	Forks       int
+}
+
+type cardFaces struct {
+	owner, name, description, stats font.Face
+}
+
+// faces are parsed once: the Go fonts are compiled into the binary, so the
+// renderer works without network access or fonts installed on the host.
+var faces = func() cardFaces {
+	regular, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		panic(fmt.Sprintf("failed to parse Go Regular: %v", err))
+	}
+	bold, err := opentype.Parse(gobold.TTF)
+	if err != nil {
+		panic(fmt.Sprintf("failed to parse Go Bold: %v", err))
+	}
+
+	face := func(f *opentype.Font, size float64) font.Face {
+		face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+		if err != nil {
+			panic(fmt.Sprintf("failed to create font face: %v", err))
+		}
+		return face
+	}
+
+	return cardFaces{
+		owner:       face(regular, 44),
+		name:        face(bold, 88),
+		description: face(regular, 36),
+		stats:       face(regular, 34),
+	}
+}()
+
+// DrawCard renders a repository card.
+func DrawCard(card RepoCard) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, CardWidth, CardHeight))
+	draw.Draw(img, img.Bounds(), image.NewUniform(cardBackground), image.Point{}, draw.Src)
+
+	accent := defaultAccent
+	if c, ok := languageColors[card.Language]; ok {
+		accent = c
+	}
+	fillRect(img, image.Rect(0, 0, CardWidth, 16), accent)
+
+	width := CardWidth - 2*cardMargin
+	y := 180
+	if card.Owner != "" {
+		drawText(img, faces.owner, cardMuted, cardMargin, y, truncate(faces.owner, card.Owner+" /", width))
+		y += 100
+	}
+	drawText(img, faces.name, cardText, cardMargin, y, truncate(faces.name, card.Name, width))
+	y += 70
+
+	if card.Description != "" {
+		for _, line := range wrap(faces.description, card.Description, width, maxDescriptionLines) {
+			drawText(img, faces.description, cardMuted, cardMargin, y, line)
+			y += 48
+		}
+	}
+
+	// A card drawn from the repository path alone has no stats to show, and
+	// "0 stars" would misreport them.
+	if card.Language == "" && card.Stars == 0 && card.Forks == 0 {
+		return img
+	}
+
+	fillRect(img, image.Rect(cardMargin, CardHeight-150, CardWidth-cardMargin, CardHeight-148), cardRule)
+
+	x := cardMargin
+	baseline := CardHeight - 78
+	if card.Language != "" {
+		fillCircle(img, x+12, baseline-12, 12, accent)
+		x += 36
+		x = drawText(img, faces.stats, cardText, x, baseline, card.Language) + 56
+	}
+	x = drawText(img, faces.stats, cardText, x, baseline, compactCount(card.Stars)+" stars") + 56
+	drawText(img, faces.stats, cardText, x, baseline, compactCount(card.Forks)+" forks")
+
+	return img
+}
+
+// WriteCard renders a card into a PNG file at outputPath.
+func WriteCard(card RepoCard, outputPath string) error {
+	return Save(DrawCard(card), outputPath, models.ImageFormatPNG, 0)
+}
+
+// Save writes img to outputPath in format, scaled to width pixels wide with
+// its aspect kept. A width of 0 keeps the image's own size.
+func Save(img image.Image, outputPath, format string, width int) error {
+	bounds := img.Bounds()
+	if width > 0 && width != bounds.Dx() {
+		height := bounds.Dy() * width / bounds.Dx()
+		scaled := image.NewRGBA(image.Rect(0, 0, width, height))
+		xdraw.CatmullRom.Scale(scaled, scaled.Bounds(), img, bounds, draw.Src, nil)
+		img = scaled
+	}
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create image: %w", err)
+	}
+
+	switch format {
+	case models.ImageFormatJPEG:
+		err = jpeg.Encode(file, img, &jpeg.Options{Quality: 90})
+	case models.ImageFormatPNG, "":
+		err = png.Encode(file, img)
+	default:
+		err = fmt.Errorf("unknown image format: %s", format)
+	}
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to encode image: %w", err)
+	}
+	return file.Close()
+}
+
+// drawText draws s with its baseline at y and returns the x it ended at.
+func drawText(img draw.Image, face font.Face, c color.Color, x, y int, s string) int {
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(c),
+		Face: face,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(s)
+	return d.Dot.X.Round()
+}
+
+// wrap breaks s into at most maxLines lines no wider than width, ending the
+// last one with an ellipsis if the text does not fit.
+func wrap(face font.Face, s string, width, maxLines int) []string {
+	var lines []string
+	var line string
+	words := strings.Fields(s)
+	for i, word := range words {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if line == "" || font.MeasureString(face, candidate).Round() <= width {
+			line = candidate
+			continue
+		}
+
+		if len(lines) == maxLines-1 {
+			return append(lines, truncate(face, strings.Join(append([]string{line}, words[i:]...), " "), width))
+		}
+		lines = append(lines, truncate(face, line, width))
+		line = word
+	}
+	if line != "" {
+		lines = append(lines, truncate(face, line, width))
+	}
+	return lines
+}
+
+// truncate shortens s to fit width, marking the cut with an ellipsis.
+func truncate(face font.Face, s string, width int) string {
+	if font.MeasureString(face, s).Round() <= width {
+		return s
+	}
+	for s != "" {
+		_, size := utf8.DecodeLastRuneInString(s)
+		s = s[:len(s)-size]
+		if cut := strings.TrimRight(s, " ") + "…"; font.MeasureString(face, cut).Round() <= width {
+			return cut
+		}
+	}
+	return "…"
+}
+
+// compactCount formats a count the way GitHub does: 950, 1.2k, 3.4m.
+func compactCount(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return strings.TrimSuffix(fmt.Sprintf("%.1f", float64(n)/1_000_000), ".0") + "m"
+	case n >= 1_000:
+		return strings.TrimSuffix(fmt.Sprintf("%.1f", float64(n)/1_000), ".0") + "k"
+	default:
+		return fmt.Sprint(n)
+	}
+}
+
+func fillRect(img draw.Image, r image.Rectangle, c color.Color) {
+	draw.Draw(img, r, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+func fillCircle(img *image.RGBA, cx, cy, r int, c color.RGBA) {
+	for y := -r; y <= r; y++ {
+		for x := -r; x <= r; x++ {
+			if x*x+y*y <= r*r {
+				img.SetRGBA(cx+x, cy+y, c)
+			}
+		}
+	}
+}
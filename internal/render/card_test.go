@@ -0,0 +1,202 @@
+package render
+
+import (
+	"content-maestro/internal/models"
+	"flag"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/image/font"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden images in testdata")
+
+// goldenTolerance absorbs rounding differences in glyph anti-aliasing between
+// architectures; layout changes move whole glyphs and exceed it.
+const goldenTolerance = 8
+
+func TestDrawCardGolden(t *testing.T) {
+	tests := []struct {
+		name string
+		card RepoCard
+	}{
+		{
+			name: "full",
+			card: RepoCard{
+				Owner:       "think-root",
+				Name:        "content-maestro",
+				Description: "Schedules, generates and publishes posts about GitHub repositories to social networks.",
+				Language:    "Go",
+				Stars:       1234,
+				Forks:       56,
+			},
+		},
+		{
+			name: "long_description",
+			card: RepoCard{
+				Owner: "example",
+				Name:  "a-repository-with-a-name-far-too-long-for-a-single-line-of-the-card",
+				Description: "A description that goes on and on, long enough to need more than the three lines the card " +
+					"has room for, so the renderer has to wrap it and cut the last line short with an ellipsis " +
+					"rather than drawing it over the stats row underneath.",
+				Language: "Brainfuck",
+				Stars:    2_500_000,
+			},
+		},
+		{
+			name: "name_only",
+			card: RepoCard{Owner: "think-root", Name: "content-maestro"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DrawCard(tt.card)
+			path := filepath.Join("testdata", tt.name+".png")
+
+			if *update {
+				writeGolden(t, path, got)
+				return
+			}
+
+			want := readGolden(t, path)
+			if want.Bounds() != got.Bounds() {
+				t.Fatalf("card is %v, golden image is %v", got.Bounds(), want.Bounds())
+			}
+
+			diff := 0
+			for y := got.Bounds().Min.Y; y < got.Bounds().Max.Y; y++ {
+				for x := got.Bounds().Min.X; x < got.Bounds().Max.X; x++ {
+					if !closeColor(got.At(x, y), want.At(x, y)) {
+						diff++
+					}
+				}
+			}
+			if diff > 0 {
+				t.Errorf("%d pixels differ from %s; run go test ./internal/render -update and review the diff", diff, path)
+			}
+		})
+	}
+}
+
+func TestWriteCard(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "card.png")
+	if err := WriteCard(RepoCard{Owner: "think-root", Name: "content-maestro"}, path); err != nil {
+		t.Fatalf("WriteCard() error = %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	config, err := png.DecodeConfig(file)
+	if err != nil {
+		t.Fatalf("written card is not a PNG: %v", err)
+	}
+	if config.Width != CardWidth || config.Height != CardHeight {
+		t.Errorf("card is %dx%d, want %dx%d", config.Width, config.Height, CardWidth, CardHeight)
+	}
+}
+
+func TestCompactCount(t *testing.T) {
+	tests := map[int]string{
+		0:         "0",
+		999:       "999",
+		1000:      "1k",
+		1234:      "1.2k",
+		56_789:    "56.8k",
+		2_500_000: "2.5m",
+	}
+	for n, want := range tests {
+		if got := compactCount(n); got != want {
+			t.Errorf("compactCount(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestWrapLimitsLines(t *testing.T) {
+	width := 300
+	lines := wrap(faces.description, "one two three four five six seven eight nine ten eleven twelve thirteen fourteen", width, 2)
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2: %q", len(lines), lines)
+	}
+	for _, line := range lines {
+		if w := font.MeasureString(faces.description, line).Round(); w > width {
+			t.Errorf("line %q is %dpx wide, want at most %d", line, w, width)
+		}
+	}
+	if !strings.HasSuffix(lines[1], "…") {
+		t.Errorf("last line %q should end with an ellipsis", lines[1])
+	}
+}
+
+func closeColor(a, b color.Color) bool {
+	ar, ag, ab, aa := a.RGBA()
+	br, bg, bb, ba := b.RGBA()
+	for _, d := range [][2]uint32{{ar, br}, {ag, bg}, {ab, bb}, {aa, ba}} {
+		x, y := int(d[0]>>8), int(d[1]>>8)
+		if x-y > goldenTolerance || y-x > goldenTolerance {
+			return false
+		}
+	}
+	return true
+}
+
+func readGolden(t *testing.T, path string) image.Image {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open golden image (run with -update to create it): %v", err)
+	}
+	defer file.Close()
+
+	img, err := png.Decode(file)
+	if err != nil {
+		t.Fatalf("failed to decode golden image: %v", err)
+	}
+	return img
+}
+
+func writeGolden(t *testing.T, path string, img image.Image) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if err := png.Encode(file, img); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSaveScalesAndConverts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "card.jpg")
+	if err := Save(DrawCard(RepoCard{Name: "content-maestro"}), path, models.ImageFormatJPEG, 640); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	config, err := jpeg.DecodeConfig(file)
+	if err != nil {
+		t.Fatalf("saved image is not a JPEG: %v", err)
+	}
+	if config.Width != 640 || config.Height != 320 {
+		t.Errorf("image is %dx%d, want 640x320", config.Width, config.Height)
+	}
+}
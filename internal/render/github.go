@@ -0,0 +1,85 @@
+package render
+
+import (
+	"content-maestro/internal/models"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const githubAPIURL = "https://api.github.com/repos/"
+
+var GitHubHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+type githubRepository struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Language    string `json:"language"`
+	Stars       int    `json:"stargazers_count"`
+	Forks       int    `json:"forks_count"`
+	Owner       struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+}
+
+// FetchRepoCard reads the card fields of "owner/name" from the GitHub API.
+// GITHUB_TOKEN, if set, lifts the unauthenticated rate limit.
+func FetchRepoCard(usernameRepo string) (RepoCard, error) {
+	req, err := http.NewRequest("GET", githubAPIURL+usernameRepo, nil)
+	if err != nil {
+		return RepoCard{}, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", "Content-Maestro/1.0")
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	response, err := GitHubHTTPClient.Do(req)
+	if err != nil {
+		return RepoCard{}, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return RepoCard{}, fmt.Errorf("github returned status %d for %s", response.StatusCode, usernameRepo)
+	}
+
+	var repo githubRepository
+	if err := json.NewDecoder(response.Body).Decode(&repo); err != nil {
+		return RepoCard{}, fmt.Errorf("failed to decode github response: %w", err)
+	}
+
+	return RepoCard{
+		Owner:       repo.Owner.Login,
+		Name:        repo.Name,
+		Description: repo.Description,
+		Language:    repo.Language,
+		Stars:       repo.Stars,
+		Forks:       repo.Forks,
+	}, nil
+}
+
+// RepoImage renders the card for "owner/name" into outputPath. It stands in
+// for socialify, so it must not depend on the network either: when GitHub
+// cannot be reached the card shows the owner and name alone. Of the image
+// settings only the format and width apply; the card has a look of its own.
+func RepoImage(usernameRepo, outputPath string, settings models.ImageSettings) error {
+	card, err := FetchRepoCard(usernameRepo)
+	if err != nil {
+		log.Errorf("Failed to fetch repository metadata for %s, rendering its name only: %v", usernameRepo, err)
+		card = cardFromPath(usernameRepo)
+	}
+	return Save(DrawCard(card), outputPath, settings.Format, settings.Width)
+}
+
+func cardFromPath(usernameRepo string) RepoCard {
+	owner, name, found := strings.Cut(strings.Trim(usernameRepo, "/"), "/")
+	if !found {
+		return RepoCard{Name: owner}
+	}
+	return RepoCard{Owner: owner, Name: name}
+}
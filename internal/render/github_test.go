@@ -0,0 +1,117 @@
+package render
+
+import (
+	"bytes"
+	"content-maestro/internal/models"
+	"errors"
+	"image/png"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type mockTransport struct {
+	status int
+	body   string
+	err    error
+	path   string
+}
+
+func (m *mockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	m.path = req.URL.Path
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &http.Response{
+		StatusCode: m.status,
+		Body:       io.NopCloser(bytes.NewReader([]byte(m.body))),
+	}, nil
+}
+
+func useTransport(t *testing.T, transport *mockTransport) {
+	t.Helper()
+	oldClient := GitHubHTTPClient
+	GitHubHTTPClient = &http.Client{Transport: transport}
+	t.Cleanup(func() { GitHubHTTPClient = oldClient })
+}
+
+func TestFetchRepoCard(t *testing.T) {
+	transport := &mockTransport{
+		status: http.StatusOK,
+		body: `{"name": "content-maestro", "owner": {"login": "think-root"},
+			"description": "Publishes posts", "language": "Go", "stargazers_count": 42, "forks_count": 7}`,
+	}
+	useTransport(t, transport)
+
+	card, err := FetchRepoCard("think-root/content-maestro")
+	if err != nil {
+		t.Fatalf("FetchRepoCard() error = %v", err)
+	}
+
+	want := RepoCard{Owner: "think-root", Name: "content-maestro", Description: "Publishes posts", Language: "Go", Stars: 42, Forks: 7}
+	if card != want {
+		t.Errorf("FetchRepoCard() = %+v, want %+v", card, want)
+	}
+	if transport.path != "/repos/think-root/content-maestro" {
+		t.Errorf("requested %s", transport.path)
+	}
+}
+
+func TestFetchRepoCardErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		transport *mockTransport
+	}{
+		{name: "network error", transport: &mockTransport{err: errors.New("connection refused")}},
+		{name: "not found", transport: &mockTransport{status: http.StatusNotFound, body: `{"message": "Not Found"}`}},
+		{name: "invalid json", transport: &mockTransport{status: http.StatusOK, body: `{`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTransport(t, tt.transport)
+			if _, err := FetchRepoCard("think-root/content-maestro"); err == nil {
+				t.Error("FetchRepoCard() expected an error")
+			}
+		})
+	}
+}
+
+// Without metadata the card is still rendered, from the repository path.
+func TestRepoImageWithoutMetadata(t *testing.T) {
+	useTransport(t, &mockTransport{err: errors.New("connection refused")})
+
+	path := filepath.Join(t.TempDir(), "card.png")
+	if err := RepoImage("think-root/content-maestro", path, models.DefaultImageSettings()); err != nil {
+		t.Fatalf("RepoImage() error = %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	got, err := png.Decode(file)
+	if err != nil {
+		t.Fatalf("rendered image is not a PNG: %v", err)
+	}
+
+	want := readGolden(t, filepath.Join("testdata", "name_only.png"))
+	for y := 0; y < CardHeight; y++ {
+		for x := 0; x < CardWidth; x++ {
+			if !closeColor(got.At(x, y), want.At(x, y)) {
+				t.Fatalf("image differs from testdata/name_only.png at %d,%d", x, y)
+			}
+		}
+	}
+}
+
+func TestRepoImageInvalidPath(t *testing.T) {
+	useTransport(t, &mockTransport{err: errors.New("connection refused")})
+
+	if err := RepoImage("think-root/content-maestro", filepath.Join(t.TempDir(), "missing", "card.png"), models.DefaultImageSettings()); err == nil {
+		t.Error("RepoImage() expected an error for a missing directory")
+	}
+}
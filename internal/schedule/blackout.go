@@ -0,0 +1,107 @@
+package schedule
+
+import (
+	"content-maestro/internal/models"
+	"content-maestro/internal/store"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// BlackoutCovers reports whether a blackout window blocks publishing at t.
+// Disabled windows cover nothing.
+func BlackoutCovers(window models.BlackoutWindow, t time.Time) (bool, error) {
+	if !window.Enabled {
+		return false, nil
+	}
+
+	loc, err := time.LoadLocation(window.Timezone)
+	if err != nil {
+		return false, fmt.Errorf("blackout window %d has an unknown timezone: %w", window.ID, err)
+	}
+	local := t.In(loc)
+
+	switch window.Kind {
+	case models.BlackoutKindRange:
+		start, end, err := blackoutRange(window, loc)
+		if err != nil {
+			return false, err
+		}
+		return !local.Before(start) && local.Before(end), nil
+	case models.BlackoutKindWeekly:
+		day := strings.ToLower(local.Weekday().String()[:3])
+		return slices.Contains(window.Weekdays, day), nil
+	case models.BlackoutKindYearly:
+		// Zero-padded month-days sort like the days they name.
+		today := local.Format(models.BlackoutMonthDayLayout)
+		if window.Start <= window.End {
+			return today >= window.Start && today <= window.End, nil
+		}
+		// A window such as 12-31 to 01-01 wraps around the new year.
+		return today >= window.Start || today <= window.End, nil
+	default:
+		return false, fmt.Errorf("blackout window %d has an unknown kind: %s", window.ID, window.Kind)
+	}
+}
+
+// blackoutRange returns the span a range window blocks, end exclusive. Dates
+// cover whole days in the window's zone; RFC 3339 times carry their own offset.
+func blackoutRange(window models.BlackoutWindow, loc *time.Location) (start, end time.Time, err error) {
+	if start, err = time.ParseInLocation(models.BlackoutDateLayout, window.Start, loc); err == nil {
+		if end, err = time.ParseInLocation(models.BlackoutDateLayout, window.End, loc); err != nil {
+			return start, end, fmt.Errorf("blackout window %d has an invalid end: %w", window.ID, err)
+		}
+		return start, end.AddDate(0, 0, 1), nil
+	}
+
+	if start, err = time.Parse(time.RFC3339, window.Start); err != nil {
+		return start, end, fmt.Errorf("blackout window %d has an invalid start: %w", window.ID, err)
+	}
+	if end, err = time.Parse(time.RFC3339, window.End); err != nil {
+		return start, end, fmt.Errorf("blackout window %d has an invalid end: %w", window.ID, err)
+	}
+	return start, end, nil
+}
+
+// activeBlackout returns the first blackout window covering t, or nil. A window
+// that cannot be read is logged and ignored, as is a store error: a broken
+// calendar should not silently stop publishing.
+func activeBlackout(st store.StoreInterface, t time.Time) *models.BlackoutWindow {
+	windows, err := st.GetBlackoutWindows()
+	if err != nil {
+		log.Errorf("Failed to read blackout windows: %v", err)
+		return nil
+	}
+
+	for i := range windows {
+		covers, err := BlackoutCovers(windows[i], t)
+		if err != nil {
+			log.Errorf("Ignoring blackout window: %v", err)
+			continue
+		}
+		if covers {
+			return &windows[i]
+		}
+	}
+	return nil
+}
+
+// blackoutRun records a run that published nothing because of a blackout.
+func blackoutRun(st store.StoreInterface, name string, run models.JobRun, window *models.BlackoutWindow) {
+	message := fmt.Sprintf("Skipped: blackout window '%s' (%d)", window.Name, window.ID)
+	log.Debug(message)
+
+	entry := &models.CronHistory{
+		Name:        name,
+		Success:     models.CronStatusBlackout,
+		Output:      message,
+		RunID:       run.ID,
+		Manual:      run.Manual,
+		ScheduledAt: run.ScheduledAt,
+		FiredAt:     run.FiredAt,
+	}
+	if err := st.LogCronHistory(entry); err != nil {
+		log.Errorf("Failed to log blacked-out %s run: %v", name, err)
+	}
+}
@@ -0,0 +1,101 @@
+package schedule
+
+import (
+	"content-maestro/internal/api"
+	"content-maestro/internal/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBlackoutCovers(t *testing.T) {
+	kyiv, err := time.LoadLocation("Europe/Kyiv")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+
+	christmas := models.BlackoutWindow{Kind: models.BlackoutKindRange, Start: "2025-12-24", End: "2025-12-26", Timezone: "UTC", Enabled: true}
+	incident := models.BlackoutWindow{Kind: models.BlackoutKindRange, Start: "2025-06-01T10:00:00Z", End: "2025-06-01T12:00:00Z", Timezone: "UTC", Enabled: true}
+	weekend := models.BlackoutWindow{Kind: models.BlackoutKindWeekly, Weekdays: []string{"sat", "sun"}, Timezone: "Europe/Kyiv", Enabled: true}
+	newYear := models.BlackoutWindow{Kind: models.BlackoutKindYearly, Start: "12-31", End: "01-01", Timezone: "UTC", Enabled: true}
+	summer := models.BlackoutWindow{Kind: models.BlackoutKindYearly, Start: "07-01", End: "08-31", Timezone: "UTC", Enabled: true}
+	disabled := christmas
+	disabled.Enabled = false
+
+	tests := []struct {
+		name   string
+		window models.BlackoutWindow
+		at     time.Time
+		want   bool
+	}{
+		{"first day of date range", christmas, time.Date(2025, 12, 24, 0, 0, 0, 0, time.UTC), true},
+		{"last day of date range", christmas, time.Date(2025, 12, 26, 23, 59, 0, 0, time.UTC), true},
+		{"day after date range", christmas, time.Date(2025, 12, 27, 0, 0, 0, 0, time.UTC), false},
+		{"inside time range", incident, time.Date(2025, 6, 1, 11, 0, 0, 0, time.UTC), true},
+		{"end of time range", incident, time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC), false},
+		// Friday 23:30 UTC is already Saturday in Kyiv.
+		{"weekday in window zone", weekend, time.Date(2025, 6, 6, 23, 30, 0, 0, time.UTC), true},
+		{"weekday outside", weekend, time.Date(2025, 6, 4, 12, 0, 0, 0, kyiv), false},
+		{"yearly wrap before new year", newYear, time.Date(2030, 12, 31, 9, 0, 0, 0, time.UTC), true},
+		{"yearly wrap after new year", newYear, time.Date(2031, 1, 1, 9, 0, 0, 0, time.UTC), true},
+		{"yearly wrap outside", newYear, time.Date(2031, 1, 2, 9, 0, 0, 0, time.UTC), false},
+		{"yearly inside", summer, time.Date(2026, 8, 15, 9, 0, 0, 0, time.UTC), true},
+		{"yearly outside", summer, time.Date(2026, 9, 1, 9, 0, 0, 0, time.UTC), false},
+		{"disabled", disabled, time.Date(2025, 12, 25, 12, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BlackoutCovers(tt.window, tt.at)
+			if err != nil {
+				t.Fatalf("BlackoutCovers() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("BlackoutCovers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMessageJobSkipsDuringBlackout(t *testing.T) {
+	withMessageJobWorkdir(t)
+
+	st := &retryStore{blackouts: []models.BlackoutWindow{
+		{ID: 1, Name: "broken", Kind: "monthly", Timezone: "UTC", Enabled: true},
+		{ID: 2, Name: "incident", Kind: models.BlackoutKindWeekly, Timezone: "UTC", Enabled: true,
+			Weekdays: []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"}},
+	}}
+	if err := api.LoadAPIConfigs(st); err != nil {
+		t.Fatalf("LoadAPIConfigs() error = %v", err)
+	}
+
+	// The queue is never asked for an item, so no repository stub is needed.
+	MessageJob(nil, st, models.JobRun{})
+
+	if st.logCalls != 1 {
+		t.Fatalf("cron history writes = %d, want 1", st.logCalls)
+	}
+	if st.loggedStatus != models.CronStatusBlackout {
+		t.Errorf("status = %d, want %d", st.loggedStatus, models.CronStatusBlackout)
+	}
+	if !strings.Contains(st.loggedOutput, "incident") {
+		t.Errorf("output = %q, want it to name the window", st.loggedOutput)
+	}
+	if IsJobRunning("message") {
+		t.Error("blacked-out run kept the job lock")
+	}
+}
+
+func TestRetryQueueJobWaitsOutBlackout(t *testing.T) {
+	st := &retryStore{
+		due: []models.Delivery{{URL: retryTestURL, APIName: "threads"}},
+		blackouts: []models.BlackoutWindow{{ID: 1, Name: "incident", Kind: models.BlackoutKindRange, Timezone: "UTC", Enabled: true,
+			Start: time.Now().Add(-time.Hour).Format(time.RFC3339), End: time.Now().Add(time.Hour).Format(time.RFC3339)}},
+	}
+
+	RetryQueueJob(st)
+
+	if len(st.attempts) != 0 || st.logCalls != 0 || len(st.abandoned) != 0 {
+		t.Errorf("retry queue ran during a blackout: attempts %+v, %d log calls", st.attempts, st.logCalls)
+	}
+}
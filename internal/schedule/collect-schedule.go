@@ -2,11 +2,13 @@ package schedule
 
 import (
 	"bytes"
+	"content-maestro/internal/models"
 	"content-maestro/internal/notification"
 	"content-maestro/internal/store"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math/rand/v2"
 	"net/http"
 	"os"
 	"strconv"
@@ -94,25 +96,146 @@ func getContentAlchemistTimeout() time.Duration {
 	return time.Duration(timeoutSeconds) * time.Second
 }
 
-func CollectJob(s *gocron.Scheduler, store store.StoreInterface) {
-	log.Debug("Collecting posts...")
+func CollectJob(s *gocron.Scheduler, store store.StoreInterface, run models.JobRun) {
+	runCollect(s, store, models.JobCollect, run)
+}
+
+// CollectProfileJob is the job of a named collect profile.
+func CollectProfileJob(store store.StoreInterface, name string) models.JobFunc {
+	return func(s *gocron.Scheduler, run models.JobRun) {
+		runCollect(s, store, name, run)
+	}
+}
+
+// collectSettingsFor returns what a collect run harvests: the collect settings
+// when profileName is empty, the named profile's otherwise, along with the
+// profile.
+func collectSettingsFor(s store.StoreInterface, profileName string) (*store.CollectSettings, *models.CollectProfile, error) {
+	if profileName == "" {
+		settings, err := s.GetCollectSettings()
+		return settings, nil, err
+	}
+
+	profile, err := s.GetCollectProfile(profileName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if profile == nil {
+		return nil, nil, fmt.Errorf("collect profile '%s' not found", profileName)
+	}
+	return &store.CollectSettings{
+		MaxRepos:           profile.MaxRepos,
+		Resource:           profile.Resource,
+		Since:              profile.Since,
+		SpokenLanguageCode: profile.SpokenLanguageCode,
+		Period:             profile.Period,
+		Language:           profile.Language,
+	}, profile, nil
+}
+
+// pickPromptVersion chooses one of the assigned prompt versions in proportion
+// to their weights, given roll in [0, 1). It returns "" when none is assigned.
+func pickPromptVersion(assigned []models.PromptVersionWeight, roll float64) string {
+	total := 0
+	for _, version := range assigned {
+		total += promptVersionWeight(version)
+	}
+	if total == 0 {
+		return ""
+	}
+
+	target := int(roll * float64(total))
+	for _, version := range assigned {
+		target -= promptVersionWeight(version)
+		if target < 0 {
+			return version.Name
+		}
+	}
+	return assigned[len(assigned)-1].Name
+}
+
+func promptVersionWeight(version models.PromptVersionWeight) int {
+	if version.Weight <= 0 {
+		return 1
+	}
+	return version.Weight
+}
+
+// applyPromptVersion returns the prompt settings with the version's prompt, and
+// its model and temperature where it sets them.
+func applyPromptVersion(settings models.PromptSettings, version *models.PromptVersion) models.PromptSettings {
+	settings.Content = version.Content
+	if version.Model != "" {
+		settings.Model = version.Model
+	}
+	if version.Temperature != nil {
+		settings.Temperature = *version.Temperature
+	}
+	return settings
+}
+
+// applyPromptOverride returns the prompt settings with the fields the override
+// sets replaced.
+func applyPromptOverride(settings models.PromptSettings, override *models.UpdatePromptSettingsRequest) models.PromptSettings {
+	if override == nil {
+		return settings
+	}
+	if override.UseDirectURL != nil {
+		settings.UseDirectURL = *override.UseDirectURL
+	}
+	if override.LlmProvider != nil {
+		settings.LlmProvider = *override.LlmProvider
+	}
+	if override.Temperature != nil {
+		settings.Temperature = *override.Temperature
+	}
+	if override.Content != nil {
+		settings.Content = *override.Content
+	}
+	if override.Model != nil {
+		settings.Model = *override.Model
+	}
+	if override.LlmOutputLanguage != nil {
+		settings.LlmOutputLanguage = *override.LlmOutputLanguage
+	}
+	return settings
+}
+
+// runCollect asks content-alchemist for new repositories. The collect cron uses
+// the collect settings; any other name is a collect profile, which brings its
+// own settings and prompt override and tags its history entries.
+func runCollect(s *gocron.Scheduler, store store.StoreInterface, name string, run models.JobRun) {
+	log.Debugf("Collecting posts for %s...", name)
+
+	profileName := ""
+	if name != models.JobCollect {
+		profileName = name
+	}
+
+	run, finish, holder := beginRun(name, run)
+	if finish == nil {
+		skipRun(store, name, run, holder)
+		return
+	}
+	defer finish()
 
 	var status int
 	var logMessage string
+	var promptVersion string
 
 	defer func() {
 
 		if r := recover(); r != nil {
 			panicMessage := fmt.Sprintf("Panic occurred: %v. %s", r, logMessage)
 			log.Error("Collect job panic: %v", r)
-			if err := store.LogCronExecution("collect", 0, panicMessage); err != nil {
+			if err := store.LogCronHistory(&models.CronHistory{Name: name, Success: 0, Output: panicMessage, RunID: run.ID, Manual: run.Manual, ScheduledAt: run.ScheduledAt, FiredAt: run.FiredAt, Profile: profileName, PromptVersion: promptVersion}); err != nil {
 				log.Error("Failed to log panic execution: %v", err)
 			}
-			notification.NotifyCronResult("collect", 0, panicMessage)
+			notification.NotifyCronResult(name, 0, panicMessage)
 			panic(r)
 		}
 
-		if err := store.LogCronExecution("collect", status, logMessage); err != nil {
+		if err := store.LogCronHistory(&models.CronHistory{Name: name, Success: status, Output: logMessage, RunID: run.ID, Manual: run.Manual, ScheduledAt: run.ScheduledAt, FiredAt: run.FiredAt, Profile: profileName, PromptVersion: promptVersion}); err != nil {
 			log.Error("Failed to log cron execution: %v", err)
 		}
 		// Only alert via Pushover when the collect job genuinely failed
@@ -120,12 +243,12 @@ func CollectJob(s *gocron.Scheduler, store store.StoreInterface) {
 		// hit transient errors while others were collected successfully - that
 		// is normal operation and shouldn't generate a notification.
 		if status == 0 {
-			notification.NotifyCronResult("collect", status, logMessage)
+			notification.NotifyCronResult(name, status, logMessage)
 		}
 	}()
 
 
-	settings, err := store.GetCollectSettings()
+	settings, profile, err := collectSettingsFor(store, profileName)
 	if err != nil {
 		log.Error("Error getting collect settings: %v", err)
 		status = 0
@@ -133,13 +256,32 @@ func CollectJob(s *gocron.Scheduler, store store.StoreInterface) {
 		return
 	}
 
-	promptSettings, err := store.GetPromptSettings()
+	globalPromptSettings, err := store.GetPromptSettings()
 	if err != nil {
 		log.Error("Error getting prompt settings: %v", err)
 		status = 0
 		logMessage = fmt.Sprintf("Error getting prompt settings: %v", err)
 		return
 	}
+	promptSettings := *globalPromptSettings
+	if profile != nil {
+		promptSettings = applyPromptOverride(promptSettings, profile.Prompt)
+
+		if versionName := pickPromptVersion(profile.PromptVersions, rand.Float64()); versionName != "" {
+			version, err := store.GetPromptVersion(versionName)
+			if err == nil && version == nil {
+				err = fmt.Errorf("prompt version '%s' not found", versionName)
+			}
+			if err != nil {
+				log.Error("Error getting prompt version: %v", err)
+				status = 0
+				logMessage = fmt.Sprintf("Error getting prompt version: %v", err)
+				return
+			}
+			promptSettings = applyPromptVersion(promptSettings, version)
+			promptVersion = version.Name
+		}
+	}
 
 	payload := generateRequest{
 		MaxRepos:           settings.MaxRepos,
@@ -279,19 +421,3 @@ func CollectJob(s *gocron.Scheduler, store store.StoreInterface) {
 		}
 	}
 }
-
-func CollectCron(store store.StoreInterface) *gocron.Scheduler {
-	s := gocron.NewScheduler(time.UTC)
-
-	setting, err := store.GetCronSetting("collect")
-	if err != nil || setting == nil || !setting.IsActive {
-		log.Debug("Collect cron is disabled")
-		return s
-	}
-
-	log.Debugf("Collect cron is enabled with schedule: %s", setting.Schedule)
-	s.Cron(setting.Schedule).Do(CollectJob, s, store)
-	s.StartAsync()
-	log.Debug("Scheduler started successfully for collect cron")
-	return s
-}
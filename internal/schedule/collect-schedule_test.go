@@ -1,7 +1,9 @@
 package schedule
 
 import (
+	"content-maestro/internal/models"
 	"os"
+	"strings"
 	"testing"
 	"time"
 )
@@ -58,3 +60,113 @@ func TestGetContentAlchemistTimeout(t *testing.T) {
 		})
 	}
 }
+
+// profileStore serves one collect profile on top of retryStore.
+type profileStore struct {
+	retryStore
+	profile *models.CollectProfile
+}
+
+func (s *profileStore) GetCollectProfile(name string) (*models.CollectProfile, error) {
+	if s.profile == nil || s.profile.Name != name {
+		return nil, nil
+	}
+	return s.profile, nil
+}
+
+func TestCollectSettingsForProfile(t *testing.T) {
+	model := "gpt-4o"
+	profile := &models.CollectProfile{
+		Name: "rust-daily", MaxRepos: 3, Resource: "github", Since: "daily", SpokenLanguageCode: "en",
+		Period: "past_24_hours", Language: "Rust", Prompt: &models.UpdatePromptSettingsRequest{Model: &model},
+	}
+	store := &profileStore{profile: profile}
+
+	settings, got, err := collectSettingsFor(store, "rust-daily")
+	if err != nil {
+		t.Fatalf("collectSettingsFor() error = %v", err)
+	}
+	if settings.Language != "Rust" || settings.MaxRepos != 3 || settings.Since != "daily" {
+		t.Errorf("collectSettingsFor() settings = %+v, want the profile's", settings)
+	}
+	if got != profile {
+		t.Errorf("collectSettingsFor() profile = %v, want %v", got, profile)
+	}
+
+	if _, _, err := collectSettingsFor(store, "go-weekly"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("collectSettingsFor() error = %v for a missing profile, want not found", err)
+	}
+}
+
+func TestApplyPromptOverride(t *testing.T) {
+	global := models.PromptSettings{
+		UseDirectURL: true, LlmProvider: "openrouter", Temperature: 0.2, Content: "global prompt", Model: "m1", LlmOutputLanguage: "en",
+	}
+
+	if got := applyPromptOverride(global, nil); got != global {
+		t.Errorf("applyPromptOverride(nil) = %+v, want the global settings", got)
+	}
+
+	useDirectURL := false
+	content := "Rust prompt"
+	temperature := 0.0
+	got := applyPromptOverride(global, &models.UpdatePromptSettingsRequest{
+		UseDirectURL: &useDirectURL, Content: &content, Temperature: &temperature,
+	})
+	want := global
+	want.UseDirectURL = false
+	want.Content = "Rust prompt"
+	want.Temperature = 0
+	if got != want {
+		t.Errorf("applyPromptOverride() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPickPromptVersion(t *testing.T) {
+	assigned := []models.PromptVersionWeight{{Name: "concise", Weight: 3}, {Name: "detailed", Weight: 1}}
+
+	tests := []struct {
+		roll float64
+		want string
+	}{
+		{roll: 0, want: "concise"},
+		{roll: 0.74, want: "concise"},
+		{roll: 0.75, want: "detailed"},
+		{roll: 0.999, want: "detailed"},
+	}
+	for _, tt := range tests {
+		if got := pickPromptVersion(assigned, tt.roll); got != tt.want {
+			t.Errorf("pickPromptVersion(%v) = %q, want %q", tt.roll, got, tt.want)
+		}
+	}
+
+	// Unset weights count as one each.
+	even := []models.PromptVersionWeight{{Name: "a"}, {Name: "b"}}
+	if got := pickPromptVersion(even, 0.49); got != "a" {
+		t.Errorf("pickPromptVersion(0.49) = %q, want a", got)
+	}
+	if got := pickPromptVersion(even, 0.5); got != "b" {
+		t.Errorf("pickPromptVersion(0.5) = %q, want b", got)
+	}
+
+	if got := pickPromptVersion(nil, 0.5); got != "" {
+		t.Errorf("pickPromptVersion() = %q with nothing assigned, want none", got)
+	}
+}
+
+func TestApplyPromptVersion(t *testing.T) {
+	settings := models.PromptSettings{LlmProvider: "openai", Temperature: 0.2, Content: "global prompt", Model: "m1"}
+
+	got := applyPromptVersion(settings, &models.PromptVersion{Name: "concise", Content: "Be brief."})
+	want := settings
+	want.Content = "Be brief."
+	if got != want {
+		t.Errorf("applyPromptVersion() = %+v, want %+v", got, want)
+	}
+
+	temperature := 0.9
+	got = applyPromptVersion(settings, &models.PromptVersion{Name: "wild", Content: "Be bold.", Model: "m2", Temperature: &temperature})
+	if got.Model != "m2" || got.Temperature != 0.9 || got.LlmProvider != "openai" {
+		t.Errorf("applyPromptVersion() = %+v, want model m2 at 0.9 from openai", got)
+	}
+}
@@ -0,0 +1,236 @@
+package schedule
+
+import (
+	"content-maestro/internal/imageprovider"
+	"content-maestro/internal/imageurl"
+	"content-maestro/internal/models"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-co-op/gocron"
+)
+
+const (
+	defaultImageCacheTTL      = 24 * time.Hour
+	defaultImageCacheMaxBytes = 200 << 20
+
+	imageCacheInterval = time.Hour
+
+	partialImageSuffix = ".partial"
+)
+
+// imageCacheDir holds the generated images, one file per repository and image
+// settings, named after the hash of both. Tests point it elsewhere.
+var imageCacheDir = imageDir + "/cache"
+
+// imageCacheLocks holds a lock per cache entry, so a cron run, a retry and a
+// preview that want the same image wait for one generation instead of each
+// fetching it.
+var imageCacheLocks = struct {
+	sync.Mutex
+	byKey map[string]*imageCacheLock
+}{byKey: map[string]*imageCacheLock{}}
+
+type imageCacheLock struct {
+	sync.Mutex
+	waiters int
+}
+
+func lockImageCacheEntry(name string) func() {
+	imageCacheLocks.Lock()
+	lock, ok := imageCacheLocks.byKey[name]
+	if !ok {
+		lock = &imageCacheLock{}
+		imageCacheLocks.byKey[name] = lock
+	}
+	lock.waiters++
+	imageCacheLocks.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		imageCacheLocks.Lock()
+		lock.waiters--
+		if lock.waiters == 0 {
+			delete(imageCacheLocks.byKey, name)
+		}
+		imageCacheLocks.Unlock()
+	}
+}
+
+// imageCacheTTL is how long a generated image is reused, from
+// IMAGE_CACHE_TTL in seconds. Stars and descriptions change, so entries do not
+// live forever.
+func imageCacheTTL() time.Duration {
+	ttlStr := os.Getenv("IMAGE_CACHE_TTL")
+	if ttlStr == "" {
+		return defaultImageCacheTTL
+	}
+
+	ttlSeconds, err := strconv.Atoi(ttlStr)
+	if err != nil || ttlSeconds < 0 {
+		log.Errorf("Invalid IMAGE_CACHE_TTL value: %s, using default %s", ttlStr, defaultImageCacheTTL)
+		return defaultImageCacheTTL
+	}
+
+	return time.Duration(ttlSeconds) * time.Second
+}
+
+// imageCacheMaxBytes bounds the cache directory, from IMAGE_CACHE_MAX_MB.
+func imageCacheMaxBytes() int64 {
+	maxStr := os.Getenv("IMAGE_CACHE_MAX_MB")
+	if maxStr == "" {
+		return defaultImageCacheMaxBytes
+	}
+
+	maxMB, err := strconv.Atoi(maxStr)
+	if err != nil || maxMB < 1 {
+		log.Errorf("Invalid IMAGE_CACHE_MAX_MB value: %s, using default %d MB", maxStr, defaultImageCacheMaxBytes>>20)
+		return defaultImageCacheMaxBytes
+	}
+
+	return int64(maxMB) << 20
+}
+
+// cachedImagePath is where the image of a repository under the given settings
+// is cached. The name is derived from imageKey, so connectors, runs, retries
+// and previews that need the same image find the same file.
+func cachedImagePath(repoURL string, settings models.ImageSettings) string {
+	sum := sha256.Sum256([]byte(imageKey(repoURL, settings)))
+	return filepath.Join(imageCacheDir, hex.EncodeToString(sum[:])+"."+imageExtension(settings))
+}
+
+// cachedImage returns the image of a repository under the given settings,
+// generating it with the retry budget in config unless a fresh one is cached.
+func cachedImage(repoURL string, settings models.ImageSettings, config imageprovider.RetryConfig) (string, error) {
+	imageName := cachedImagePath(repoURL, settings)
+	unlock := lockImageCacheEntry(imageName)
+	defer unlock()
+
+	if info, err := os.Stat(imageName); err == nil && time.Since(info.ModTime()) < imageCacheTTL() {
+		log.Debugf("Using cached image %s for %s", filepath.Base(imageName), repoURL)
+		return imageName, nil
+	}
+
+	if err := os.MkdirAll(imageCacheDir, 0o777); err != nil {
+		return "", fmt.Errorf("failed to create image cache directory: %w", err)
+	}
+
+	// Generated under a temporary name and renamed into place, so /images/
+	// never serves a half-written file and a failed generation leaves the
+	// previous entry, if any, as it was.
+	partial := fmt.Sprintf("%s.%d%s", imageName, time.Now().UnixNano(), partialImageSuffix)
+	usernameRepo := strings.TrimPrefix(repoURL, "https://github.com/")
+	if err := generateImage(usernameRepo, partial, settings, config); err != nil {
+		if removeErr := os.Remove(partial); removeErr != nil && !os.IsNotExist(removeErr) {
+			log.Errorf("Failed to remove partial image %s: %v", partial, removeErr)
+		}
+		return "", err
+	}
+
+	if err := os.Rename(partial, imageName); err != nil {
+		os.Remove(partial)
+		return "", fmt.Errorf("failed to store image in cache: %w", err)
+	}
+
+	return imageName, nil
+}
+
+// imageCacheGrace is how much longer than the TTL an entry is kept on disk. An
+// entry handed out just before it went stale must outlive the signed link to
+// it, or a connector that fetches late gets a 404.
+func imageCacheGrace() time.Duration {
+	return imageurl.TTL()
+}
+
+// EvictImageCache removes cache entries that went stale more than
+// imageCacheGrace ago, then the oldest entries until the cache fits its size
+// limit. Entries younger than imageCacheGrace are kept either way: they may
+// have just been handed to a connector.
+func EvictImageCache(now time.Time) {
+	entries, err := os.ReadDir(imageCacheDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Errorf("Failed to read image cache: %v", err)
+		}
+		return
+	}
+
+	type cacheFile struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+
+	ttl := imageCacheTTL()
+	grace := imageCacheGrace()
+	var files []cacheFile
+	var total int64
+	removed := 0
+
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		path := filepath.Join(imageCacheDir, entry.Name())
+		if now.Sub(info.ModTime()) > ttl+grace {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				log.Errorf("Failed to evict cached image %s: %v", entry.Name(), err)
+				continue
+			}
+			removed++
+			continue
+		}
+
+		total += info.Size()
+		// A partial file is an image still being generated.
+		if !strings.HasSuffix(entry.Name(), partialImageSuffix) {
+			files = append(files, cacheFile{path: path, size: info.Size(), modTime: info.ModTime()})
+		}
+	}
+
+	maxBytes := imageCacheMaxBytes()
+	slices.SortFunc(files, func(a, b cacheFile) int { return a.modTime.Compare(b.modTime) })
+	for _, file := range files {
+		if total <= maxBytes {
+			break
+		}
+		if now.Sub(file.modTime) < grace {
+			break
+		}
+		if err := os.Remove(file.path); err != nil && !os.IsNotExist(err) {
+			log.Errorf("Failed to evict cached image %s: %v", filepath.Base(file.path), err)
+			continue
+		}
+		total -= file.size
+		removed++
+	}
+
+	if removed > 0 {
+		log.Debugf("Evicted %d cached images, %d bytes left", removed, total)
+	}
+}
+
+// ImageCacheCron starts the housekeeping worker that keeps the image cache
+// within its TTL and size limit. Like the retry queue, it has no setting and
+// always runs.
+func ImageCacheCron() *gocron.Scheduler {
+	s := gocron.NewScheduler(time.UTC)
+	s.Every(imageCacheInterval).SingletonMode().Do(func() { EvictImageCache(time.Now()) })
+	s.StartAsync()
+	log.Debug("Image cache housekeeping started")
+	return s
+}
@@ -0,0 +1,166 @@
+package schedule
+
+import (
+	"content-maestro/internal/imageprovider"
+	"content-maestro/internal/models"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// countingProvider writes a small image and counts how often it was asked to.
+type countingProvider struct{ calls int }
+
+func (p *countingProvider) Name() string { return "counting" }
+
+func (p *countingProvider) Generate(_, outputPath string, _ models.ImageSettings) error {
+	p.calls++
+	return os.WriteFile(outputPath, []byte("image"), 0o644)
+}
+
+func useImageCache(t *testing.T) *countingProvider {
+	t.Helper()
+
+	oldDir := imageCacheDir
+	imageCacheDir = t.TempDir()
+	t.Cleanup(func() { imageCacheDir = oldDir })
+
+	provider := &countingProvider{}
+	oldProviderFor := imageProviderFor
+	imageProviderFor = func(models.ImageSettings) (imageprovider.ImageProvider, error) { return provider, nil }
+	t.Cleanup(func() { imageProviderFor = oldProviderFor })
+
+	return provider
+}
+
+func TestCachedImageReusesFreshEntry(t *testing.T) {
+	provider := useImageCache(t)
+	settings := models.DefaultImageSettings()
+	url := "https://github.com/think-root/content-maestro"
+
+	first, err := cachedImage(url, settings, retryImageConfig)
+	if err != nil {
+		t.Fatalf("cachedImage() error = %v", err)
+	}
+	second, err := cachedImage(url, settings, retryImageConfig)
+	if err != nil {
+		t.Fatalf("cachedImage() error = %v", err)
+	}
+
+	if first != second {
+		t.Errorf("cachedImage() = %s, then %s; want the same entry", first, second)
+	}
+	if provider.calls != 1 {
+		t.Errorf("provider called %d times, want 1", provider.calls)
+	}
+	if filepath.Dir(first) != imageCacheDir {
+		t.Errorf("image %s is outside the cache directory", first)
+	}
+
+	settings.Theme = "Dark"
+	other, err := cachedImage(url, settings, retryImageConfig)
+	if err != nil {
+		t.Fatalf("cachedImage() error = %v", err)
+	}
+	if other == first {
+		t.Error("different image settings should get an entry of their own")
+	}
+}
+
+func TestCachedImageRegeneratesStaleEntry(t *testing.T) {
+	provider := useImageCache(t)
+	t.Setenv("IMAGE_CACHE_TTL", "60")
+	settings := models.DefaultImageSettings()
+	url := "https://github.com/think-root/content-maestro"
+
+	imageName, err := cachedImage(url, settings, retryImageConfig)
+	if err != nil {
+		t.Fatalf("cachedImage() error = %v", err)
+	}
+	old := time.Now().Add(-2 * time.Minute)
+	if err := os.Chtimes(imageName, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := cachedImage(url, settings, retryImageConfig); err != nil {
+		t.Fatalf("cachedImage() error = %v", err)
+	}
+	if provider.calls != 2 {
+		t.Errorf("provider called %d times, want 2", provider.calls)
+	}
+
+	entries, _ := os.ReadDir(imageCacheDir)
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), partialImageSuffix) {
+			t.Errorf("partial file %s left behind", entry.Name())
+		}
+	}
+}
+
+func TestEvictImageCache(t *testing.T) {
+	now := time.Now()
+	write := func(t *testing.T, name string, size int, age time.Duration) {
+		t.Helper()
+		path := filepath.Join(imageCacheDir, name)
+		if err := os.WriteFile(path, make([]byte, size), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		modTime := now.Add(-age)
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	check := func(t *testing.T, want map[string]bool) {
+		t.Helper()
+		for name, kept := range want {
+			_, err := os.Stat(filepath.Join(imageCacheDir, name))
+			if exists := err == nil; exists != kept {
+				t.Errorf("%s kept = %v, want %v", name, exists, kept)
+			}
+		}
+	}
+
+	t.Run("ttl", func(t *testing.T) {
+		useImageCache(t)
+		t.Setenv("IMAGE_CACHE_TTL", "3600")
+		t.Setenv("IMAGE_URL_TTL", "900")
+
+		write(t, "fresh.png", 10, 30*time.Minute)
+		write(t, "stale-in-grace.png", 10, time.Hour+time.Minute)
+		write(t, "expired.png", 10, 2*time.Hour)
+		write(t, "abandoned.png.1"+partialImageSuffix, 10, 2*time.Hour)
+
+		EvictImageCache(now)
+
+		check(t, map[string]bool{
+			"fresh.png": true,
+			// Stale, but possibly still being fetched by a connector.
+			"stale-in-grace.png":                   true,
+			"expired.png":                          false,
+			"abandoned.png.1" + partialImageSuffix: false,
+		})
+	})
+
+	t.Run("size", func(t *testing.T) {
+		useImageCache(t)
+		t.Setenv("IMAGE_CACHE_TTL", "3600")
+		t.Setenv("IMAGE_URL_TTL", "900")
+		t.Setenv("IMAGE_CACHE_MAX_MB", "1")
+
+		write(t, "oldest.png", 600<<10, 50*time.Minute)
+		write(t, "older.png", 300<<10, 40*time.Minute)
+		write(t, "new.png", 600<<10, time.Minute)
+
+		EvictImageCache(now)
+
+		check(t, map[string]bool{
+			// The oldest entries go until the cache fits in 1 MB.
+			"oldest.png": false,
+			"older.png":  true,
+			// Too young to evict even over the limit.
+			"new.png": true,
+		})
+	})
+}
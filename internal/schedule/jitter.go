@@ -0,0 +1,63 @@
+package schedule
+
+import (
+	"content-maestro/internal/models"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	"github.com/robfig/cron/v3"
+)
+
+// jitterTag marks the one-off jobs a jittered cron runs on.
+const jitterTag = "jitter"
+
+// scheduleJittered runs a cron as a chain of one-off jobs, each at the time its
+// schedule names moved by a random offset within the jitter window. A plain
+// cron job cannot fire before its scheduled time, which rules out the early
+// half of the window. Each run schedules its successor before the job starts,
+// so a long run does not hold the chain up.
+func scheduleJittered(s *gocron.Scheduler, setting *models.CronSetting, job models.JobFunc) error {
+	parsed, err := cron.ParseStandard(fmt.Sprintf("CRON_TZ=%s %s", s.Location(), setting.Schedule))
+	if err != nil {
+		return fmt.Errorf("failed to schedule %s: %w", setting.Name, err)
+	}
+	window := time.Duration(setting.JitterSeconds) * time.Second
+	name := setting.Name
+
+	var scheduleAfter func(after time.Time) error
+	scheduleAfter = func(after time.Time) error {
+		scheduledAt := parsed.Next(after)
+		fireAt := scheduledAt.Add(jitterOffset(window))
+		// Only the first run can land in the past, when the service starts
+		// inside its window.
+		if earliest := time.Now().Add(time.Second); fireAt.Before(earliest) {
+			fireAt = earliest
+		}
+
+		_, err := s.Every(1).Day().StartAt(fireAt).LimitRunsTo(1).Tag(jitterTag).Do(func() {
+			if err := scheduleAfter(scheduledAt); err != nil {
+				log.Errorf("Failed to schedule the next %s run: %v", name, err)
+			}
+			log.Debugf("%s run due at %s fired with jitter at %s", name,
+				scheduledAt.Format(time.RFC3339), time.Now().In(s.Location()).Format(time.RFC3339))
+			job(s, models.JobRun{ScheduledAt: &scheduledAt})
+		})
+		return err
+	}
+
+	if err := scheduleAfter(time.Now()); err != nil {
+		return fmt.Errorf("failed to schedule %s: %w", name, err)
+	}
+	s.StartAsync()
+	return nil
+}
+
+// jitterOffset picks an offset in [-window, window].
+func jitterOffset(window time.Duration) time.Duration {
+	if window <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int64N(int64(2*window)+1)) - window
+}
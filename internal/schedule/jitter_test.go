@@ -0,0 +1,79 @@
+package schedule
+
+import (
+	"content-maestro/internal/models"
+	"testing"
+	"time"
+
+	"github.com/go-co-op/gocron"
+)
+
+func TestJitterOffsetStaysInWindow(t *testing.T) {
+	window := 20 * time.Minute
+	var early, late bool
+	for i := 0; i < 1000; i++ {
+		offset := jitterOffset(window)
+		if offset < -window || offset > window {
+			t.Fatalf("jitterOffset() = %v, want within ±%v", offset, window)
+		}
+		early = early || offset < 0
+		late = late || offset > 0
+	}
+	if !early || !late {
+		t.Errorf("jitterOffset() only moved runs one way (early=%v, late=%v)", early, late)
+	}
+	if offset := jitterOffset(0); offset != 0 {
+		t.Errorf("jitterOffset(0) = %v, want 0", offset)
+	}
+}
+
+func TestStartSchedulerAppliesJitter(t *testing.T) {
+	s := gocron.NewScheduler(time.UTC)
+	defer s.Stop()
+
+	runs := make(chan models.JobRun, 1)
+	setting := &models.CronSetting{Name: "message", Schedule: "0 9 * * *", IsActive: true, Timezone: "Europe/Kyiv", JitterSeconds: 1200}
+	if err := StartScheduler(s, &retryStore{}, setting, func(_ *gocron.Scheduler, run models.JobRun) { runs <- run }); err != nil {
+		t.Fatalf("StartScheduler() error = %v", err)
+	}
+
+	scheduled := RunsAfter(setting, time.Now(), 3)
+	next := NextRun(s)
+	if next == nil {
+		t.Fatal("NextRun() = nil, want a jittered run")
+	}
+	if diff := next.Sub(scheduled[0]); diff < -20*time.Minute || diff > 20*time.Minute {
+		t.Errorf("NextRun() = %v, want within 20m of %v", next, scheduled[0])
+	}
+
+	// Later runs are listed at their scheduled times.
+	upcoming := NextRuns(s, setting, 3)
+	if len(upcoming) != 3 || !upcoming[1].Equal(scheduled[1]) || !upcoming[2].Equal(scheduled[2]) {
+		t.Errorf("NextRuns() = %v, want %v then %v", upcoming, next, scheduled[1:])
+	}
+
+	// Firing the pending run hands the job its scheduled time and queues the next.
+	if err := s.RunByTag(jitterTag); err != nil {
+		t.Fatalf("RunByTag() error = %v", err)
+	}
+	select {
+	case run := <-runs:
+		if run.ScheduledAt == nil || !run.ScheduledAt.Equal(scheduled[0]) {
+			t.Errorf("run.ScheduledAt = %v, want %v", run.ScheduledAt, scheduled[0])
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("jittered job did not run")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		next = NextRun(s)
+		if next != nil && next.Sub(scheduled[1]) >= -20*time.Minute && next.Sub(scheduled[1]) <= 20*time.Minute {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("NextRun() = %v after the run, want within 20m of %v", next, scheduled[1])
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
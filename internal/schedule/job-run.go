@@ -0,0 +1,142 @@
+package schedule
+
+import (
+	"content-maestro/internal/models"
+	"content-maestro/internal/store"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-co-op/gocron"
+)
+
+// ErrJobRunning is returned when a job is started by hand while a run of it -
+// scheduled, manual or a message retry - is still in progress.
+var ErrJobRunning = errors.New("job is already running")
+
+var (
+	runningMutex sync.Mutex
+	// runningJobs maps each locked job to the ID of the run holding it.
+	runningJobs = map[string]string{}
+)
+
+// NewRunID returns a random identifier for a job run.
+func NewRunID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(buf)
+}
+
+// lockJob takes the run lock of a job for runID. It returns the ID of the run
+// already holding the lock, or "" once the lock is runID's.
+func lockJob(name, runID string) string {
+	runningMutex.Lock()
+	defer runningMutex.Unlock()
+
+	if holder := runningJobs[name]; holder != "" {
+		return holder
+	}
+	runningJobs[name] = runID
+	return ""
+}
+
+// unlockJob releases the run lock of a job if runID holds it.
+func unlockJob(name, runID string) {
+	runningMutex.Lock()
+	defer runningMutex.Unlock()
+
+	if runningJobs[name] == runID {
+		delete(runningJobs, name)
+	}
+}
+
+// beginRun takes the run lock for a job run, giving the run an ID if the
+// scheduler did not and stamping the time it fired. When another run holds the
+// lock, finish is nil and holder is that run's ID.
+//
+// The scheduler itself would queue an overlapping run in singleton mode; the
+// lock skips it instead and leaves a trace in the cron history, which also
+// covers overlaps with manual runs and retries the scheduler knows nothing of.
+func beginRun(name string, run models.JobRun) (_ models.JobRun, finish func(), holder string) {
+	if run.ID == "" {
+		run.ID = NewRunID()
+	}
+	if run.FiredAt == nil {
+		firedAt := time.Now()
+		run.FiredAt = &firedAt
+	}
+
+	runningMutex.Lock()
+	defer runningMutex.Unlock()
+
+	switch holder := runningJobs[name]; holder {
+	case "":
+		runningJobs[name] = run.ID
+		return run, func() { unlockJob(name, run.ID) }, ""
+	case run.ID:
+		// RunJobNow took the lock for this run and releases it when the job returns.
+		return run, func() {}, ""
+	default:
+		return run, nil, holder
+	}
+}
+
+// skipRun records a run that did not start because another run held the job.
+func skipRun(st store.StoreInterface, name string, run models.JobRun, holder string) {
+	message := fmt.Sprintf("Skipped: %s run %s was still in progress", name, holder)
+	log.Debug(message)
+
+	entry := &models.CronHistory{
+		Name:        name,
+		Success:     models.CronStatusSkipped,
+		Output:      message,
+		RunID:       run.ID,
+		Manual:      run.Manual,
+		ScheduledAt: run.ScheduledAt,
+		FiredAt:     run.FiredAt,
+	}
+	if err := st.LogCronHistory(entry); err != nil {
+		log.Errorf("Failed to log skipped %s run: %v", name, err)
+	}
+}
+
+// IsJobRunning reports whether a run of the named job is in progress.
+func IsJobRunning(name string) bool {
+	runningMutex.Lock()
+	defer runningMutex.Unlock()
+	return runningJobs[name] != ""
+}
+
+// RunJobNow starts a manual run of a job in the background and returns as soon
+// as it has been started. It refuses to start while another run of the same job
+// is in progress.
+func RunJobNow(name string, job models.JobFunc, s *gocron.Scheduler) (models.JobRun, error) {
+	run := models.JobRun{ID: NewRunID(), Manual: true}
+	// Taken here rather than by the job, so a second request arriving before the
+	// goroutine is scheduled is still refused.
+	if holder := lockJob(name, run.ID); holder != "" {
+		return models.JobRun{}, fmt.Errorf("%w: %s run %s is in progress", ErrJobRunning, name, holder)
+	}
+
+	go func() {
+		defer unlockJob(name, run.ID)
+		defer func() {
+			// The jobs re-panic after recording the failure; a manual run must
+			// not take the API server down with it.
+			if r := recover(); r != nil {
+				log.Errorf("Manual %s run %s panicked: %v", name, run.ID, r)
+			}
+		}()
+
+		log.Debugf("Manual %s run %s started", name, run.ID)
+		job(s, run)
+	}()
+
+	return run, nil
+}
@@ -0,0 +1,145 @@
+package schedule
+
+import (
+	"content-maestro/internal/api"
+	"content-maestro/internal/models"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-co-op/gocron"
+)
+
+// waitForJob blocks until no run of the job is in progress.
+func waitForJob(t *testing.T, name string) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for IsJobRunning(name) {
+		if time.Now().After(deadline) {
+			t.Fatalf("%s is still running", name)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestRunJobNowRefusesOverlap(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan models.JobRun, 1)
+	job := func(_ *gocron.Scheduler, run models.JobRun) {
+		started <- run
+		<-release
+	}
+
+	run, err := RunJobNow("overlap", job, nil)
+	if err != nil {
+		t.Fatalf("RunJobNow() error = %v", err)
+	}
+	if run.ID == "" || !run.Manual {
+		t.Errorf("run = %+v, want a manual run with an ID", run)
+	}
+	if got := <-started; got != run {
+		t.Errorf("job received %+v, want %+v", got, run)
+	}
+
+	if _, err := RunJobNow("overlap", job, nil); !errors.Is(err, ErrJobRunning) {
+		t.Errorf("second RunJobNow() error = %v, want ErrJobRunning", err)
+	}
+
+	close(release)
+	waitForJob(t, "overlap")
+
+	// The scheduled instance counts as running too.
+	_, finish, _ := beginRun("overlap", models.JobRun{})
+	if _, err := RunJobNow("overlap", job, nil); !errors.Is(err, ErrJobRunning) {
+		t.Errorf("RunJobNow() during a scheduled run error = %v, want ErrJobRunning", err)
+	}
+	finish()
+}
+
+func TestRunJobNowRecordsManualRun(t *testing.T) {
+	withMessageJobWorkdir(t)
+
+	st := &retryStore{}
+	if err := api.LoadAPIConfigs(st); err != nil {
+		t.Fatalf("LoadAPIConfigs() error = %v", err)
+	}
+
+	run, err := RunJobNow("message", InitJobs(st)["message"], nil)
+	if err != nil {
+		t.Fatalf("RunJobNow() error = %v", err)
+	}
+	waitForJob(t, "message")
+
+	if st.logCalls != 1 {
+		t.Fatalf("cron history writes = %d, want 1", st.logCalls)
+	}
+	if st.loggedRunID != run.ID || !st.loggedManual {
+		t.Errorf("logged run = %q manual=%v, want %q manual=true", st.loggedRunID, st.loggedManual, run.ID)
+	}
+	// The job re-enters the lock RunJobNow took for it instead of skipping itself.
+	if st.loggedStatus == models.CronStatusSkipped {
+		t.Errorf("manual run was recorded as skipped: %s", st.loggedOutput)
+	}
+}
+
+func TestScheduledRunSkippedWhileJobRuns(t *testing.T) {
+	withMessageJobWorkdir(t)
+
+	st := &retryStore{}
+	if err := api.LoadAPIConfigs(st); err != nil {
+		t.Fatalf("LoadAPIConfigs() error = %v", err)
+	}
+
+	if holder := lockJob("message", "held-run"); holder != "" {
+		t.Fatalf("lockJob() holder = %q, want the lock", holder)
+	}
+	defer unlockJob("message", "held-run")
+
+	MessageJob(nil, st, models.JobRun{})
+
+	if st.logCalls != 1 {
+		t.Fatalf("cron history writes = %d, want 1", st.logCalls)
+	}
+	if st.loggedStatus != models.CronStatusSkipped {
+		t.Errorf("status = %d, want %d", st.loggedStatus, models.CronStatusSkipped)
+	}
+	if !strings.Contains(st.loggedOutput, "held-run") {
+		t.Errorf("output = %q, want it to name the run in progress", st.loggedOutput)
+	}
+	if st.loggedRunID == "" {
+		t.Error("skipped run was recorded without a run ID")
+	}
+	if !IsJobRunning("message") {
+		t.Error("skipped run released a lock it did not hold")
+	}
+}
+
+func TestRetryMessagePostRefusedWhileJobRuns(t *testing.T) {
+	st := &retryStore{}
+	if err := api.LoadAPIConfigs(st); err != nil {
+		t.Fatalf("LoadAPIConfigs() error = %v", err)
+	}
+
+	publishMutex.RLock()
+	defer publishMutex.RUnlock()
+
+	_, err := RetryMessagePost(st, []string{"bluesky"}, "https://github.com/owner/repo")
+	if !errors.Is(err, ErrJobRunning) {
+		t.Fatalf("RetryMessagePost() error = %v, want ErrJobRunning", err)
+	}
+	if st.logCalls != 0 {
+		t.Errorf("cron history writes = %d, want 0", st.logCalls)
+	}
+}
+
+func TestBeginRunStampsFireTime(t *testing.T) {
+	before := time.Now()
+	run, finish, _ := beginRun("fire-time", models.JobRun{})
+	defer finish()
+
+	if run.FiredAt == nil || run.FiredAt.Before(before) {
+		t.Errorf("run.FiredAt = %v, want the time the run began", run.FiredAt)
+	}
+}
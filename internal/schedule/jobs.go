@@ -3,20 +3,239 @@ package schedule
 import (
 	"content-maestro/internal/models"
 	"content-maestro/internal/store"
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/go-co-op/gocron"
+	"github.com/robfig/cron/v3"
 )
 
+// InitJobs maps every cron name to its job: collect, message, and each named
+// message schedule and collect profile in the store.
 func InitJobs(store store.StoreInterface) models.JobRegistry {
-	return models.JobRegistry{
-		"collect": func(s *gocron.Scheduler) {
-			CollectJob(s, store)
+	jobs := models.JobRegistry{
+		"collect": func(s *gocron.Scheduler, run models.JobRun) {
+			CollectJob(s, store, run)
 		},
-		"message": func(s *gocron.Scheduler) {
-			MessageJob(s, store)
+		"message": func(s *gocron.Scheduler, run models.JobRun) {
+			MessageJob(s, store, run)
 		},
 	}
+
+	settings, err := store.GetAllCronSettings()
+	if err != nil {
+		log.Errorf("Failed to load named schedules: %v", err)
+		return jobs
+	}
+	for _, setting := range settings {
+		if _, exists := jobs[setting.Name]; exists {
+			continue
+		}
+		switch setting.Job {
+		case models.JobMessage:
+			jobs[setting.Name] = MessageScheduleJob(store, setting.Name)
+		case models.JobCollect:
+			jobs[setting.Name] = CollectProfileJob(store, setting.Name)
+		}
+	}
+
+	return jobs
+}
+
+// MessageScheduleJob is the job of a named message schedule.
+func MessageScheduleJob(store store.StoreInterface, name string) models.JobFunc {
+	return func(s *gocron.Scheduler, run models.JobRun) {
+		runMessageSchedule(s, store, name, run)
+	}
+}
+
+// InitSchedulers builds a scheduler for every cron setting and starts the active
+// ones.
+func InitSchedulers(store store.StoreInterface, jobs models.JobRegistry) map[string]*gocron.Scheduler {
+	schedulers := map[string]*gocron.Scheduler{}
+
+	settings, err := store.GetAllCronSettings()
+	if err != nil {
+		log.Errorf("Failed to load cron settings: %v", err)
+		return schedulers
+	}
+
+	for i := range settings {
+		setting := &settings[i]
+		s := gocron.NewScheduler(time.UTC)
+		schedulers[setting.Name] = s
+
+		job, exists := jobs[setting.Name]
+		if !exists {
+			log.Errorf("No job registered for cron %s", setting.Name)
+			continue
+		}
+		if err := StartScheduler(s, store, setting, job); err != nil {
+			log.Errorf("Failed to start %s cron: %v", setting.Name, err)
+			continue
+		}
+		log.Debugf("%s cron active=%v with schedule: %s", setting.Name, setting.IsActive, setting.Schedule)
+	}
+
+	return schedulers
+}
+
+// resumeTag marks the job that ends a pause, so it is not taken for a run of
+// the cron.
+const resumeTag = "resume"
+
+// StartScheduler replaces whatever s was running with job on the setting's
+// schedule. An inactive setting leaves s stopped and empty; a paused one only
+// waits for its pause to end. A pause that ran out while the service was down
+// is cleared here.
+func StartScheduler(s *gocron.Scheduler, store store.StoreInterface, setting *models.CronSetting, job models.JobFunc) error {
+	s.Stop()
+	s.Clear()
+
+	location, err := CronLocation(setting)
+	if err != nil {
+		return fmt.Errorf("invalid timezone for %s: %w", setting.Name, err)
+	}
+	s.ChangeLocation(location)
+
+	if !setting.IsActive || setting.Schedule == "" {
+		return nil
+	}
+
+	if setting.PausedUntil != nil {
+		if setting.PausedUntil.After(time.Now()) {
+			return schedulePauseEnd(s, store, setting, job)
+		}
+		if err := store.UpdateCronPause(setting.Name, nil, ""); err != nil {
+			log.Errorf("Failed to clear the expired pause of %s: %v", setting.Name, err)
+		}
+		setting.PausedUntil, setting.PauseReason = nil, ""
+	}
+
+	if setting.JitterSeconds > 0 {
+		return scheduleJittered(s, setting, job)
+	}
+
+	if _, err := s.Cron(setting.Schedule).Do(job, s, models.JobRun{}); err != nil {
+		return fmt.Errorf("failed to schedule %s: %w", setting.Name, err)
+	}
+	s.StartAsync()
+	return nil
+}
+
+// schedulePauseEnd leaves only a one-off job on s that puts the cron back on
+// its schedule when the pause ends. Changing the cron in the meantime restarts
+// s, which drops the job along with the pause it belonged to.
+func schedulePauseEnd(s *gocron.Scheduler, store store.StoreInterface, setting *models.CronSetting, job models.JobFunc) error {
+	name := setting.Name
+	resume := func() {
+		// Restarting s from one of its own jobs would wait for that job to end.
+		go func() {
+			current, err := store.GetCronSetting(name)
+			if err != nil || current == nil {
+				log.Errorf("Failed to resume %s cron: setting unavailable (%v)", name, err)
+				return
+			}
+			if current.PausedUntil != nil && current.PausedUntil.After(time.Now()) {
+				// Extended since; the newer pause has its own resume job.
+				return
+			}
+			if err := StartScheduler(s, store, current, job); err != nil {
+				log.Errorf("Failed to resume %s cron: %v", name, err)
+				return
+			}
+			log.Debugf("%s cron resumed after its pause", name)
+		}()
+	}
+
+	until := *setting.PausedUntil
+	if _, err := s.Every(1).Day().StartAt(until).LimitRunsTo(1).Tag(resumeTag).Do(resume); err != nil {
+		return fmt.Errorf("failed to schedule the end of the %s pause: %w", name, err)
+	}
+	s.StartAsync()
+	log.Debugf("%s cron paused until %s", name, until.Format(time.RFC3339))
+	return nil
+}
+
+// CronLocation is the zone a cron setting's schedule is read in.
+func CronLocation(setting *models.CronSetting) (*time.Location, error) {
+	if setting.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(setting.Timezone)
+}
+
+// NextRun is when s fires next, in the scheduler's zone, or nil when it has
+// nothing scheduled. The end of a pause is not a run, and neither is a jittered
+// run that already fired: gocron only drops it at the time it would repeat.
+func NextRun(s *gocron.Scheduler) *time.Time {
+	if s == nil || !s.IsRunning() {
+		return nil
+	}
+
+	var next time.Time
+	for _, job := range s.Jobs() {
+		tags := job.Tags()
+		if slices.Contains(tags, resumeTag) || slices.Contains(tags, jitterTag) && job.RunCount() > 0 {
+			continue
+		}
+		if run := job.NextRun(); !run.IsZero() && (next.IsZero() || run.Before(next)) {
+			next = run
+		}
+	}
+	if next.IsZero() {
+		return nil
+	}
+	next = next.In(s.Location())
+	return &next
+}
+
+// NextRuns lists the next n times s fires. The first comes from the live
+// scheduler; the rest follow from the schedule, parsed the way gocron parses it.
+// With jitter only the first is exact: the others are the scheduled times, each
+// moved by an offset that is picked when its turn comes.
+func NextRuns(s *gocron.Scheduler, setting *models.CronSetting, n int) []time.Time {
+	next := NextRun(s)
+	if next == nil {
+		return nil
+	}
+
+	// The run the first one was moved from is the only one within the window,
+	// which validation keeps below half the gap between runs.
+	after := *next
+	if setting.JitterSeconds > 0 {
+		after = next.Add(-time.Duration(setting.JitterSeconds)*time.Second - time.Nanosecond)
+		if scheduled := runsAfter(s.Location(), setting.Schedule, after, 1); len(scheduled) > 0 {
+			after = scheduled[0]
+		}
+	}
+	return append([]time.Time{*next}, runsAfter(s.Location(), setting.Schedule, after, n-1)...)
+}
+
+// RunsAfter lists the first n times a cron fires after the given time, as it
+// would once a pause ending then is over.
+func RunsAfter(setting *models.CronSetting, after time.Time, n int) []time.Time {
+	location, err := CronLocation(setting)
+	if err != nil {
+		return nil
+	}
+	return runsAfter(location, setting.Schedule, after, n)
+}
+
+// runsAfter parses the schedule the way gocron parses it.
+func runsAfter(location *time.Location, schedule string, after time.Time, n int) []time.Time {
+	parsed, err := cron.ParseStandard(fmt.Sprintf("CRON_TZ=%s %s", location, schedule))
+	if err != nil {
+		return nil
+	}
+
+	var runs []time.Time
+	for next := after; len(runs) < n; {
+		next = parsed.Next(next).In(location)
+		runs = append(runs, next)
+	}
+	return runs
 }
 
 func NewScheduler(store store.StoreInterface, name string, defaultSchedule string) *gocron.Scheduler {
@@ -26,7 +245,7 @@ func NewScheduler(store store.StoreInterface, name string, defaultSchedule strin
 	if err != nil || setting == nil {
 		log.Debugf("%s cron setting not found in database, using default schedule", name)
 		if job, exists := InitJobs(store)[name]; exists {
-			s.Cron(defaultSchedule).Do(job, s)
+			s.Cron(defaultSchedule).Do(job, s, models.JobRun{})
 			s.StartAsync()
 			log.Debugf("Scheduler started for %s with default schedule: %s", name, defaultSchedule)
 		}
@@ -38,10 +257,12 @@ func NewScheduler(store store.StoreInterface, name string, defaultSchedule strin
 		return s
 	}
 
-	if job, exists := InitJobs(store)[name]; exists && setting.Schedule != "" {
-		s.Cron(setting.Schedule).Do(job, s)
-		s.StartAsync()
-		log.Debugf("Scheduler started for %s with schedule: %s", name, setting.Schedule)
+	if job, exists := InitJobs(store)[name]; exists {
+		if err := StartScheduler(s, store, setting, job); err != nil {
+			log.Errorf("Failed to start %s cron: %v", name, err)
+			return s
+		}
+		log.Debugf("Scheduler started for %s with schedule: %s (%s)", name, setting.Schedule, setting.Timezone)
 	}
 
 	return s
@@ -0,0 +1,123 @@
+package schedule
+
+import (
+	"content-maestro/internal/models"
+	"testing"
+	"time"
+
+	"github.com/go-co-op/gocron"
+)
+
+func TestStartSchedulerUsesTimezone(t *testing.T) {
+	s := gocron.NewScheduler(time.UTC)
+	defer s.Stop()
+
+	setting := &models.CronSetting{Name: "message", Schedule: "0 9 * * *", IsActive: true, Timezone: "Europe/Kyiv"}
+	if err := StartScheduler(s, &retryStore{}, setting, func(*gocron.Scheduler, models.JobRun) {}); err != nil {
+		t.Fatalf("StartScheduler() error = %v", err)
+	}
+
+	next := NextRun(s)
+	if next == nil {
+		t.Fatal("NextRun() = nil, want the next 09:00")
+	}
+	if next.Location().String() != "Europe/Kyiv" || next.Hour() != 9 || next.Minute() != 0 {
+		t.Errorf("NextRun() = %v, want 09:00 Europe/Kyiv", next)
+	}
+
+	runs := NextRuns(s, setting, 3)
+	if len(runs) != 3 || !runs[0].Equal(*next) {
+		t.Fatalf("NextRuns() = %v, want three runs starting at %v", runs, next)
+	}
+	for i := 1; i < len(runs); i++ {
+		if runs[i].Hour() != 9 || runs[i].Sub(runs[i-1]) < 23*time.Hour {
+			t.Errorf("NextRuns()[%d] = %v, want 09:00 on the following day", i, runs[i])
+		}
+	}
+
+	// Disabling stops the scheduler, which then has no next run.
+	setting.IsActive = false
+	if err := StartScheduler(s, &retryStore{}, setting, func(*gocron.Scheduler, models.JobRun) {}); err != nil {
+		t.Fatalf("StartScheduler() error = %v", err)
+	}
+	if next := NextRun(s); next != nil {
+		t.Errorf("NextRun() = %v for an inactive cron, want nil", next)
+	}
+	if runs := NextRuns(s, setting, 3); runs != nil {
+		t.Errorf("NextRuns() = %v for an inactive cron, want nil", runs)
+	}
+}
+
+func TestStartSchedulerRejectsUnknownTimezone(t *testing.T) {
+	s := gocron.NewScheduler(time.UTC)
+	defer s.Stop()
+
+	setting := &models.CronSetting{Name: "message", Schedule: "0 9 * * *", IsActive: true, Timezone: "Europe/Atlantis"}
+	if err := StartScheduler(s, &retryStore{}, setting, func(*gocron.Scheduler, models.JobRun) {}); err == nil {
+		t.Error("StartScheduler() = nil, want an error for an unknown timezone")
+	}
+}
+
+func TestStartSchedulerResumesAfterPause(t *testing.T) {
+	s := gocron.NewScheduler(time.UTC)
+	defer s.Stop()
+
+	until := time.Now().Add(300 * time.Millisecond)
+	setting := &models.CronSetting{Name: "message", Schedule: "0 9 * * *", IsActive: true, PausedUntil: &until, PauseReason: "incident"}
+	stored := *setting
+	st := &retryStore{cronSettings: map[string]*models.CronSetting{"message": &stored}}
+
+	if err := StartScheduler(s, st, setting, func(*gocron.Scheduler, models.JobRun) {}); err != nil {
+		t.Fatalf("StartScheduler() error = %v", err)
+	}
+	if next := NextRun(s); next != nil {
+		t.Fatalf("NextRun() = %v while paused, want nil", next)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for NextRun(s) == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("cron did not resume after its pause")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if stored.PausedUntil != nil || stored.PauseReason != "" {
+		t.Errorf("stored pause = %v %q after resuming, want it cleared", stored.PausedUntil, stored.PauseReason)
+	}
+}
+
+func TestStartSchedulerClearsExpiredPause(t *testing.T) {
+	s := gocron.NewScheduler(time.UTC)
+	defer s.Stop()
+
+	until := time.Now().Add(-time.Hour)
+	setting := &models.CronSetting{Name: "message", Schedule: "0 9 * * *", IsActive: true, PausedUntil: &until, PauseReason: "incident"}
+	stored := *setting
+	st := &retryStore{cronSettings: map[string]*models.CronSetting{"message": &stored}}
+
+	if err := StartScheduler(s, st, setting, func(*gocron.Scheduler, models.JobRun) {}); err != nil {
+		t.Fatalf("StartScheduler() error = %v", err)
+	}
+	if NextRun(s) == nil {
+		t.Error("NextRun() = nil, want the cron back on its schedule")
+	}
+	if stored.PausedUntil != nil || setting.PausedUntil != nil {
+		t.Error("expired pause was not cleared")
+	}
+}
+
+func TestRunsAfter(t *testing.T) {
+	setting := &models.CronSetting{Schedule: "0 9 * * *", Timezone: "Europe/Kyiv"}
+	kyiv, _ := time.LoadLocation("Europe/Kyiv")
+
+	runs := RunsAfter(setting, time.Date(2025, 6, 1, 9, 0, 0, 0, kyiv), 2)
+	want := []time.Time{time.Date(2025, 6, 2, 9, 0, 0, 0, kyiv), time.Date(2025, 6, 3, 9, 0, 0, 0, kyiv)}
+	if len(runs) != len(want) {
+		t.Fatalf("RunsAfter() = %v, want %v", runs, want)
+	}
+	for i := range want {
+		if !runs[i].Equal(want[i]) || runs[i].Location().String() != "Europe/Kyiv" {
+			t.Errorf("RunsAfter()[%d] = %v, want %v", i, runs[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package schedule
+
+import (
+	"content-maestro/internal/api"
+	"content-maestro/internal/models"
+	"content-maestro/internal/repository"
+	"content-maestro/internal/store"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// ConnectorPreview is what one connector would receive. Skip explains why the
+// connector would get nothing; Error is a failure the real send would hit too.
+type ConnectorPreview struct {
+	APIName      string              `json:"api_name"`
+	TextLanguage string              `json:"text_language"`
+	URL          string              `json:"url,omitempty"`
+	Skip         string              `json:"skip,omitempty"`
+	Error        string              `json:"error,omitempty"`
+	ImageURL     string              `json:"image_url,omitempty"`
+	Request      *api.RequestPreview `json:"request,omitempty"`
+}
+
+// MessagePreview is the response of a dry run.
+type MessagePreview struct {
+	Connectors []ConnectorPreview `json:"connectors"`
+}
+
+// PreviewMessage shows what a run of the named message schedule would send if
+// it ran now: the next queue item per connector language and the request of
+// each enabled connector the schedule publishes to. An empty name previews
+// every enabled connector. It has no side effects beyond caching the image,
+// which the run then reuses - nothing is sent, recorded or marked posted, and
+// an unreachable repository is reported instead of deleted.
+func PreviewMessage(st store.StoreInterface, name string) (*MessagePreview, error) {
+	apiConfigs := api.GetAPIConfigs()
+	if apiConfigs == nil {
+		return nil, fmt.Errorf("API configurations not loaded")
+	}
+
+	// Like the run, a schedule bound to no connectors publishes to every
+	// enabled one.
+	var bound []string
+	if name != "" {
+		setting, err := st.GetCronSetting(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s cron setting: %v", name, err)
+		}
+		if setting != nil {
+			bound = setting.APIs
+		}
+	}
+
+	globalSettings := globalImageSettings(st)
+	preview := &MessagePreview{Connectors: []ConnectorPreview{}}
+
+	var connectors []string
+	for _, apiName := range sortedAPINames(apiConfigs) {
+		if apiConfigs.APIs[apiName].Enabled && (len(bound) == 0 || slices.Contains(bound, apiName)) {
+			connectors = append(connectors, apiName)
+		}
+	}
+	for _, apiName := range bound {
+		if _, ok := apiConfigs.APIs[apiName]; !ok {
+			preview.Connectors = append(preview.Connectors, ConnectorPreview{APIName: apiName, Error: "not configured"})
+		}
+	}
+
+	for _, apiName := range connectors {
+		endpoint := apiConfigs.APIs[apiName]
+		connector := ConnectorPreview{APIName: apiName, TextLanguage: textLanguageOf(endpoint)}
+
+		item, _, err := nextQueueItem(st, connector.TextLanguage, connectors)
+		if err != nil {
+			connector.Error = fmt.Sprintf("failed to get repository (language %s): %v", connector.TextLanguage, err)
+			preview.Connectors = append(preview.Connectors, connector)
+			continue
+		}
+		if item == nil {
+			connector.Skip = fmt.Sprintf("no items in the publication queue for language %s", connector.TextLanguage)
+			preview.Connectors = append(preview.Connectors, connector)
+			continue
+		}
+
+		connector.URL = item.URL
+
+		statusCode, err := repository.ValidateRepositoryURL(item.URL)
+		if err == nil && statusCode != 200 {
+			connector.Skip = fmt.Sprintf("repository returned status %d: the run would delete it and take the next item in the queue", statusCode)
+			preview.Connectors = append(preview.Connectors, connector)
+			continue
+		}
+
+		previewConnector(st, &connector, endpoint, *item, globalSettings)
+		preview.Connectors = append(preview.Connectors, connector)
+	}
+
+	return preview, nil
+}
+
+// PreviewRetry shows what RetryMessagePost would send for the same arguments.
+func PreviewRetry(st store.StoreInterface, apiNames []string, url string) (*MessagePreview, error) {
+	apiConfigs := api.GetAPIConfigs()
+	if apiConfigs == nil {
+		return nil, fmt.Errorf("API configurations not loaded")
+	}
+
+	requested, err := normalizeAPINames(apiNames)
+	if err != nil {
+		return nil, err
+	}
+
+	url = strings.TrimSpace(url)
+	if url == "" {
+		latest, err := repository.GetLatestPostedRepository("")
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve the latest published repository: %w", err)
+		}
+		url = latest.URL
+	}
+
+	globalSettings := globalImageSettings(st)
+	preview := &MessagePreview{Connectors: []ConnectorPreview{}}
+
+	for _, apiName := range requested {
+		connector := ConnectorPreview{APIName: apiName, URL: url}
+
+		endpoint, ok := apiConfigs.APIs[apiName]
+		if !ok {
+			connector.Error = fmt.Sprintf("API %s is not configured", apiName)
+			preview.Connectors = append(preview.Connectors, connector)
+			continue
+		}
+		if !endpoint.Enabled {
+			connector.Error = fmt.Sprintf("API %s is disabled", apiName)
+			preview.Connectors = append(preview.Connectors, connector)
+			continue
+		}
+		connector.TextLanguage = textLanguageOf(endpoint)
+
+		item, err := repository.GetRepositoryByURL(url, connector.TextLanguage)
+		if err != nil {
+			connector.Error = fmt.Sprintf("failed to get repository (language %s): %v", connector.TextLanguage, err)
+			preview.Connectors = append(preview.Connectors, connector)
+			continue
+		}
+
+		previewConnector(st, &connector, endpoint, *item, globalSettings)
+		preview.Connectors = append(preview.Connectors, connector)
+	}
+
+	return preview, nil
+}
+
+// previewConnector fills in the request a connector would get for an item. The
+// ledger is only read: a connector that already has the item is shown as
+// skipped, as the message job would skip it.
+func previewConnector(st store.StoreInterface, connector *ConnectorPreview, endpoint api.APIEndpoint, item repository.Item, globalSettings models.ImageSettings) {
+	delivery, err := st.GetDelivery(item.URL, connector.APIName)
+	if err != nil {
+		log.Errorf("Failed to read delivery ledger for %s and %s API: %v", item.URL, connector.APIName, err)
+	} else if delivery != nil && delivery.Status == models.DeliveryStatusDelivered {
+		connector.Skip = "already delivered"
+		return
+	}
+
+	imageName := ""
+	if endpoint.SocialifyImage {
+		imageName, err = cachedImage(item.URL, endpoint.ImageSettings.Apply(globalSettings), retryImageConfig)
+		if err != nil {
+			connector.Error = fmt.Sprintf("failed to prepare image: %v", err)
+			return
+		}
+		if publicURL := os.Getenv("PUBLIC_URL"); publicURL != "" {
+			connector.ImageURL = signedImageURL(publicURL, imageName)
+		}
+	}
+
+	req, err := buildItemRequest(connector.APIName, endpoint, item, imageName)
+	if err == nil {
+		connector.Request, err = api.PreviewRequest(req)
+	}
+	if err != nil {
+		connector.Error = err.Error()
+	}
+}
+
+func textLanguageOf(endpoint api.APIEndpoint) string {
+	if endpoint.TextLanguage == "" {
+		return "en"
+	}
+	return endpoint.TextLanguage
+}
+
+func sortedAPINames(apiConfigs *api.APIConfig) []string {
+	names := make([]string, 0, len(apiConfigs.APIs))
+	for name := range apiConfigs.APIs {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
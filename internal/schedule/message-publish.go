@@ -2,11 +2,12 @@ package schedule
 
 import (
 	"content-maestro/internal/api"
+	"content-maestro/internal/imageprovider"
+	"content-maestro/internal/imageurl"
 	"content-maestro/internal/models"
 	"content-maestro/internal/repository"
-	"content-maestro/internal/socialify"
 	"content-maestro/internal/store"
-	"content-maestro/internal/utils"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -20,23 +21,24 @@ import (
 // so callers can answer with 400 instead of 500.
 var ErrInvalidRetryRequest = errors.New("invalid retry request")
 
-// retrySocialifyConfig keeps image generation short: a manual retry answers an
-// HTTP request, and the cron default (5 attempts, 20s apart) would block it for
-// well over a minute.
-var retrySocialifyConfig = socialify.RetryConfig{
+// retryImageConfig keeps image generation short: manual retries and previews
+// answer an HTTP request, and the cron default (5 attempts, 20s apart) would
+// block one for well over a minute.
+var retryImageConfig = imageprovider.RetryConfig{
 	MaxRetries:    2,
 	RetryInterval: 3 * time.Second,
 }
 
-const (
-	imageDir      = "./tmp/gh_project_img"
-	retryImageDir = imageDir + "/retry"
-)
+const imageDir = "./tmp/gh_project_img"
 
-// retryMutex serialises manual retries. Two of them publishing the same item
-// concurrently - a double-clicked button, or two open dashboards - would post
-// twice to the same connector.
-var retryMutex sync.Mutex
+// publishMutex keeps retries apart from everything else that publishes. Message
+// schedule runs - the message cron and every named schedule - share its read
+// lock, since each publishes to its own connectors. Manual retries and retry
+// queue passes take the write lock: they re-send an item a schedule may be
+// publishing right now, or another retry may be - a double-clicked button, two
+// open dashboards, or a queued retry coming due - and would post it twice to the
+// same connector.
+var publishMutex sync.RWMutex
 
 // imageURLPath turns a local image path into the path it is served under
 // /images/, so an image kept in a subdirectory stays reachable.
@@ -49,11 +51,83 @@ func imageURLPath(imageName string) string {
 	return filepath.ToSlash(relative)
 }
 
-// publishItem sends one repository to one configured API. Shared by the message
-// cron and by manual retries so both build requests the same way.
-func publishItem(apiName string, endpoint api.APIEndpoint, item repository.Item, imageName string) (*api.APIResponse, error) {
+// signedImageURL is the link a connector fetches a local image from. It is
+// signed and expires, so images under /images/ cannot be listed, guessed or
+// hot-linked.
+func signedImageURL(publicURL, imageName string) string {
+	return imageurl.Sign(publicURL, imageURLPath(imageName), time.Now().Add(imageurl.TTL()))
+}
+
+// publishItem sends one repository to one configured API and records the outcome
+// in the delivery ledger. Shared by the message cron and by manual retries so
+// both build requests the same way; manual marks the attempt as a manual retry.
+func publishItem(st store.StoreInterface, apiName string, endpoint api.APIEndpoint, item repository.Item, imageName string, manual bool) (*api.APIResponse, error) {
+	resp, err := sendItem(apiName, endpoint, item, imageName)
+
+	attempt := &models.DeliveryAttempt{URL: item.URL, APIName: apiName, Manual: manual}
+	switch {
+	case err != nil:
+		attempt.Error = err.Error()
+	case resp.Success:
+		attempt.Success = true
+		attempt.PostID = resp.PostID
+		attempt.PostURL = resp.PostURL
+	default:
+		attempt.Error = responseFailure(resp)
+	}
+	// The post itself already happened, so a ledger failure must not turn a
+	// delivered item into a reported failure.
+	if recordErr := st.RecordDeliveryAttempt(attempt); recordErr != nil {
+		log.Errorf("Failed to record delivery of %s to %s: %v", item.URL, apiName, recordErr)
+	}
+
+	return resp, err
+}
+
+// publishedPost returns the post reference a successful response carried, if
+// the connector's config extracts one.
+func publishedPost(apiName string, resp *api.APIResponse) (models.PublishedPost, bool) {
+	if resp == nil || !resp.Success || (resp.PostID == "" && resp.PostURL == "") {
+		return models.PublishedPost{}, false
+	}
+	return models.PublishedPost{APIName: apiName, PostID: resp.PostID, PostURL: resp.PostURL}, true
+}
+
+// responseFailure describes a response that did not count as a success. A
+// success rule explains itself; otherwise the status was not accepted.
+func responseFailure(resp *api.APIResponse) string {
+	if resp.Error != "" {
+		return fmt.Sprintf("API request failed with status %d: %s", resp.StatusCode, resp.Error)
+	}
+	return fmt.Sprintf("API request failed with status %d", resp.StatusCode)
+}
+
+func sendItem(apiName string, endpoint api.APIEndpoint, item repository.Item, imageName string) (*api.APIResponse, error) {
+	req, err := buildItemRequest(apiName, endpoint, item, imageName)
+	if err != nil {
+		return nil, err
+	}
+	return api.ExecuteRequest(req)
+}
+
+// buildItemRequest is the request a connector gets for an item, shared by real
+// sends and previews.
+func buildItemRequest(apiName string, endpoint api.APIEndpoint, item repository.Item, imageName string) (api.RequestConfig, error) {
 	var req api.RequestConfig
 
+	imageURL := ""
+	if endpoint.SocialifyImage && imageName != "" {
+		if publicURL := os.Getenv("PUBLIC_URL"); publicURL != "" {
+			imageURL = signedImageURL(publicURL, imageName)
+		} else if strings.ToLower(endpoint.ContentType) == "json" {
+			log.Error("PUBLIC_URL not set, cannot generate image_url for API %s", apiName)
+		}
+	}
+
+	if endpoint.BodyTemplate != "" {
+		return buildTemplatedRequest(apiName, endpoint, item, imageName, imageURL)
+	}
+
 	commonFields := map[string]string{
 		"text": item.Text,
 		"url":  item.URL,
@@ -77,13 +151,8 @@ func publishItem(apiName string, endpoint api.APIEndpoint, item repository.Item,
 			JSONBody: map[string]any{"text": item.Text, "url": item.URL},
 		}
 
-		if endpoint.SocialifyImage && imageName != "" {
-			publicURL := os.Getenv("PUBLIC_URL")
-			if publicURL != "" {
-				req.JSONBody["image_url"] = fmt.Sprintf("%s/images/%s", publicURL, imageURLPath(imageName))
-			} else {
-				log.Error("PUBLIC_URL not set, cannot generate image_url for API %s", apiName)
-			}
+		if imageURL != "" {
+			req.JSONBody["image_url"] = imageURL
 		}
 	default:
 		req = api.RequestConfig{
@@ -92,7 +161,46 @@ func publishItem(apiName string, endpoint api.APIEndpoint, item repository.Item,
 		}
 	}
 
-	return api.ExecuteRequest(req)
+	return req, nil
+}
+
+// buildTemplatedRequest builds the payload from the connector's body template. A
+// multipart connector still gets the image attached as a file; the template
+// only decides the form fields.
+func buildTemplatedRequest(apiName string, endpoint api.APIEndpoint, item repository.Item, imageName, imageURL string) (api.RequestConfig, error) {
+	language := endpoint.TextLanguage
+	if language == "" {
+		language = "en"
+	}
+
+	body, err := api.RenderBodyTemplate(endpoint.BodyTemplate, api.TemplateData{
+		Item:     item,
+		APIName:  apiName,
+		ImageURL: imageURL,
+		Language: language,
+		RunTime:  time.Now(),
+	})
+	if err != nil {
+		return api.RequestConfig{}, err
+	}
+
+	req := api.RequestConfig{APIName: apiName}
+
+	if strings.ToLower(endpoint.ContentType) == "multipart" {
+		req.FormFields, err = api.FormFieldsFromBody(body)
+		if err != nil {
+			return api.RequestConfig{}, err
+		}
+		if endpoint.SocialifyImage && imageName != "" {
+			req.FileFields = map[string]string{
+				"image": imageName,
+			}
+		}
+	} else {
+		req.JSONBody = body
+	}
+
+	return req, nil
 }
 
 // RetryOutcome is the per-connector result of a manual retry.
@@ -100,6 +208,8 @@ type RetryOutcome struct {
 	APIName string `json:"api_name"`
 	Success bool   `json:"success"`
 	Error   string `json:"error,omitempty"`
+	PostID  string `json:"post_id,omitempty"`
+	PostURL string `json:"post_url,omitempty"`
 }
 
 // RetryResult is the response of a manual retry.
@@ -130,8 +240,12 @@ func RetryMessagePost(st store.StoreInterface, apiNames []string, url string) (*
 		return nil, err
 	}
 
-	retryMutex.Lock()
-	defer retryMutex.Unlock()
+	if !publishMutex.TryLock() {
+		return nil, fmt.Errorf("%w: a message run or retry is in progress", ErrJobRunning)
+	}
+	defer publishMutex.Unlock()
+
+	runID := NewRunID()
 
 	// Pin the target before contacting any connector so every API in this call
 	// publishes the same repository.
@@ -147,18 +261,11 @@ func RetryMessagePost(st store.StoreInterface, apiNames []string, url string) (*
 	}
 
 	result := &RetryResult{URL: url}
+	var posts []models.PublishedPost
 
-	// One image per retry, not one per connector: the cron shares a single image
-	// across all of them, and each generation is a separate upstream fetch.
-	imageName := ""
-	defer func() {
-		if imageName == "" {
-			return
-		}
-		if err := os.Remove(imageName); err != nil && !os.IsNotExist(err) {
-			log.Errorf("Failed to remove retry image %s: %v", imageName, err)
-		}
-	}()
+	// Connectors whose image settings match share the cached image, which the
+	// cron run that published the item has usually generated already.
+	globalSettings := globalImageSettings(st)
 
 	for _, apiName := range requested {
 		endpoint, ok := apiConfigs.APIs[apiName]
@@ -183,15 +290,16 @@ func RetryMessagePost(st store.StoreInterface, apiNames []string, url string) (*
 		}
 		itemPosted = itemPosted || item.Posted
 
-		if endpoint.SocialifyImage && imageName == "" {
-			imageName, err = generateRetryImage(item.URL)
+		imageName := ""
+		if endpoint.SocialifyImage {
+			imageName, err = cachedImage(item.URL, endpoint.ImageSettings.Apply(globalSettings), retryImageConfig)
 			if err != nil {
 				result.addFailure(apiName, fmt.Sprintf("failed to prepare image: %v", err))
 				continue
 			}
 		}
 
-		resp, err := publishItem(apiName, endpoint, *item, imageName)
+		resp, err := publishItem(st, apiName, endpoint, *item, imageName, true)
 
 		switch {
 		case err != nil:
@@ -200,17 +308,22 @@ func RetryMessagePost(st store.StoreInterface, apiNames []string, url string) (*
 		case resp.Success:
 			log.Debugf("%s post created successfully during manual retry with language %s!", apiName, textLanguage)
 			result.Succeeded = append(result.Succeeded, apiName)
-			result.Outcomes = append(result.Outcomes, RetryOutcome{APIName: apiName, Success: true})
+			result.Outcomes = append(result.Outcomes, RetryOutcome{APIName: apiName, Success: true, PostID: resp.PostID, PostURL: resp.PostURL})
+			if post, ok := publishedPost(apiName, resp); ok {
+				posts = append(posts, post)
+			}
 		default:
 			log.Errorf("%s API request failed during manual retry (status %d): %s", apiName, resp.StatusCode, string(resp.Body))
-			result.addFailure(apiName, fmt.Sprintf("API request failed with status %d", resp.StatusCode))
+			result.addFailure(apiName, responseFailure(resp))
 		}
 	}
 
 	// Marking an unposted item as posted while some connector still failed would
 	// drop it out of the queue - the very failure this endpoint exists to repair -
-	// so it is only marked once every requested connector has it.
-	if !itemPosted && len(result.Succeeded) > 0 && len(result.Failed) == 0 {
+	// so it is only marked once every requested connector has it, and the
+	// connectors of every message schedule too.
+	if !itemPosted && len(result.Succeeded) > 0 && len(result.Failed) == 0 &&
+		receivedAll(st, url, publishingConnectors(st, apiConfigs, requested), result.Succeeded) {
 		if _, err := repository.UpdateRepositoryPosted(url, true); err != nil {
 			log.Errorf("Failed to update posted status for %s after manual retry: %v", url, err)
 		}
@@ -237,8 +350,17 @@ func RetryMessagePost(st store.StoreInterface, apiNames []string, url string) (*
 		Sent:   result.Succeeded,
 		Failed: result.Failed,
 		Manual: true,
+		Posts:  posts,
+	}
+	entry := &models.CronHistory{
+		Name:    "message",
+		Success: result.Status,
+		Output:  result.Message,
+		Details: details,
+		RunID:   runID,
+		Manual:  true,
 	}
-	if err := st.LogCronExecutionDetails("message", result.Status, result.Message, details); err != nil {
+	if err := st.LogCronHistory(entry); err != nil {
 		log.Errorf("Failed to log manual retry execution: %v", err)
 	}
 
@@ -280,27 +402,52 @@ func normalizeAPINames(apiNames []string) ([]string, error) {
 	return normalized, nil
 }
 
-// generateRetryImage writes the image into a subdirectory of the image root, so
-// the cron's cleanup - which only touches files - cannot delete it while the
-// connector is still fetching it.
-func generateRetryImage(repoURL string) (string, error) {
-	if err := os.MkdirAll(retryImageDir, 0o777); err != nil {
-		return "", fmt.Errorf("failed to create retry image directory: %w", err)
+// imageProviderFor picks the provider for a set of image settings; tests
+// replace it with a stub.
+var imageProviderFor = imageprovider.ForSettings
+
+// generateImage runs the provider the settings select with the given retry
+// budget and falls back to rendering the card locally, which needs no remote
+// service, when it fails.
+func generateImage(usernameRepo, imageName string, settings models.ImageSettings, config imageprovider.RetryConfig) error {
+	provider, err := imageProviderFor(settings)
+	if err == nil {
+		err = imageprovider.Generate(provider, usernameRepo, imageName, settings, config)
+		if err == nil || provider.Name() == models.ImageProviderLocal {
+			return err
+		}
 	}
 
-	usernameRepo := strings.TrimPrefix(repoURL, "https://github.com/")
-	imageName := fmt.Sprintf("%s/retry_%d.png", retryImageDir, time.Now().UnixNano())
+	log.Errorf("Image provider %s failed for %s: %v", settings.Provider, usernameRepo, err)
+	if err := (imageprovider.Local{}).Generate(usernameRepo, imageName, settings); err != nil {
+		return fmt.Errorf("failed to render fallback image: %w", err)
+	}
+	return nil
+}
 
-	if err := socialify.SocialifyWithConfig(usernameRepo, imageName, retrySocialifyConfig); err != nil {
-		log.Errorf("Socialify failed during manual retry: %v", err)
-		if err := utils.CopyFile("./assets/banner.jpg", imageName); err != nil {
-			// A failed generation can still have left a partial file behind.
-			if removeErr := os.Remove(imageName); removeErr != nil && !os.IsNotExist(removeErr) {
-				log.Errorf("Failed to remove partial retry image %s: %v", imageName, removeErr)
-			}
-			return "", fmt.Errorf("failed to copy fallback banner file: %w", err)
-		}
+// globalImageSettings reads the image settings connectors start from. The
+// defaults stand in when they cannot be read: an image in the default style is
+// better than none.
+func globalImageSettings(st store.StoreInterface) models.ImageSettings {
+	settings, err := st.GetImageSettings()
+	if err != nil {
+		log.Errorf("Failed to read image settings, using the defaults: %v", err)
+		return models.DefaultImageSettings()
 	}
+	return *settings
+}
+
+// imageKey identifies the image of a repository under the given settings, so
+// connectors whose settings match share one.
+func imageKey(repoURL string, settings models.ImageSettings) string {
+	settings.UpdatedAt = time.Time{}
+	data, _ := json.Marshal(settings)
+	return repoURL + " " + string(data)
+}
 
-	return imageName, nil
+func imageExtension(settings models.ImageSettings) string {
+	if settings.Format == models.ImageFormatJPEG {
+		return "jpg"
+	}
+	return "png"
 }
@@ -2,57 +2,123 @@ package schedule
 
 import (
 	"content-maestro/internal/api"
+	"content-maestro/internal/imageprovider"
+	"content-maestro/internal/imageurl"
 	"content-maestro/internal/models"
+	"content-maestro/internal/render"
+	"content-maestro/internal/repository"
 	"content-maestro/internal/store"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 )
 
 // retryStore is a StoreInterface stub that only serves API configs and captures
-// what the retry recorded in the cron history.
+// what a run recorded in the cron history and the delivery ledger.
 type retryStore struct {
 	configs []models.APIConfigModel
+	// cronSettings is what GetCronSetting finds; a missing name reads as nil.
+	cronSettings map[string]*models.CronSetting
 
 	loggedName    string
 	loggedStatus  int
 	loggedOutput  string
 	loggedDetails *models.MessageRunDetails
+	loggedRunID   string
+	loggedManual  bool
 	logCalls      int
+
+	// delivered seeds the ledger, keyed by "url api"; attempts captures what
+	// publishing recorded. Like the store, an attempt adds to the entry or
+	// creates it, and queueing or abandoning a retry updates it.
+	delivered map[string]*models.Delivery
+	attempts  []models.DeliveryAttempt
+
+	// due is what the retry queue finds; queued and abandoned capture what it
+	// did with the failures, keyed like delivered.
+	due       []models.Delivery
+	queued    map[string]time.Time
+	abandoned map[string]string
+
+	// blackouts is what the blackout check finds.
+	blackouts []models.BlackoutWindow
+
+	// imageSettings are the global image settings; nil reads as the defaults.
+	imageSettings *models.ImageSettings
 }
 
 func (s *retryStore) GetAllAPIConfigs() ([]models.APIConfigModel, error) {
 	return s.configs, nil
 }
 
-func (s *retryStore) LogCronExecutionDetails(name string, status int, output string, details *models.MessageRunDetails) error {
+func (s *retryStore) LogCronHistory(entry *models.CronHistory) error {
 	s.logCalls++
-	s.loggedName = name
-	s.loggedStatus = status
-	s.loggedOutput = output
-	s.loggedDetails = details
+	s.loggedName = entry.Name
+	s.loggedStatus = entry.Success
+	s.loggedOutput = entry.Output
+	s.loggedDetails = entry.Details
+	s.loggedRunID = entry.RunID
+	s.loggedManual = entry.Manual
 	return nil
 }
 
+func (s *retryStore) LogCronExecutionDetails(name string, status int, output string, details *models.MessageRunDetails) error {
+	return s.LogCronHistory(&models.CronHistory{Name: name, Success: status, Output: output, Details: details})
+}
+
 func (s *retryStore) LogCronExecution(name string, status int, output string) error {
 	return s.LogCronExecutionDetails(name, status, output, nil)
 }
 
 func (s *retryStore) Close() error                     { return nil }
 func (s *retryStore) InitializeDefaultSettings() error { return nil }
-func (s *retryStore) GetCronSetting(string) (*models.CronSetting, error) {
-	return nil, errors.New("not implemented")
+func (s *retryStore) GetCronSetting(name string) (*models.CronSetting, error) {
+	return s.cronSettings[name], nil
 }
 func (s *retryStore) GetAllCronSettings() ([]models.CronSetting, error) {
-	return nil, errors.New("not implemented")
+	var settings []models.CronSetting
+	for _, setting := range s.cronSettings {
+		settings = append(settings, *setting)
+	}
+	return settings, nil
 }
 func (s *retryStore) UpdateCronSetting(string, string, bool) (*models.CronSetting, error) {
 	return nil, errors.New("not implemented")
 }
+func (s *retryStore) CreateCronSetting(*models.CreateCronRequest) (*models.CronSetting, error) {
+	return nil, errors.New("not implemented")
+}
+func (s *retryStore) UpdateCronAPIs(string, []string) (*models.CronSetting, error) {
+	return nil, errors.New("not implemented")
+}
+func (s *retryStore) UpdateCronSchedule(string, string, string, int) error {
+	return errors.New("not implemented")
+}
+func (s *retryStore) UpdateCronStatus(string, bool) error {
+	return errors.New("not implemented")
+}
+func (s *retryStore) UpdateCronPause(name string, until *time.Time, reason string) error {
+	setting := s.cronSettings[name]
+	if setting == nil {
+		return errors.New("not found")
+	}
+	setting.PausedUntil, setting.PauseReason = until, reason
+	return nil
+}
+func (s *retryStore) DeleteCronSetting(string) error {
+	return errors.New("not implemented")
+}
+func (s *retryStore) GetLastCronRun(string) (*models.CronRunSummary, error) {
+	return nil, errors.New("not implemented")
+}
 func (s *retryStore) GetCronHistoryCount(string, *int, *time.Time, *time.Time) (int, error) {
 	return 0, errors.New("not implemented")
 }
@@ -65,10 +131,61 @@ func (s *retryStore) GetCollectSettings() (*store.CollectSettings, error) {
 func (s *retryStore) UpdateCollectSettings(*store.CollectSettings) error {
 	return errors.New("not implemented")
 }
+func (s *retryStore) GetCollectProfiles() ([]models.CollectProfile, error) {
+	return nil, errors.New("not implemented")
+}
+func (s *retryStore) GetCollectProfile(string) (*models.CollectProfile, error) { return nil, nil }
+func (s *retryStore) CreateCollectProfile(*models.CreateCollectProfileRequest) (*models.CollectProfile, error) {
+	return nil, errors.New("not implemented")
+}
+func (s *retryStore) UpdateCollectProfile(string, *models.UpdateCollectProfileRequest) (*models.CollectProfile, error) {
+	return nil, errors.New("not implemented")
+}
+func (s *retryStore) DeleteCollectProfile(string) error { return errors.New("not implemented") }
+func (s *retryStore) GetPromptVersions() ([]models.PromptVersion, error) {
+	return nil, errors.New("not implemented")
+}
+func (s *retryStore) GetPromptVersion(string) (*models.PromptVersion, error) { return nil, nil }
+func (s *retryStore) CreatePromptVersion(*models.CreatePromptVersionRequest) (*models.PromptVersion, error) {
+	return nil, errors.New("not implemented")
+}
+func (s *retryStore) DeletePromptVersion(string) error { return errors.New("not implemented") }
 func (s *retryStore) GetPromptSettings() (*models.PromptSettings, error) {
 	return nil, errors.New("not implemented")
 }
-func (s *retryStore) UpdatePromptSettings(*models.UpdatePromptSettingsRequest) error {
+func (s *retryStore) UpdatePromptSettings(*models.UpdatePromptSettingsRequest, string) error {
+	return errors.New("not implemented")
+}
+func (s *retryStore) GetPromptRevisionCount() (int, error) { return 0, errors.New("not implemented") }
+func (s *retryStore) GetPromptRevisions(int, int) ([]models.PromptRevision, error) {
+	return nil, errors.New("not implemented")
+}
+func (s *retryStore) GetPromptRevision(int64) (*models.PromptRevision, error) { return nil, nil }
+func (s *retryStore) RollbackPromptSettings(int64, string) (*models.PromptRevision, error) {
+	return nil, errors.New("not implemented")
+}
+func (s *retryStore) RecordAuditEntry(*models.AuditEntry) error {
+	return errors.New("not implemented")
+}
+func (s *retryStore) GetAuditLogCount(string, string, *time.Time, *time.Time) (int, error) {
+	return 0, errors.New("not implemented")
+}
+func (s *retryStore) GetAuditLog(string, string, int, int, *time.Time, *time.Time) ([]models.AuditEntry, error) {
+	return nil, errors.New("not implemented")
+}
+func (s *retryStore) GetAPITokens() ([]models.APIToken, error) {
+	return nil, errors.New("not implemented")
+}
+func (s *retryStore) GetAPIToken(string) (*models.APIToken, error) {
+	return nil, errors.New("not implemented")
+}
+func (s *retryStore) GetAPITokenByHash(string) (*models.APIToken, error) {
+	return nil, errors.New("not implemented")
+}
+func (s *retryStore) CreateAPIToken(*models.CreateAPITokenRequest, string) (*models.APIToken, error) {
+	return nil, errors.New("not implemented")
+}
+func (s *retryStore) DeleteAPIToken(string) error {
 	return errors.New("not implemented")
 }
 func (s *retryStore) GetAPIConfig(string) (*models.APIConfigModel, error) {
@@ -81,6 +198,84 @@ func (s *retryStore) UpdateAPIConfig(string, *models.UpdateAPIConfigRequest) (*m
 	return nil, errors.New("not implemented")
 }
 func (s *retryStore) DeleteAPIConfig(string) error { return errors.New("not implemented") }
+func (s *retryStore) RecordDeliveryAttempt(attempt *models.DeliveryAttempt) error {
+	s.attempts = append(s.attempts, *attempt)
+	key := attempt.URL + " " + attempt.APIName
+	if s.delivered == nil {
+		s.delivered = map[string]*models.Delivery{}
+	}
+	delivery, ok := s.delivered[key]
+	if !ok {
+		delivery = &models.Delivery{URL: attempt.URL, APIName: attempt.APIName}
+		s.delivered[key] = delivery
+	}
+	delivery.Attempts++
+	if attempt.Manual {
+		delivery.ManualAttempts++
+	}
+	switch {
+	case attempt.Success:
+		delivery.Status = models.DeliveryStatusDelivered
+		delivery.NextAttemptAt = nil
+	case delivery.Status != models.DeliveryStatusDelivered:
+		delivery.Status = models.DeliveryStatusFailed
+	}
+	return nil
+}
+func (s *retryStore) GetDelivery(url, apiName string) (*models.Delivery, error) {
+	return s.delivered[url+" "+apiName], nil
+}
+func (s *retryStore) GetDeliveryCount(string, string, string) (int, error) {
+	return 0, errors.New("not implemented")
+}
+func (s *retryStore) GetDeliveries(string, string, string, int, int) ([]models.Delivery, error) {
+	return nil, errors.New("not implemented")
+}
+func (s *retryStore) ScheduleDeliveryRetry(url, apiName string, at time.Time) error {
+	if s.queued == nil {
+		s.queued = map[string]time.Time{}
+	}
+	s.queued[url+" "+apiName] = at
+	if delivery, ok := s.delivered[url+" "+apiName]; ok {
+		delivery.NextAttemptAt = &at
+	}
+	return nil
+}
+func (s *retryStore) AbandonDelivery(url, apiName, reason string) error {
+	if s.abandoned == nil {
+		s.abandoned = map[string]string{}
+	}
+	s.abandoned[url+" "+apiName] = reason
+	if delivery, ok := s.delivered[url+" "+apiName]; ok {
+		delivery.Status = models.DeliveryStatusAbandoned
+		delivery.NextAttemptAt = nil
+	}
+	return nil
+}
+func (s *retryStore) GetDueDeliveryRetries(time.Time, int) ([]models.Delivery, error) {
+	return s.due, nil
+}
+func (s *retryStore) GetBlackoutWindows() ([]models.BlackoutWindow, error) {
+	return s.blackouts, nil
+}
+func (s *retryStore) GetBlackoutWindow(int64) (*models.BlackoutWindow, error) { return nil, nil }
+func (s *retryStore) CreateBlackoutWindow(*models.BlackoutWindowRequest) (*models.BlackoutWindow, error) {
+	return nil, errors.New("not implemented")
+}
+func (s *retryStore) UpdateBlackoutWindow(int64, *models.BlackoutWindowRequest) (*models.BlackoutWindow, error) {
+	return nil, errors.New("not implemented")
+}
+func (s *retryStore) DeleteBlackoutWindow(int64) error { return errors.New("not implemented") }
+func (s *retryStore) GetImageSettings() (*models.ImageSettings, error) {
+	if s.imageSettings == nil {
+		settings := models.DefaultImageSettings()
+		return &settings, nil
+	}
+	return s.imageSettings, nil
+}
+func (s *retryStore) UpdateImageSettings(*models.ImageOverrides) (*models.ImageSettings, error) {
+	return nil, errors.New("not implemented")
+}
 
 var _ store.StoreInterface = (*retryStore)(nil)
 
@@ -339,7 +534,7 @@ func TestRetryMessagePostMarksPostedOnlyWhenComplete(t *testing.T) {
 	}
 }
 
-// A retry keeps its image in a subdirectory of the image root, so the served
+// Cached images live in a subdirectory of the image root, so the served
 // path has to carry that subdirectory or the connector fetches a 404.
 func TestImageURLPath(t *testing.T) {
 	tests := []struct {
@@ -348,7 +543,7 @@ func TestImageURLPath(t *testing.T) {
 		want  string
 	}{
 		{name: "cron image at the root", image: imageDir + "/image_123.png", want: "image_123.png"},
-		{name: "retry image in a subdirectory", image: retryImageDir + "/retry_123.png", want: "retry/retry_123.png"},
+		{name: "cached image in a subdirectory", image: imageDir + "/cache/0a1b2c.png", want: "cache/0a1b2c.png"},
 		{name: "path outside the image root falls back to the file name", image: "/var/tmp/other.png", want: "other.png"},
 	}
 
@@ -361,6 +556,94 @@ func TestImageURLPath(t *testing.T) {
 	}
 }
 
+func TestBuildItemRequestSignsImageURL(t *testing.T) {
+	t.Setenv("PUBLIC_URL", "https://maestro.example.com")
+	t.Setenv("IMAGE_URL_SECRET", "test-secret")
+
+	endpoint := api.APIEndpoint{ContentType: "json", SocialifyImage: true}
+	item := repository.Item{URL: "https://github.com/think-root/content-maestro", Text: "text"}
+	req, err := buildItemRequest("threads", endpoint, item, imageDir+"/cache/abc.png")
+	if err != nil {
+		t.Fatalf("buildItemRequest() error = %v", err)
+	}
+
+	imageURL, _ := req.JSONBody["image_url"].(string)
+	parsed, err := url.Parse(imageURL)
+	if err != nil || parsed.Host != "maestro.example.com" || parsed.Path != "/images/cache/abc.png" {
+		t.Fatalf("image_url = %q, want a link to /images/cache/abc.png", imageURL)
+	}
+	query := parsed.Query()
+	if !imageurl.Verify("cache/abc.png", query.Get("expires"), query.Get("signature"), time.Now()) {
+		t.Errorf("image_url %q does not carry a valid signature", imageURL)
+	}
+}
+
+// Connectors share an image exactly when their overrides leave them with the
+// same settings.
+func TestImageKeyFollowsConnectorSettings(t *testing.T) {
+	global := models.DefaultImageSettings()
+	global.UpdatedAt = time.Now()
+	dark := "Dark"
+	light := "Light"
+	url := "https://github.com/think-root/content-maestro"
+
+	plain := imageKey(url, (*models.ImageOverrides)(nil).Apply(global))
+	if got := imageKey(url, (&models.ImageOverrides{Theme: &light}).Apply(global)); got != plain {
+		t.Error("an override matching the global settings should share the image")
+	}
+	if got := imageKey(url, (&models.ImageOverrides{Theme: &dark}).Apply(global)); got == plain {
+		t.Error("a different theme should get an image of its own")
+	}
+	if got := imageKey("https://github.com/think-root/content-alchemist", global); got == plain {
+		t.Error("a different repository should get an image of its own")
+	}
+
+	jpeg := models.ImageFormatJPEG
+	if ext := imageExtension((&models.ImageOverrides{Format: &jpeg}).Apply(global)); ext != "jpg" {
+		t.Errorf("imageExtension() = %q, want jpg", ext)
+	}
+}
+
+type failingProvider struct{ calls int }
+
+func (p *failingProvider) Name() string { return "failing" }
+
+func (p *failingProvider) Generate(string, string, models.ImageSettings) error {
+	p.calls++
+	return errors.New("provider unavailable")
+}
+
+type offlineTransport struct{}
+
+func (offlineTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("offline")
+}
+
+// A provider that keeps failing leaves the post with a locally rendered card,
+// not without an image.
+func TestGenerateImageFallsBackToLocal(t *testing.T) {
+	provider := &failingProvider{}
+	oldProviderFor := imageProviderFor
+	imageProviderFor = func(models.ImageSettings) (imageprovider.ImageProvider, error) { return provider, nil }
+	defer func() { imageProviderFor = oldProviderFor }()
+
+	oldClient := render.GitHubHTTPClient
+	render.GitHubHTTPClient = &http.Client{Transport: offlineTransport{}}
+	defer func() { render.GitHubHTTPClient = oldClient }()
+
+	path := filepath.Join(t.TempDir(), "image.png")
+	config := imageprovider.RetryConfig{MaxRetries: 2, RetryInterval: time.Millisecond}
+	if err := generateImage("think-root/content-maestro", path, models.DefaultImageSettings(), config); err != nil {
+		t.Fatalf("generateImage() error = %v", err)
+	}
+	if provider.calls != 2 {
+		t.Errorf("provider called %d times, want 2", provider.calls)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("fallback image was not written: %v", err)
+	}
+}
+
 func TestNormalizeAPINames(t *testing.T) {
 	got, err := normalizeAPINames([]string{" threads ", "threads", "bluesky", ""})
 	if err != nil {
@@ -377,3 +660,123 @@ func TestNormalizeAPINames(t *testing.T) {
 		}
 	}
 }
+
+func TestRetryMessagePostRendersBodyTemplate(t *testing.T) {
+	var connectorBody map[string]any
+	connector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&connectorBody)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer connector.Close()
+
+	alchemist := alchemistStub(t, true, nil)
+	defer alchemist.Close()
+	withRepositoryEndpoints(t, alchemist.URL)
+
+	st := &retryStore{configs: []models.APIConfigModel{{
+		Name: "discord", URL: connector.URL, Method: http.MethodPost,
+		ContentType: "json", SuccessCode: http.StatusNoContent, Enabled: true,
+		TextLanguage: "uk", DefaultJSONBody: `{"username": "content-maestro"}`,
+		BodyTemplate: `{"content": {{json .Item.Text}}, "embeds": [{"url": {{json .Item.URL}}, "footer": {"text": {{json .Language}}}}]}`,
+	}}}
+	if err := api.LoadAPIConfigs(st); err != nil {
+		t.Fatalf("LoadAPIConfigs() error = %v", err)
+	}
+
+	result, err := RetryMessagePost(st, []string{"discord"}, retryTestURL)
+	if err != nil {
+		t.Fatalf("RetryMessagePost() error = %v", err)
+	}
+	if len(result.Succeeded) != 1 {
+		t.Fatalf("result = %+v, want discord to succeed", result)
+	}
+
+	want := map[string]any{
+		"content":  "text for uk",
+		"username": "content-maestro",
+		"embeds": []any{map[string]any{
+			"url":    retryTestURL,
+			"footer": map[string]any{"text": "uk"},
+		}},
+	}
+	if !reflect.DeepEqual(connectorBody, want) {
+		t.Errorf("connector body = %#v, want %#v", connectorBody, want)
+	}
+}
+
+// A connector that answers 200 with an error in the body is a failure, in the
+// result and in the ledger alike.
+func TestRetryMessagePostAppliesSuccessRule(t *testing.T) {
+	connector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status":"error","message":"chat not found"}`))
+	}))
+	defer connector.Close()
+
+	alchemist := alchemistStub(t, true, nil)
+	defer alchemist.Close()
+	withRepositoryEndpoints(t, alchemist.URL)
+
+	st := &retryStore{configs: []models.APIConfigModel{{
+		Name: "telegram", URL: connector.URL, Method: http.MethodPost,
+		ContentType: "json", SuccessCode: http.StatusOK, Enabled: true,
+		TextLanguage: "uk", SuccessField: "status", SuccessValue: "ok",
+	}}}
+	if err := api.LoadAPIConfigs(st); err != nil {
+		t.Fatalf("LoadAPIConfigs() error = %v", err)
+	}
+
+	result, err := RetryMessagePost(st, []string{"telegram"}, retryTestURL)
+	if err != nil {
+		t.Fatalf("RetryMessagePost() error = %v", err)
+	}
+
+	if len(result.Failed) != 1 || len(result.Succeeded) != 0 {
+		t.Fatalf("result = %+v, want telegram to fail", result)
+	}
+	if !strings.Contains(result.Outcomes[0].Error, `"error"`) {
+		t.Errorf("outcome error = %q, want the rule's explanation", result.Outcomes[0].Error)
+	}
+	if len(st.attempts) != 1 || st.attempts[0].Success {
+		t.Errorf("attempts = %+v, want one failed attempt", st.attempts)
+	}
+}
+
+func TestPreviewRetrySendsNothing(t *testing.T) {
+	var connectorCalls int
+	connector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		connectorCalls++
+	}))
+	defer connector.Close()
+
+	alchemist := alchemistStub(t, false, nil)
+	defer alchemist.Close()
+	withRepositoryEndpoints(t, alchemist.URL)
+
+	st := &retryStore{configs: []models.APIConfigModel{{
+		Name: "threads", URL: connector.URL, Method: http.MethodPost,
+		ContentType: "json", SuccessCode: http.StatusOK, Enabled: true,
+		TextLanguage: "uk",
+	}}}
+	if err := api.LoadAPIConfigs(st); err != nil {
+		t.Fatalf("LoadAPIConfigs() error = %v", err)
+	}
+
+	preview, err := PreviewRetry(st, []string{"threads", "missing"}, retryTestURL)
+	if err != nil {
+		t.Fatalf("PreviewRetry() error = %v", err)
+	}
+
+	if len(preview.Connectors) != 2 {
+		t.Fatalf("connectors = %+v, want one per requested API", preview.Connectors)
+	}
+	if body, _ := preview.Connectors[0].Request.Body.(map[string]any); body["text"] != "text for uk" {
+		t.Errorf("threads body = %v, want the Ukrainian text", preview.Connectors[0].Request.Body)
+	}
+	if preview.Connectors[1].Error == "" {
+		t.Errorf("missing API preview = %+v, want an error", preview.Connectors[1])
+	}
+	if connectorCalls != 0 || len(st.attempts) != 0 || st.logCalls != 0 {
+		t.Errorf("connector calls = %d, attempts = %+v, log calls = %d, want none", connectorCalls, st.attempts, st.logCalls)
+	}
+}
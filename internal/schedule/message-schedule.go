@@ -2,21 +2,55 @@ package schedule
 
 import (
 	"content-maestro/internal/api"
+	"content-maestro/internal/imageprovider"
 	"content-maestro/internal/models"
 	"content-maestro/internal/notification"
 	"content-maestro/internal/repository"
-	"content-maestro/internal/socialify"
 	"content-maestro/internal/store"
-	"content-maestro/internal/utils"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/go-co-op/gocron"
 )
 
-func MessageJob(s *gocron.Scheduler, store store.StoreInterface) {
-	log.Debug("cron job started")
+func MessageJob(s *gocron.Scheduler, store store.StoreInterface, run models.JobRun) {
+	runMessageSchedule(s, store, "message", run)
+}
+
+// queueWindow is how far into the publication queue a schedule looks for an
+// item its connectors do not have yet.
+const queueWindow = 20
+
+// runMessageSchedule publishes the next item of the queue to the connectors of
+// the named message schedule. Each schedule has its own run lock and history, so
+// a Telegram-only and an X-only schedule run independently, and both publish the
+// same item: the queue is read through the delivery ledger, and an item only
+// leaves it once every schedule's connectors are done with it.
+func runMessageSchedule(s *gocron.Scheduler, store store.StoreInterface, name string, run models.JobRun) {
+	log.Debugf("%s cron job started", name)
+
+	run, finish, holder := beginRun(name, run)
+	if finish == nil {
+		skipRun(store, name, run, holder)
+		return
+	}
+	defer finish()
+
+	// Checked before anything is fetched, so a blacked-out run leaves the queue
+	// as it is. A manual run is a deliberate publish and goes ahead.
+	if !run.Manual {
+		if window := activeBlackout(store, time.Now()); window != nil {
+			blackoutRun(store, name, run, window)
+			return
+		}
+	}
+
+	// Waits out a retry in progress rather than skipping: a retry is short, and
+	// a schedule that publishes once a day should not lose its run to one.
+	publishMutex.RLock()
+	defer publishMutex.RUnlock()
 
 	var status int
 	var logMessage string
@@ -25,6 +59,7 @@ func MessageJob(s *gocron.Scheduler, store store.StoreInterface) {
 	var failedAPIs []string
 	var errorMessages []string
 	var updatedURL string
+	var posts []models.PublishedPost
 
 	// Assembled at exit rather than at the end of the publishing loop, so an
 	// early return - or a panic - still records which item the run consumed and
@@ -38,24 +73,33 @@ func MessageJob(s *gocron.Scheduler, store store.StoreInterface) {
 			URL:    updatedURL,
 			Sent:   successfulAPIs,
 			Failed: failedAPIs,
+			Posts:  posts,
+			Manual: run.Manual,
 		}
 	}
 
+	logRun := func(status int, output string) error {
+		return store.LogCronHistory(&models.CronHistory{
+			Name: name, Success: status, Output: output, Details: runDetails(),
+			RunID: run.ID, Manual: run.Manual, ScheduledAt: run.ScheduledAt, FiredAt: run.FiredAt,
+		})
+	}
+
 	defer func() {
 		if r := recover(); r != nil {
 			panicMessage := fmt.Sprintf("Panic occurred: %v. %s", r, logMessage)
 			log.Error("Message job panic: %v", r)
-			if err := store.LogCronExecutionDetails("message", 0, panicMessage, runDetails()); err != nil {
+			if err := logRun(0, panicMessage); err != nil {
 				log.Error("Failed to log panic execution: %v", err)
 			}
-			notification.NotifyCronResult("message", 0, panicMessage)
+			notification.NotifyCronResult(name, 0, panicMessage)
 			panic(r)
 		}
 
-		if err := store.LogCronExecutionDetails("message", status, logMessage, runDetails()); err != nil {
+		if err := logRun(status, logMessage); err != nil {
 			log.Error("Failed to log cron execution: %v", err)
 		}
-		notification.NotifyCronResult("message", status, logMessage)
+		notification.NotifyCronResult(name, status, logMessage)
 	}()
 
 	apiConfigs := api.GetAPIConfigs()
@@ -66,19 +110,85 @@ func MessageJob(s *gocron.Scheduler, store store.StoreInterface) {
 		return
 	}
 
-	var image_name string
+	setting, err := store.GetCronSetting(name)
+	if err != nil {
+		log.Errorf("Error getting %s cron setting: %v", name, err)
+		status = 0
+		logMessage = fmt.Sprintf("Error getting %s cron setting: %v", name, err)
+		return
+	}
+
+	// A schedule bound to no connectors - the message cron itself - publishes to
+	// every enabled one.
+	var bound map[string]bool
+	if setting != nil && len(setting.APIs) > 0 {
+		bound = make(map[string]bool, len(setting.APIs))
+		for _, apiName := range setting.APIs {
+			bound[apiName] = true
+			if _, ok := apiConfigs.APIs[apiName]; !ok {
+				failedAPIs = append(failedAPIs, apiName)
+				errorMessages = append(errorMessages, fmt.Sprintf("%s API error: not configured", apiName))
+			}
+		}
+	}
+	selected := func(apiName string, endpoint api.APIEndpoint) bool {
+		return endpoint.Enabled && (bound == nil || bound[apiName])
+	}
+
+	var connectors []string
+	for apiName, endpoint := range apiConfigs.APIs {
+		if selected(apiName, endpoint) {
+			connectors = append(connectors, apiName)
+		}
+	}
+	required := publishingConnectors(store, apiConfigs, connectors)
+
+	// nextItem picks the item for a connector language and lets go of the
+	// items passed over that every connector is done with. When that empties a
+	// whole window, the queue is read again rather than reported empty. The
+	// pick is kept for the run: once the first connector has the item, the
+	// ledger would steer the others past it.
+	picked := map[string]*repository.Item{}
+	nextItem := func(textLanguage string) (*repository.Item, error) {
+		for {
+			item, passed, err := nextQueueItem(store, textLanguage, connectors)
+			released := 0
+			for _, url := range passed {
+				if receivedAll(store, url, required, nil) && markPosted(url) {
+					released++
+				}
+			}
+			if item != nil || err != nil || len(passed) < queueWindow || released == 0 {
+				if err == nil {
+					picked[textLanguage] = item
+				}
+				return item, err
+			}
+		}
+	}
+	itemFor := func(textLanguage string) (*repository.Item, error) {
+		if item, ok := picked[textLanguage]; ok {
+			return item, nil
+		}
+		return nextItem(textLanguage)
+	}
+
+	// Images by connector. Connectors with the same image settings share one
+	// cache entry.
+	images := map[string]string{}
 
 	needsImage := false
-	for _, endpoint := range apiConfigs.APIs {
-		if endpoint.Enabled && endpoint.SocialifyImage {
+	for apiName, endpoint := range apiConfigs.APIs {
+		if selected(apiName, endpoint) && endpoint.SocialifyImage {
 			needsImage = true
 			break
 		}
 	}
 
 	if needsImage {
-		for _, endpoint := range apiConfigs.APIs {
-			if !endpoint.Enabled || !endpoint.SocialifyImage {
+		var repoURL string
+		for apiName, endpoint := range apiConfigs.APIs {
+			if !selected(apiName, endpoint) || !endpoint.SocialifyImage {
 				continue
 			}
 
@@ -87,40 +197,40 @@ func MessageJob(s *gocron.Scheduler, store store.StoreInterface) {
 				textLanguage = "en"
 			}
 
-			repo, err := repository.GetRepository(1, false, "ASC", "publication_queue", textLanguage)
+			item, err := itemFor(textLanguage)
 			if err != nil {
 				log.Error("Error getting repository for language %s: %v", textLanguage, err)
 				continue
 			}
 
-			if len(repo.Data.Items) == 0 {
+			if item == nil {
 				log.Debugf("No items found in repository for language %s", textLanguage)
 				continue
 			}
 
-			item := repo.Data.Items[0]
-			username_repo := strings.TrimPrefix(item.URL, "https://github.com/")
-			timestamp := time.Now().UnixNano()
-			imageFilename := fmt.Sprintf("image_%d.png", timestamp)
-			image_name = fmt.Sprintf("%s/%s", imageDir, imageFilename)
+			repoURL = item.URL
+			break
+		}
+
+		globalSettings := globalImageSettings(store)
+		for apiName, endpoint := range apiConfigs.APIs {
+			if repoURL == "" || !selected(apiName, endpoint) || !endpoint.SocialifyImage {
+				continue
+			}
 
-			err = socialify.Socialify(username_repo, image_name)
+			image_name, err := cachedImage(repoURL, endpoint.ImageSettings.Apply(globalSettings), imageprovider.DefaultRetryConfig)
 			if err != nil {
 				log.Error(err)
-				err := utils.CopyFile("./assets/banner.jpg", image_name)
-				if err != nil {
-					log.Error("Failed to copy file: %v", err)
-					status = 0
-					logMessage = fmt.Sprintf("Failed to copy fallback banner file: %v", err)
-					return
-				}
+				status = 0
+				logMessage = fmt.Sprintf("Failed to generate image: %v", err)
+				return
 			}
-			break
+			images[apiName] = image_name
 		}
 	}
 
 	for apiName, endpoint := range apiConfigs.APIs {
-		if !endpoint.Enabled {
+		if !selected(apiName, endpoint) {
 			continue
 		}
 
@@ -129,7 +239,7 @@ func MessageJob(s *gocron.Scheduler, store store.StoreInterface) {
 			textLanguage = "en"
 		}
 
-		repo, err := repository.GetRepository(1, false, "ASC", "publication_queue", textLanguage)
+		queued, err := itemFor(textLanguage)
 		if err != nil {
 			log.Error("Error getting repository for %s API with language %s: %v", apiName, textLanguage, err)
 			failedAPIs = append(failedAPIs, apiName)
@@ -137,14 +247,14 @@ func MessageJob(s *gocron.Scheduler, store store.StoreInterface) {
 			continue
 		}
 
-		if len(repo.Data.Items) == 0 {
+		if queued == nil {
 			log.Debugf("No items found in repository for %s API with language %s", apiName, textLanguage)
 			failedAPIs = append(failedAPIs, apiName)
 			errorMessages = append(errorMessages, fmt.Sprintf("%s API error: no items for language %s", apiName, textLanguage))
 			continue
 		}
 
-		item := repo.Data.Items[0]
+		item := *queued
 
 		// Repositories whose URL no longer resolves are dropped and the next
 		// candidate is fetched. The loop reports its outcome through this flag:
@@ -171,7 +281,7 @@ func MessageJob(s *gocron.Scheduler, store store.StoreInterface) {
 				log.Error("Error deleting repository %s: %v", item.URL, err)
 			}
 
-			nextRepo, err := repository.GetRepository(1, false, "ASC", "publication_queue", textLanguage)
+			next, err := nextItem(textLanguage)
 			if err != nil {
 				log.Error("Error getting next repository for %s API: %v", apiName, err)
 				failedAPIs = append(failedAPIs, apiName)
@@ -180,7 +290,7 @@ func MessageJob(s *gocron.Scheduler, store store.StoreInterface) {
 				break
 			}
 
-			if len(nextRepo.Data.Items) == 0 {
+			if next == nil {
 				log.Debugf("No more valid repositories available for %s API", apiName)
 				failedAPIs = append(failedAPIs, apiName)
 				errorMessages = append(errorMessages, fmt.Sprintf("%s API error: no valid repositories available", apiName))
@@ -188,7 +298,7 @@ func MessageJob(s *gocron.Scheduler, store store.StoreInterface) {
 				break
 			}
 
-			item = nextRepo.Data.Items[0]
+			item = *next
 		}
 
 		if !itemAvailable {
@@ -199,7 +309,7 @@ func MessageJob(s *gocron.Scheduler, store store.StoreInterface) {
 			updatedURL = item.URL
 		}
 
-		resp, err := publishItem(apiName, endpoint, item, image_name)
+		resp, err := publishItem(store, apiName, endpoint, item, images[apiName], false)
 		if err != nil {
 			log.Errorf("%s API error: %v", apiName, err)
 			failedAPIs = append(failedAPIs, apiName)
@@ -207,14 +317,27 @@ func MessageJob(s *gocron.Scheduler, store store.StoreInterface) {
 		} else if resp.Success {
 			log.Debugf("%s post created successfully with language %s!", apiName, textLanguage)
 			successfulAPIs = append(successfulAPIs, apiName)
+			if post, ok := publishedPost(apiName, resp); ok {
+				posts = append(posts, post)
+			}
 		} else {
 			log.Errorf("%s API request failed (status %d): %s", apiName, resp.StatusCode, string(resp.Body))
 			failedAPIs = append(failedAPIs, apiName)
-			errorMessages = append(errorMessages, fmt.Sprintf("%s API failed (status %d)", apiName, resp.StatusCode))
+			message := fmt.Sprintf("%s API failed (status %d)", apiName, resp.StatusCode)
+			if resp.Error != "" {
+				message += ": " + resp.Error
+			}
+			errorMessages = append(errorMessages, message)
+		}
+
+		if err != nil || !resp.Success {
+			queueDeliveryRetry(store, item.URL, apiName, endpoint)
 		}
 	}
 
-	if len(successfulAPIs) > 0 && updatedURL != "" {
+	// The item stays queued for the schedules whose connectors do not have it
+	// yet; the last of them takes it off.
+	if len(successfulAPIs) > 0 && updatedURL != "" && receivedAll(store, updatedURL, required, successfulAPIs) {
 		if _, err := repository.UpdateRepositoryPosted(updatedURL, true); err != nil {
 			log.Error("Error updating repository posted status: %v", err)
 			status = 0
@@ -223,14 +346,6 @@ func MessageJob(s *gocron.Scheduler, store store.StoreInterface) {
 		}
 	}
 
-	err := utils.RemoveAllFilesInFolder(imageDir)
-	if err != nil {
-		log.Error(err)
-		status = 0
-		logMessage = fmt.Sprintf("Error cleaning up temporary files: %v", err)
-		return
-	}
-
 	if len(successfulAPIs) == 0 {
 		status = 0
 		logMessage = fmt.Sprintf("No messages sent successfully. Errors: %s", strings.Join(errorMessages, "; "))
@@ -247,18 +362,100 @@ func MessageJob(s *gocron.Scheduler, store store.StoreInterface) {
 	}
 }
 
-func MessageCron(store store.StoreInterface) *gocron.Scheduler {
-	s := gocron.NewScheduler(time.UTC)
+// nextQueueItem returns the oldest item of the publication queue, in
+// textLanguage, that none of connectors has received or given up on, or nil if
+// there is none. passed lists the queued items it skipped because one of them
+// had. An item that failed with no retry pending is picked again, so the next
+// run retries it.
+func nextQueueItem(st store.StoreInterface, textLanguage string, connectors []string) (item *repository.Item, passed []string, err error) {
+	repo, err := repository.GetRepository(queueWindow, false, "ASC", "publication_queue", textLanguage)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := range repo.Data.Items {
+		candidate := &repo.Data.Items[i]
+		if !slices.ContainsFunc(connectors, func(apiName string) bool { return handled(st, candidate.URL, apiName) }) {
+			return candidate, passed, nil
+		}
+		passed = append(passed, candidate.URL)
+	}
+	return nil, passed, nil
+}
+
+// publishingConnectors lists the connectors an item has to reach before it
+// leaves the queue: those of every active message schedule, and own, those of
+// the schedule that is running.
+func publishingConnectors(st store.StoreInterface, apiConfigs *api.APIConfig, own []string) []string {
+	connectors := slices.Clone(own)
+
+	settings, err := st.GetAllCronSettings()
+	if err != nil {
+		log.Errorf("Failed to load message schedules: %v", err)
+		return connectors
+	}
+	for _, setting := range settings {
+		if setting.Job != models.JobMessage || !setting.IsActive {
+			continue
+		}
+		for apiName, endpoint := range apiConfigs.APIs {
+			if endpoint.Enabled && (len(setting.APIs) == 0 || slices.Contains(setting.APIs, apiName)) && !slices.Contains(connectors, apiName) {
+				connectors = append(connectors, apiName)
+			}
+		}
+	}
+	return connectors
+}
+
+// receivedAll reports whether every one of connectors is done with url, by the
+// ledger or because it is among sent. A connector is done once it has the item,
+// gave up on it, or failed it with no retry left to wait for: one failing
+// connector must not keep the item queued, and with it the queue, forever.
+func receivedAll(st store.StoreInterface, url string, connectors, sent []string) bool {
+	for _, apiName := range connectors {
+		if slices.Contains(sent, apiName) {
+			continue
+		}
+		delivery := ledgerEntry(st, url, apiName)
+		if delivery == nil {
+			return false
+		}
+		switch delivery.Status {
+		case models.DeliveryStatusDelivered, models.DeliveryStatusAbandoned:
+		case models.DeliveryStatusFailed:
+			if delivery.NextAttemptAt != nil {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// handled reports whether the ledger has url delivered to apiName or given up
+// on for it.
+func handled(st store.StoreInterface, url, apiName string) bool {
+	delivery := ledgerEntry(st, url, apiName)
+	return delivery != nil && (delivery.Status == models.DeliveryStatusDelivered || delivery.Status == models.DeliveryStatusAbandoned)
+}
 
-	setting, err := store.GetCronSetting("message")
-	if err != nil || setting == nil || !setting.IsActive {
-		log.Debug("Message cron is disabled")
-		return s
+// ledgerEntry returns the ledger entry of url for apiName. A ledger that cannot
+// be read counts as having none.
+func ledgerEntry(st store.StoreInterface, url, apiName string) *models.Delivery {
+	delivery, err := st.GetDelivery(url, apiName)
+	if err != nil {
+		log.Errorf("Failed to read delivery ledger for %s and %s API: %v", url, apiName, err)
+		return nil
 	}
+	return delivery
+}
 
-	log.Debugf("Message cron is enabled with schedule: %s", setting.Schedule)
-	s.Cron(setting.Schedule).Do(MessageJob, s, store)
-	s.StartAsync()
-	log.Debug("scheduler started successfully")
-	return s
+// markPosted takes url off the queue and reports whether that succeeded.
+func markPosted(url string) bool {
+	if _, err := repository.UpdateRepositoryPosted(url, true); err != nil {
+		log.Errorf("Error updating repository posted status of %s: %v", url, err)
+		return false
+	}
+	return true
 }
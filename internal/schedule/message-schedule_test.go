@@ -4,11 +4,14 @@ import (
 	"content-maestro/internal/api"
 	"content-maestro/internal/models"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 )
 
 // withMessageJobWorkdir gives the job an empty working directory containing the
@@ -100,7 +103,7 @@ func TestMessageJobSurvivesRejectedRefetch(t *testing.T) {
 		}
 	}()
 
-	MessageJob(nil, st)
+	MessageJob(nil, st, models.JobRun{})
 
 	if st.logCalls != 1 {
 		t.Fatalf("cron history writes = %d, want 1", st.logCalls)
@@ -130,7 +133,7 @@ func TestMessageJobRecordsNoDetailsWithoutConfigs(t *testing.T) {
 		t.Fatalf("LoadAPIConfigs() error = %v", err)
 	}
 
-	MessageJob(nil, st)
+	MessageJob(nil, st, models.JobRun{})
 
 	if st.logCalls != 1 {
 		t.Fatalf("cron history writes = %d, want 1", st.logCalls)
@@ -160,7 +163,7 @@ func TestMessageJobRecordsDetailsOnSuccess(t *testing.T) {
 		t.Fatalf("LoadAPIConfigs() error = %v", err)
 	}
 
-	MessageJob(nil, st)
+	MessageJob(nil, st, models.JobRun{})
 
 	if st.loggedStatus != 1 {
 		t.Fatalf("status = %d, want 1 (output: %s)", st.loggedStatus, st.loggedOutput)
@@ -178,3 +181,585 @@ func TestMessageJobRecordsDetailsOnSuccess(t *testing.T) {
 		t.Error("details manual = true, want false for a scheduled run")
 	}
 }
+
+func TestMessageJobRecordsDeliveryAttempts(t *testing.T) {
+	stub := &queueStub{repositoryPath: "/live/repo", validationCode: http.StatusOK}
+	server := httptest.NewServer(stub.handler(t))
+	defer server.Close()
+	repoURL := server.URL + stub.repositoryPath
+	stub.repositoryURL = repoURL
+	withMessageJobWorkdir(t)
+
+	t.Setenv("CONTENT_ALCHEMIST_URL", server.URL)
+	t.Setenv("CONTENT_ALCHEMIST_BEARER", "test-token")
+
+	st := &retryStore{configs: []models.APIConfigModel{
+		{
+			Name: "threads", URL: server.URL, Method: http.MethodPost,
+			ContentType: "json", SuccessCode: http.StatusOK, Enabled: true,
+			TextLanguage: "en",
+		},
+		{
+			Name: "bluesky", URL: "http://127.0.0.1:1", Method: http.MethodPost,
+			ContentType: "json", SuccessCode: http.StatusOK, Enabled: true,
+			TextLanguage: "en", Timeout: 1,
+		},
+	}}
+	if err := api.LoadAPIConfigs(st); err != nil {
+		t.Fatalf("LoadAPIConfigs() error = %v", err)
+	}
+
+	MessageJob(nil, st, models.JobRun{})
+
+	outcomes := map[string]models.DeliveryAttempt{}
+	for _, attempt := range st.attempts {
+		if attempt.URL != repoURL {
+			t.Errorf("attempt url = %q, want %q", attempt.URL, repoURL)
+		}
+		outcomes[attempt.APIName] = attempt
+	}
+	if len(outcomes) != 2 {
+		t.Fatalf("attempts = %+v, want one per connector", st.attempts)
+	}
+	if !outcomes["threads"].Success {
+		t.Errorf("threads attempt = %+v, want success", outcomes["threads"])
+	}
+	if outcomes["bluesky"].Success || outcomes["bluesky"].Error == "" {
+		t.Errorf("bluesky attempt = %+v, want a failure with its reason", outcomes["bluesky"])
+	}
+}
+
+func TestMessageScheduleJobPublishesToBoundConnectors(t *testing.T) {
+	stub := &queueStub{repositoryPath: "/live/repo", validationCode: http.StatusOK}
+	server := httptest.NewServer(stub.handler(t))
+	defer server.Close()
+	stub.repositoryURL = server.URL + stub.repositoryPath
+	withMessageJobWorkdir(t)
+
+	t.Setenv("CONTENT_ALCHEMIST_URL", server.URL)
+	t.Setenv("CONTENT_ALCHEMIST_BEARER", "test-token")
+
+	st := &retryStore{
+		configs: []models.APIConfigModel{
+			{
+				Name: "threads", URL: server.URL, Method: http.MethodPost,
+				ContentType: "json", SuccessCode: http.StatusOK, Enabled: true,
+				TextLanguage: "en",
+			},
+			{
+				Name: "bluesky", URL: server.URL, Method: http.MethodPost,
+				ContentType: "json", SuccessCode: http.StatusOK, Enabled: true,
+				TextLanguage: "en",
+			},
+		},
+		cronSettings: map[string]*models.CronSetting{
+			"threads-evening": {Name: "threads-evening", Job: models.JobMessage, APIs: []string{"threads", "removed"}},
+		},
+	}
+	if err := api.LoadAPIConfigs(st); err != nil {
+		t.Fatalf("LoadAPIConfigs() error = %v", err)
+	}
+
+	MessageScheduleJob(st, "threads-evening")(nil, models.JobRun{})
+
+	if len(st.attempts) != 1 || st.attempts[0].APIName != "threads" {
+		t.Fatalf("attempts = %+v, want one to threads", st.attempts)
+	}
+	if st.loggedName != "threads-evening" {
+		t.Errorf("history name = %q, want threads-evening", st.loggedName)
+	}
+	// A connector deleted after the schedule was saved is reported, not ignored.
+	if st.loggedStatus != models.CronStatusPartial || st.loggedDetails == nil ||
+		strings.Join(st.loggedDetails.Failed, ",") != "removed" {
+		t.Errorf("status = %d, details = %+v, want partial with removed failed", st.loggedStatus, st.loggedDetails)
+	}
+}
+
+// orderedQueue serves a publication queue of several items, up to the requested
+// limit, and takes an item off when it is marked posted, recording the order it
+// happened in.
+type orderedQueue struct {
+	items  []string
+	posted []string
+}
+
+func (q *orderedQueue) handler(t *testing.T) http.HandlerFunc {
+	t.Helper()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodHead:
+			w.WriteHeader(http.StatusOK)
+			return
+		case http.MethodPatch:
+			var body struct {
+				URL string `json:"url"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			q.posted = append(q.posted, body.URL)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"status":"ok","message":"done"}`))
+			return
+		}
+
+		var request struct {
+			Limit int `json:"limit"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+
+		var items []map[string]any
+		for i, url := range q.items {
+			if len(items) == request.Limit {
+				break
+			}
+			if !slices.Contains(q.posted, url) {
+				items = append(items, map[string]any{"id": i + 1, "posted": false, "url": url, "text": "text"})
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"status": "ok", "data": map[string]any{"items": items}})
+	}
+}
+
+// connectorStub records the url of every item a connector was sent.
+func connectorStub(t *testing.T, urls *[]string) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		url, _ := body["url"].(string)
+		*urls = append(*urls, url)
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+// An item that stayed in the queue - because marking it posted failed - must not
+// be posted again to a connector that already has it. The run passes it over,
+// takes it off the queue and publishes the next one.
+func TestMessageJobSkipsDeliveredConnectors(t *testing.T) {
+	queue := &orderedQueue{}
+	server := httptest.NewServer(queue.handler(t))
+	defer server.Close()
+	delivered, next := server.URL+"/delivered/repo", server.URL+"/next/repo"
+	queue.items = []string{delivered, next}
+	withMessageJobWorkdir(t)
+
+	t.Setenv("CONTENT_ALCHEMIST_URL", server.URL)
+	t.Setenv("CONTENT_ALCHEMIST_BEARER", "test-token")
+
+	var sent []string
+	connector := connectorStub(t, &sent)
+	defer connector.Close()
+
+	st := &retryStore{
+		configs: []models.APIConfigModel{{
+			Name: "threads", URL: connector.URL, Method: http.MethodPost,
+			ContentType: "json", SuccessCode: http.StatusOK, Enabled: true,
+			TextLanguage: "en",
+		}},
+		delivered: map[string]*models.Delivery{
+			delivered + " threads": {URL: delivered, APIName: "threads", Status: models.DeliveryStatusDelivered, Attempts: 1},
+		},
+	}
+	if err := api.LoadAPIConfigs(st); err != nil {
+		t.Fatalf("LoadAPIConfigs() error = %v", err)
+	}
+
+	MessageJob(nil, st, models.JobRun{})
+
+	if !slices.Equal(sent, []string{next}) {
+		t.Errorf("sent = %v, want only %s", sent, next)
+	}
+	if !slices.Equal(queue.posted, []string{delivered, next}) {
+		t.Errorf("posted = %v, want the delivered item taken off, then the published one", queue.posted)
+	}
+	if st.loggedStatus != 1 || st.loggedDetails == nil || st.loggedDetails.URL != next {
+		t.Errorf("status = %d, details = %+v, want %s published (output: %s)", st.loggedStatus, st.loggedDetails, next, st.loggedOutput)
+	}
+}
+
+// Schedules bound to different connectors publish the same item at their own
+// times; the item only leaves the queue once the last of them has published it.
+func TestMessageSchedulesPublishSameItem(t *testing.T) {
+	queue := &orderedQueue{}
+	server := httptest.NewServer(queue.handler(t))
+	defer server.Close()
+	first, second := server.URL+"/first/repo", server.URL+"/second/repo"
+	queue.items = []string{first, second}
+	withMessageJobWorkdir(t)
+
+	t.Setenv("CONTENT_ALCHEMIST_URL", server.URL)
+	t.Setenv("CONTENT_ALCHEMIST_BEARER", "test-token")
+
+	var telegramSent, xSent []string
+	telegram := connectorStub(t, &telegramSent)
+	defer telegram.Close()
+	x := connectorStub(t, &xSent)
+	defer x.Close()
+
+	st := &retryStore{
+		configs: []models.APIConfigModel{
+			{
+				Name: "telegram", URL: telegram.URL, Method: http.MethodPost,
+				ContentType: "json", SuccessCode: http.StatusOK, Enabled: true,
+				TextLanguage: "en",
+			},
+			{
+				Name: "x", URL: x.URL, Method: http.MethodPost,
+				ContentType: "json", SuccessCode: http.StatusOK, Enabled: true,
+				TextLanguage: "en",
+			},
+		},
+		cronSettings: map[string]*models.CronSetting{
+			"telegram-morning": {Name: "telegram-morning", Job: models.JobMessage, IsActive: true, APIs: []string{"telegram"}},
+			"x-evening":        {Name: "x-evening", Job: models.JobMessage, IsActive: true, APIs: []string{"x"}},
+		},
+	}
+	if err := api.LoadAPIConfigs(st); err != nil {
+		t.Fatalf("LoadAPIConfigs() error = %v", err)
+	}
+
+	MessageScheduleJob(st, "telegram-morning")(nil, models.JobRun{})
+
+	if !slices.Equal(telegramSent, []string{first}) {
+		t.Fatalf("telegram sent = %v, want %s", telegramSent, first)
+	}
+	if len(queue.posted) != 0 {
+		t.Fatalf("posted = %v, want the item kept queued until x has it", queue.posted)
+	}
+
+	MessageScheduleJob(st, "x-evening")(nil, models.JobRun{})
+
+	if !slices.Equal(xSent, []string{first}) {
+		t.Fatalf("x sent = %v, want the same item as telegram, %s", xSent, first)
+	}
+	if !slices.Equal(queue.posted, []string{first}) {
+		t.Errorf("posted = %v, want %s taken off once both schedules published it", queue.posted, first)
+	}
+}
+
+// A named schedule holds its own run lock, not the message job's, so a retry of
+// the item it is about to publish would otherwise go out alongside it.
+func TestMessageScheduleWaitsForRetry(t *testing.T) {
+	queue := &orderedQueue{}
+	server := httptest.NewServer(queue.handler(t))
+	defer server.Close()
+	item := server.URL + "/retried/repo"
+	queue.items = []string{item}
+	withMessageJobWorkdir(t)
+
+	t.Setenv("CONTENT_ALCHEMIST_URL", server.URL)
+	t.Setenv("CONTENT_ALCHEMIST_BEARER", "test-token")
+
+	var sent []string
+	connector := connectorStub(t, &sent)
+	defer connector.Close()
+
+	st := &retryStore{
+		configs: []models.APIConfigModel{{
+			Name: "telegram", URL: connector.URL, Method: http.MethodPost,
+			ContentType: "json", SuccessCode: http.StatusOK, Enabled: true,
+			TextLanguage: "en",
+		}},
+		cronSettings: map[string]*models.CronSetting{
+			"telegram-morning": {Name: "telegram-morning", Job: models.JobMessage, IsActive: true, APIs: []string{"telegram"}},
+		},
+	}
+	if err := api.LoadAPIConfigs(st); err != nil {
+		t.Fatalf("LoadAPIConfigs() error = %v", err)
+	}
+
+	// A retry in progress.
+	publishMutex.Lock()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		MessageScheduleJob(st, "telegram-morning")(nil, models.JobRun{})
+	}()
+
+	select {
+	case <-done:
+		publishMutex.Unlock()
+		t.Fatal("schedule run finished while a retry held the publish lock")
+	case <-time.After(100 * time.Millisecond):
+	}
+	publishMutex.Unlock()
+	<-done
+
+	if !slices.Equal(sent, []string{item}) {
+		t.Errorf("sent = %v, want %s once the retry finished", sent, item)
+	}
+}
+
+// failingQueueSetup serves a queue of n items to two connectors: threads, which
+// takes every item, and x, which rejects every item and has no retries.
+func failingQueueSetup(t *testing.T, n int) (queue *orderedQueue, st *retryStore, threadsSent *[]string) {
+	t.Helper()
+
+	queue = &orderedQueue{}
+	server := httptest.NewServer(queue.handler(t))
+	t.Cleanup(server.Close)
+	for i := range n {
+		queue.items = append(queue.items, fmt.Sprintf("%s/repo/%d", server.URL, i))
+	}
+	withMessageJobWorkdir(t)
+
+	t.Setenv("CONTENT_ALCHEMIST_URL", server.URL)
+	t.Setenv("CONTENT_ALCHEMIST_BEARER", "test-token")
+
+	threadsSent = &[]string{}
+	threads := connectorStub(t, threadsSent)
+	t.Cleanup(threads.Close)
+	x := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	t.Cleanup(x.Close)
+
+	st = &retryStore{
+		configs: []models.APIConfigModel{
+			{
+				Name: "threads", URL: threads.URL, Method: http.MethodPost,
+				ContentType: "json", SuccessCode: http.StatusOK, Enabled: true,
+				TextLanguage: "en",
+			},
+			{
+				Name: "x", URL: x.URL, Method: http.MethodPost,
+				ContentType: "json", SuccessCode: http.StatusOK, Enabled: true,
+				TextLanguage: "en",
+			},
+		},
+		delivered: map[string]*models.Delivery{},
+	}
+	if err := api.LoadAPIConfigs(st); err != nil {
+		t.Fatalf("LoadAPIConfigs() error = %v", err)
+	}
+	return queue, st, threadsSent
+}
+
+// A connector that fails for good - its delivery abandoned, or failed with no
+// retries to wait for - must not keep items queued: they would fill the window
+// the queue is read through and stop publishing for every connector.
+func TestMessageJobMovesPastFailingConnector(t *testing.T) {
+	queue, st, threadsSent := failingQueueSetup(t, queueWindow+2)
+	abandoned := queue.items[0]
+	st.delivered[abandoned+" threads"] = &models.Delivery{URL: abandoned, APIName: "threads", Status: models.DeliveryStatusDelivered, Attempts: 1}
+	st.delivered[abandoned+" x"] = &models.Delivery{URL: abandoned, APIName: "x", Status: models.DeliveryStatusAbandoned, Attempts: 4}
+
+	for range queueWindow + 1 {
+		MessageJob(nil, st, models.JobRun{})
+	}
+
+	if !slices.Equal(*threadsSent, queue.items[1:]) {
+		t.Errorf("threads sent = %v, want every item after the abandoned one, in order", *threadsSent)
+	}
+	if !slices.Equal(queue.posted, queue.items) {
+		t.Errorf("posted = %v, want every item taken off the queue", queue.posted)
+	}
+}
+
+// A window made up entirely of items the connectors are done with is taken off
+// and the queue read again, rather than the run finding nothing to publish.
+func TestMessageJobReadsPastSettledWindow(t *testing.T) {
+	queue, st, threadsSent := failingQueueSetup(t, queueWindow+1)
+	for _, url := range queue.items[:queueWindow] {
+		st.delivered[url+" threads"] = &models.Delivery{URL: url, APIName: "threads", Status: models.DeliveryStatusDelivered, Attempts: 1}
+		st.delivered[url+" x"] = &models.Delivery{URL: url, APIName: "x", Status: models.DeliveryStatusAbandoned, Attempts: 4}
+	}
+
+	MessageJob(nil, st, models.JobRun{})
+
+	next := queue.items[queueWindow]
+	if !slices.Equal(*threadsSent, []string{next}) {
+		t.Errorf("threads sent = %v, want %s", *threadsSent, next)
+	}
+	if !slices.Equal(queue.posted, queue.items) {
+		t.Errorf("posted = %v, want the settled window and %s taken off", queue.posted, next)
+	}
+}
+
+func TestMessageJobRecordsPostReferences(t *testing.T) {
+	stub := &queueStub{repositoryPath: "/live/repo", validationCode: http.StatusOK}
+	server := httptest.NewServer(stub.handler(t))
+	defer server.Close()
+	repoURL := server.URL + stub.repositoryPath
+	stub.repositoryURL = repoURL
+	withMessageJobWorkdir(t)
+
+	t.Setenv("CONTENT_ALCHEMIST_URL", server.URL)
+	t.Setenv("CONTENT_ALCHEMIST_BEARER", "test-token")
+
+	connector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id":"3kx2","url":"https://bsky.app/profile/think-root/post/3kx2"}`))
+	}))
+	defer connector.Close()
+
+	st := &retryStore{configs: []models.APIConfigModel{{
+		Name: "bluesky", URL: connector.URL, Method: http.MethodPost,
+		ContentType: "json", SuccessCode: http.StatusOK, Enabled: true,
+		TextLanguage: "en", PostIDField: "id", PostURLField: "url",
+	}}}
+	if err := api.LoadAPIConfigs(st); err != nil {
+		t.Fatalf("LoadAPIConfigs() error = %v", err)
+	}
+
+	MessageJob(nil, st, models.JobRun{})
+
+	want := models.PublishedPost{APIName: "bluesky", PostID: "3kx2", PostURL: "https://bsky.app/profile/think-root/post/3kx2"}
+	if st.loggedDetails == nil || len(st.loggedDetails.Posts) != 1 || st.loggedDetails.Posts[0] != want {
+		t.Fatalf("logged details = %+v, want posts [%+v]", st.loggedDetails, want)
+	}
+	if len(st.attempts) != 1 || st.attempts[0].PostURL != want.PostURL {
+		t.Errorf("attempts = %+v, want the permalink in the ledger", st.attempts)
+	}
+}
+
+// A preview resolves the same item and builds the same request as a run, but
+// must leave no trace: no connector call, no ledger entry, no history row and no
+// change to the queue.
+func TestPreviewMessageSendsNothing(t *testing.T) {
+	stub := &queueStub{repositoryPath: "/live/repo", validationCode: http.StatusOK}
+	var queueChanges int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch || r.Method == http.MethodDelete {
+			queueChanges++
+		}
+		stub.handler(t)(w, r)
+	}))
+	defer server.Close()
+	repoURL := server.URL + stub.repositoryPath
+	stub.repositoryURL = repoURL
+	withMessageJobWorkdir(t)
+
+	t.Setenv("CONTENT_ALCHEMIST_URL", server.URL)
+	t.Setenv("CONTENT_ALCHEMIST_BEARER", "test-token")
+	t.Setenv("THREADS_TOKEN", "secret")
+
+	var connectorCalls int
+	connector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		connectorCalls++
+	}))
+	defer connector.Close()
+
+	st := &retryStore{configs: []models.APIConfigModel{
+		{
+			Name: "threads", URL: connector.URL, Method: http.MethodPost,
+			AuthType: "bearer", TokenEnvVar: "THREADS_TOKEN",
+			ContentType: "json", SuccessCode: http.StatusOK, Enabled: true,
+			TextLanguage: "en",
+		},
+		{
+			Name: "bluesky", URL: connector.URL, Method: http.MethodPost,
+			ContentType: "json", SuccessCode: http.StatusOK, Enabled: false,
+		},
+	}}
+	if err := api.LoadAPIConfigs(st); err != nil {
+		t.Fatalf("LoadAPIConfigs() error = %v", err)
+	}
+
+	preview, err := PreviewMessage(st, "")
+	if err != nil {
+		t.Fatalf("PreviewMessage() error = %v", err)
+	}
+
+	if len(preview.Connectors) != 1 {
+		t.Fatalf("connectors = %+v, want only the enabled one", preview.Connectors)
+	}
+	got := preview.Connectors[0]
+	if got.URL != repoURL || got.Request == nil || got.Error != "" {
+		t.Fatalf("preview = %+v, want a request for %s", got, repoURL)
+	}
+	if got.Request.Headers["Authorization"] != "Bearer ********" {
+		t.Errorf("Authorization = %q, want it masked", got.Request.Headers["Authorization"])
+	}
+	if body, _ := got.Request.Body.(map[string]any); body["url"] != repoURL {
+		t.Errorf("body = %v, want the queue item", got.Request.Body)
+	}
+
+	if connectorCalls != 0 || queueChanges != 0 {
+		t.Errorf("connector calls = %d, queue changes = %d, want none", connectorCalls, queueChanges)
+	}
+	if len(st.attempts) != 0 || st.logCalls != 0 {
+		t.Errorf("attempts = %+v, log calls = %d, want nothing recorded", st.attempts, st.logCalls)
+	}
+}
+
+func TestPreviewMessageFollowsScheduleConnectors(t *testing.T) {
+	stub := &queueStub{repositoryPath: "/live/repo", validationCode: http.StatusOK}
+	server := httptest.NewServer(stub.handler(t))
+	defer server.Close()
+	stub.repositoryURL = server.URL + stub.repositoryPath
+	withMessageJobWorkdir(t)
+
+	t.Setenv("CONTENT_ALCHEMIST_URL", server.URL)
+	t.Setenv("CONTENT_ALCHEMIST_BEARER", "test-token")
+
+	st := &retryStore{
+		configs: []models.APIConfigModel{
+			{Name: "threads", URL: server.URL, Method: http.MethodPost, ContentType: "json", SuccessCode: http.StatusOK, Enabled: true},
+			{Name: "x", URL: server.URL, Method: http.MethodPost, ContentType: "json", SuccessCode: http.StatusOK, Enabled: true},
+		},
+		cronSettings: map[string]*models.CronSetting{
+			"x-evening": {Name: "x-evening", Job: models.JobMessage, IsActive: true, APIs: []string{"x", "mastodon"}},
+		},
+	}
+	if err := api.LoadAPIConfigs(st); err != nil {
+		t.Fatalf("LoadAPIConfigs() error = %v", err)
+	}
+
+	preview, err := PreviewMessage(st, "x-evening")
+	if err != nil {
+		t.Fatalf("PreviewMessage() error = %v", err)
+	}
+
+	var names []string
+	for _, connector := range preview.Connectors {
+		names = append(names, connector.APIName)
+	}
+	if !slices.Equal(names, []string{"mastodon", "x"}) {
+		t.Fatalf("connectors = %v, want the schedule's mastodon and x", names)
+	}
+	if preview.Connectors[0].Error == "" {
+		t.Errorf("preview = %+v, want the unconfigured connector reported", preview.Connectors[0])
+	}
+	if preview.Connectors[1].Request == nil {
+		t.Errorf("preview = %+v, want a request for x", preview.Connectors[1])
+	}
+}
+
+func TestPreviewMessageReportsUnreachableRepository(t *testing.T) {
+	stub := &queueStub{repositoryPath: "/gone/repo", validationCode: http.StatusNotFound}
+	var deletes int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			deletes++
+		}
+		stub.handler(t)(w, r)
+	}))
+	defer server.Close()
+	stub.repositoryURL = server.URL + stub.repositoryPath
+	withMessageJobWorkdir(t)
+
+	t.Setenv("CONTENT_ALCHEMIST_URL", server.URL)
+	t.Setenv("CONTENT_ALCHEMIST_BEARER", "test-token")
+
+	st := &retryStore{configs: []models.APIConfigModel{{
+		Name: "threads", URL: server.URL, Method: http.MethodPost,
+		ContentType: "json", SuccessCode: http.StatusOK, Enabled: true,
+	}}}
+	if err := api.LoadAPIConfigs(st); err != nil {
+		t.Fatalf("LoadAPIConfigs() error = %v", err)
+	}
+
+	preview, err := PreviewMessage(st, "")
+	if err != nil {
+		t.Fatalf("PreviewMessage() error = %v", err)
+	}
+
+	if len(preview.Connectors) != 1 || !strings.Contains(preview.Connectors[0].Skip, "404") {
+		t.Fatalf("connectors = %+v, want the unreachable repository reported", preview.Connectors)
+	}
+	if deletes != 0 {
+		t.Errorf("preview deleted %d repositories, want none", deletes)
+	}
+}
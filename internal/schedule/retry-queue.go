@@ -0,0 +1,244 @@
+package schedule
+
+import (
+	"content-maestro/internal/api"
+	"content-maestro/internal/models"
+	"content-maestro/internal/notification"
+	"content-maestro/internal/repository"
+	"content-maestro/internal/store"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-co-op/gocron"
+)
+
+const (
+	// retryQueueInterval is how often the queue looks for due retries. The
+	// shortest backoff is several times longer, so no retry waits noticeably
+	// past its due time.
+	retryQueueInterval = time.Minute
+	// retryQueueBatch bounds one pass, so a backlog after a long connector outage
+	// is drained over several passes instead of in one burst.
+	retryQueueBatch = 20
+
+	retryBaseDelay = 5 * time.Minute
+	retryMaxDelay  = 6 * time.Hour
+)
+
+// retryDelay is the exponential backoff after the given number of attempts:
+// 5m after the first failure, then 10m, 20m and so on, capped at retryMaxDelay.
+func retryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= retryMaxDelay {
+			return retryMaxDelay
+		}
+	}
+	return delay
+}
+
+// queueDeliveryRetry puts a failed delivery into the retry queue, or gives up
+// on it once the connector's budget is spent. It reports whether the delivery
+// is still queued.
+func queueDeliveryRetry(st store.StoreInterface, url, apiName string, endpoint api.APIEndpoint) bool {
+	if endpoint.RetryMaxAttempts <= 0 {
+		return false
+	}
+
+	delivery, err := st.GetDelivery(url, apiName)
+	if err != nil {
+		log.Errorf("Failed to read delivery of %s to %s for the retry queue: %v", url, apiName, err)
+		return false
+	}
+	if delivery == nil || delivery.Status == models.DeliveryStatusDelivered {
+		return false
+	}
+
+	// The first attempt is not a retry, so the budget is spent once the attempts
+	// exceed it. Manual retries are not charged against it: a re-send by hand
+	// should not cost the connector its automatic retries.
+	attempts := delivery.Attempts - delivery.ManualAttempts
+	if attempts > endpoint.RetryMaxAttempts {
+		giveUpDelivery(st, delivery, fmt.Sprintf("retry budget of %d attempts spent", endpoint.RetryMaxAttempts))
+		return false
+	}
+
+	next := time.Now().Add(retryDelay(attempts))
+	if err := st.ScheduleDeliveryRetry(url, apiName, next); err != nil {
+		log.Errorf("Failed to queue retry of %s to %s: %v", url, apiName, err)
+		return false
+	}
+
+	log.Debugf("Queued retry of %s to %s API at %s", url, apiName, next.Format(time.RFC3339))
+	return true
+}
+
+// giveUpDelivery abandons a delivery and alerts about it: from here on only a
+// manual retry can get the item to the connector.
+func giveUpDelivery(st store.StoreInterface, delivery *models.Delivery, reason string) {
+	if err := st.AbandonDelivery(delivery.URL, delivery.APIName, reason); err != nil {
+		log.Errorf("Failed to abandon delivery of %s to %s: %v", delivery.URL, delivery.APIName, err)
+	}
+
+	message := fmt.Sprintf("Automatic retry gave up on %s for %s after %d attempts: %s. Last error: %s",
+		delivery.URL, delivery.APIName, delivery.Attempts, reason, delivery.LastError)
+	log.Error(message)
+	notification.NotifyCronResult("message", 0, message)
+}
+
+// RetryQueueJob sends every due delivery in the retry queue once more.
+//
+// It takes publishMutex, so neither a message schedule run nor a manual retry
+// publishes the same item alongside it. A pass that finds it taken is skipped
+// rather than queued: the next one is a minute away, and the other run may well
+// have delivered the item already.
+func RetryQueueJob(st store.StoreInterface) {
+	if !publishMutex.TryLock() {
+		log.Debug("Message run or manual retry in progress, skipping retry queue pass")
+		return
+	}
+	defer publishMutex.Unlock()
+
+	// Due retries stay queued and go out with the first pass after the window.
+	if window := activeBlackout(st, time.Now()); window != nil {
+		log.Debugf("Blackout window '%s' is active, skipping retry queue pass", window.Name)
+		return
+	}
+
+	apiConfigs := api.GetAPIConfigs()
+	if apiConfigs == nil {
+		log.Error("API configurations not loaded, skipping retry queue pass")
+		return
+	}
+
+	due, err := st.GetDueDeliveryRetries(time.Now(), retryQueueBatch)
+	if err != nil {
+		log.Errorf("Failed to read the retry queue: %v", err)
+		return
+	}
+	if len(due) == 0 {
+		return
+	}
+
+	globalSettings := globalImageSettings(st)
+	results := map[string]*models.MessageRunDetails{}
+	var order []string
+	errorMessages := map[string][]string{}
+
+	for i := range due {
+		delivery := &due[i]
+
+		details, ok := results[delivery.URL]
+		if !ok {
+			details = &models.MessageRunDetails{URL: delivery.URL, Automatic: true}
+			results[delivery.URL] = details
+			order = append(order, delivery.URL)
+		}
+
+		endpoint, ok := apiConfigs.APIs[delivery.APIName]
+		if !ok || !endpoint.Enabled || endpoint.RetryMaxAttempts <= 0 {
+			giveUpDelivery(st, delivery, "the connector is no longer configured for automatic retries")
+			continue
+		}
+
+		resp, err := retryDelivery(st, delivery, endpoint, globalSettings)
+		if err == nil {
+			log.Debugf("%s API received %s on automatic retry", delivery.APIName, delivery.URL)
+			details.Sent = append(details.Sent, delivery.APIName)
+			if post, ok := publishedPost(delivery.APIName, resp); ok {
+				details.Posts = append(details.Posts, post)
+			}
+			continue
+		}
+
+		log.Errorf("Automatic retry of %s to %s failed: %v", delivery.URL, delivery.APIName, err)
+		details.Failed = append(details.Failed, delivery.APIName)
+		errorMessages[delivery.URL] = append(errorMessages[delivery.URL], fmt.Sprintf("%s: %v", delivery.APIName, err))
+		queueDeliveryRetry(st, delivery.URL, delivery.APIName, endpoint)
+	}
+
+	for _, url := range order {
+		logRetryQueueResult(st, results[url], errorMessages[url])
+	}
+}
+
+// retryDelivery makes one more attempt at a queued delivery. Failures that
+// happen before the connector is contacted are recorded as attempts too, so a
+// repository that can no longer be fetched still spends the retry budget.
+func retryDelivery(st store.StoreInterface, delivery *models.Delivery, endpoint api.APIEndpoint, globalSettings models.ImageSettings) (*api.APIResponse, error) {
+	textLanguage := endpoint.TextLanguage
+	if textLanguage == "" {
+		textLanguage = "en"
+	}
+
+	fail := func(err error) (*api.APIResponse, error) {
+		attempt := &models.DeliveryAttempt{URL: delivery.URL, APIName: delivery.APIName, Error: err.Error()}
+		if recordErr := st.RecordDeliveryAttempt(attempt); recordErr != nil {
+			log.Errorf("Failed to record delivery of %s to %s: %v", delivery.URL, delivery.APIName, recordErr)
+		}
+		return nil, err
+	}
+
+	item, err := repository.GetRepositoryByURL(delivery.URL, textLanguage)
+	if err != nil {
+		return fail(fmt.Errorf("failed to get repository (language %s): %w", textLanguage, err))
+	}
+
+	imageName := ""
+	if endpoint.SocialifyImage {
+		imageName, err = cachedImage(item.URL, endpoint.ImageSettings.Apply(globalSettings), retryImageConfig)
+		if err != nil {
+			return fail(fmt.Errorf("failed to prepare image: %w", err))
+		}
+	}
+
+	resp, err := publishItem(st, delivery.APIName, endpoint, *item, imageName, false)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, errors.New(responseFailure(resp))
+	}
+	return resp, nil
+}
+
+// logRetryQueueResult records one repository's automatic retries under the
+// message job, next to the run that failed them. Only giving up notifies:
+// a retry that will be tried again is not yet worth an alert.
+func logRetryQueueResult(st store.StoreInterface, details *models.MessageRunDetails, errorMessages []string) {
+	if len(details.Sent) == 0 && len(details.Failed) == 0 {
+		return
+	}
+
+	var status int
+	var message string
+	switch {
+	case len(details.Sent) == 0:
+		status = 0
+		message = fmt.Sprintf("Automatic retry: nothing sent for %s. Errors: %s", details.URL, strings.Join(errorMessages, "; "))
+	case len(details.Failed) > 0:
+		status = 2
+		message = fmt.Sprintf("Automatic retry: %s sent to: %s. Failed: %s. Errors: %s", details.URL,
+			strings.Join(details.Sent, ", "), strings.Join(details.Failed, ", "), strings.Join(errorMessages, "; "))
+	default:
+		status = 1
+		message = fmt.Sprintf("Automatic retry: %s sent to: %s", details.URL, strings.Join(details.Sent, ", "))
+	}
+
+	if err := st.LogCronExecutionDetails("message", status, message, details); err != nil {
+		log.Errorf("Failed to log automatic retry execution: %v", err)
+	}
+}
+
+// RetryQueueCron starts the background worker that drains the retry queue. It
+// is not a user-facing cron: it has no setting and always runs.
+func RetryQueueCron(st store.StoreInterface) *gocron.Scheduler {
+	s := gocron.NewScheduler(time.UTC)
+	s.Every(retryQueueInterval).SingletonMode().Do(RetryQueueJob, st)
+	s.StartAsync()
+	log.Debug("Retry queue started")
+	return s
+}
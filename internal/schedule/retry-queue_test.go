@@ -0,0 +1,228 @@
+package schedule
+
+import (
+	"content-maestro/internal/api"
+	"content-maestro/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 0, want: 5 * time.Minute},
+		{attempts: 1, want: 5 * time.Minute},
+		{attempts: 2, want: 10 * time.Minute},
+		{attempts: 4, want: 40 * time.Minute},
+		{attempts: 7, want: 320 * time.Minute},
+		{attempts: 8, want: 6 * time.Hour},
+		{attempts: 100, want: 6 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := retryDelay(tt.attempts); got != tt.want {
+			t.Errorf("retryDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestRetryQueueJobDeliversDueRetry(t *testing.T) {
+	var connectorCalls int
+	connector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		connectorCalls++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer connector.Close()
+
+	alchemist := alchemistStub(t, true, nil)
+	defer alchemist.Close()
+	withRepositoryEndpoints(t, alchemist.URL)
+
+	key := retryTestURL + " threads"
+	st := &retryStore{
+		configs: []models.APIConfigModel{{
+			Name: "threads", URL: connector.URL, Method: http.MethodPost,
+			ContentType: "json", SuccessCode: http.StatusOK, Enabled: true,
+			TextLanguage: "en", RetryMaxAttempts: 3,
+		}},
+		delivered: map[string]*models.Delivery{
+			key: {URL: retryTestURL, APIName: "threads", Status: models.DeliveryStatusFailed, Attempts: 1},
+		},
+		due: []models.Delivery{{URL: retryTestURL, APIName: "threads", Status: models.DeliveryStatusFailed, Attempts: 1}},
+	}
+	if err := api.LoadAPIConfigs(st); err != nil {
+		t.Fatalf("LoadAPIConfigs() error = %v", err)
+	}
+
+	RetryQueueJob(st)
+
+	if connectorCalls != 1 {
+		t.Fatalf("connector calls = %d, want 1", connectorCalls)
+	}
+	if st.delivered[key].Status != models.DeliveryStatusDelivered {
+		t.Errorf("delivery status = %q, want delivered", st.delivered[key].Status)
+	}
+	if len(st.queued) != 0 || len(st.abandoned) != 0 {
+		t.Errorf("queued = %v, abandoned = %v, want neither after a success", st.queued, st.abandoned)
+	}
+	if st.loggedStatus != 1 || st.loggedDetails == nil || !st.loggedDetails.Automatic {
+		t.Errorf("logged status %d with details %+v, want a successful automatic entry", st.loggedStatus, st.loggedDetails)
+	}
+}
+
+func TestRetryQueueJobBacksOffThenGivesUp(t *testing.T) {
+	connector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer connector.Close()
+
+	alchemist := alchemistStub(t, true, nil)
+	defer alchemist.Close()
+	withRepositoryEndpoints(t, alchemist.URL)
+
+	key := retryTestURL + " threads"
+	st := &retryStore{
+		configs: []models.APIConfigModel{{
+			Name: "threads", URL: connector.URL, Method: http.MethodPost,
+			ContentType: "json", SuccessCode: http.StatusOK, Enabled: true,
+			TextLanguage: "en", RetryMaxAttempts: 2,
+		}},
+		delivered: map[string]*models.Delivery{
+			key: {URL: retryTestURL, APIName: "threads", Status: models.DeliveryStatusFailed, Attempts: 1},
+		},
+		due: []models.Delivery{{URL: retryTestURL, APIName: "threads", Status: models.DeliveryStatusFailed, Attempts: 1}},
+	}
+	if err := api.LoadAPIConfigs(st); err != nil {
+		t.Fatalf("LoadAPIConfigs() error = %v", err)
+	}
+
+	before := time.Now()
+	RetryQueueJob(st)
+
+	next, ok := st.queued[key]
+	if !ok {
+		t.Fatalf("failed retry was not queued again")
+	}
+	if wait := next.Sub(before); wait < retryDelay(2) || wait > retryDelay(2)+time.Minute {
+		t.Errorf("next attempt in %v, want about %v", wait, retryDelay(2))
+	}
+	if st.loggedStatus != 0 || st.loggedDetails == nil || !st.loggedDetails.Automatic {
+		t.Errorf("logged status %d with details %+v, want a failed automatic entry", st.loggedStatus, st.loggedDetails)
+	}
+
+	// The third attempt overruns a budget of two retries.
+	RetryQueueJob(st)
+
+	if _, ok := st.abandoned[key]; !ok {
+		t.Errorf("delivery was not abandoned after %d attempts", st.delivered[key].Attempts)
+	}
+}
+
+func TestRetryQueueJobDoesNotChargeManualRetries(t *testing.T) {
+	connector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer connector.Close()
+
+	alchemist := alchemistStub(t, true, nil)
+	defer alchemist.Close()
+	withRepositoryEndpoints(t, alchemist.URL)
+
+	// The first attempt and two manual retries failed: the budget of two
+	// automatic retries is untouched.
+	key := retryTestURL + " threads"
+	st := &retryStore{
+		configs: []models.APIConfigModel{{
+			Name: "threads", URL: connector.URL, Method: http.MethodPost,
+			ContentType: "json", SuccessCode: http.StatusOK, Enabled: true,
+			TextLanguage: "en", RetryMaxAttempts: 2,
+		}},
+		delivered: map[string]*models.Delivery{
+			key: {URL: retryTestURL, APIName: "threads", Status: models.DeliveryStatusFailed, Attempts: 3, ManualAttempts: 2},
+		},
+		due: []models.Delivery{{URL: retryTestURL, APIName: "threads", Status: models.DeliveryStatusFailed, Attempts: 3, ManualAttempts: 2}},
+	}
+	if err := api.LoadAPIConfigs(st); err != nil {
+		t.Fatalf("LoadAPIConfigs() error = %v", err)
+	}
+
+	RetryQueueJob(st)
+
+	if _, ok := st.abandoned[key]; ok {
+		t.Fatalf("delivery was abandoned after its first automatic retry")
+	}
+	if _, ok := st.queued[key]; !ok {
+		t.Fatalf("failed retry was not queued again")
+	}
+
+	// The second automatic retry spends the budget.
+	RetryQueueJob(st)
+
+	if _, ok := st.abandoned[key]; !ok {
+		t.Errorf("delivery was not abandoned after %d attempts", st.delivered[key].Attempts)
+	}
+}
+
+func TestRetryQueueJobSkipsWhileManualRetryRuns(t *testing.T) {
+	st := &retryStore{due: []models.Delivery{{URL: retryTestURL, APIName: "threads"}}}
+
+	publishMutex.Lock()
+	RetryQueueJob(st)
+	publishMutex.Unlock()
+
+	if len(st.attempts) != 0 || st.logCalls != 0 {
+		t.Errorf("retry queue ran while a manual retry held the lock: attempts %+v, %d log calls", st.attempts, st.logCalls)
+	}
+}
+
+func TestRetryQueueJobSkipsWhileMessageRuns(t *testing.T) {
+	var connectorCalls int
+	connector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		connectorCalls++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer connector.Close()
+
+	alchemist := alchemistStub(t, true, nil)
+	defer alchemist.Close()
+	withRepositoryEndpoints(t, alchemist.URL)
+
+	key := retryTestURL + " threads"
+	st := &retryStore{
+		configs: []models.APIConfigModel{{
+			Name: "threads", URL: connector.URL, Method: http.MethodPost,
+			ContentType: "json", SuccessCode: http.StatusOK, Enabled: true,
+			TextLanguage: "en", RetryMaxAttempts: 3,
+		}},
+		delivered: map[string]*models.Delivery{
+			key: {URL: retryTestURL, APIName: "threads", Status: models.DeliveryStatusFailed, Attempts: 1},
+		},
+		due: []models.Delivery{{URL: retryTestURL, APIName: "threads", Status: models.DeliveryStatusFailed, Attempts: 1}},
+	}
+	if err := api.LoadAPIConfigs(st); err != nil {
+		t.Fatalf("LoadAPIConfigs() error = %v", err)
+	}
+
+	// A named schedule publishing, which holds its own run lock rather than the
+	// message job's.
+	publishMutex.RLock()
+	RetryQueueJob(st)
+	publishMutex.RUnlock()
+
+	if connectorCalls != 0 || len(st.attempts) != 0 {
+		t.Errorf("retry queue published while a message run held the lock: %d connector calls, attempts %+v", connectorCalls, st.attempts)
+	}
+
+	RetryQueueJob(st)
+	if connectorCalls != 1 {
+		t.Errorf("connector calls after the message run = %d, want 1", connectorCalls)
+	}
+	if !publishMutex.TryLock() {
+		t.Fatal("retry queue pass left the publish lock taken")
+	}
+	publishMutex.Unlock()
+}
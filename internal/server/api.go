@@ -2,6 +2,7 @@ package server
 
 import (
 	apiExecutor "content-maestro/internal/api"
+	"content-maestro/internal/middleware"
 	"content-maestro/internal/models"
 	"content-maestro/internal/schedule"
 	"content-maestro/internal/store"
@@ -9,15 +10,22 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-co-op/gocron"
 )
 
 type CronAPI struct {
-	store      store.StoreInterface
+	store store.StoreInterface
+	// mu guards schedulers and jobs, which change as message schedules are
+	// created and deleted. Changes to a cron hold it from reading the setting
+	// to restarting the scheduler, so two of them cannot interleave.
+	mu         sync.Mutex
 	schedulers map[string]*gocron.Scheduler
 	jobs       models.JobRegistry
 }
@@ -30,6 +38,21 @@ func NewCronAPI(store store.StoreInterface, schedulers map[string]*gocron.Schedu
 	}
 }
 
+// cron returns the scheduler and job of a cron name.
+func (api *CronAPI) cron(name string) (*gocron.Scheduler, models.JobFunc, bool) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	scheduler, exists := api.schedulers[name]
+	if !exists {
+		return nil, nil, false
+	}
+	return scheduler, api.jobs[name], true
+}
+
+// upcomingRuns is how many fire times GetCrons lists per cron.
+const upcomingRuns = 5
+
 func (api *CronAPI) GetCrons(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -42,89 +65,99 @@ func (api *CronAPI) GetCrons(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(settings)
-}
+	for i := range settings {
+		setting := &settings[i]
+		if setting.IsActive && setting.PausedUntil != nil && setting.PausedUntil.After(time.Now()) {
+			setting.NextRuns = schedule.RunsAfter(setting, *setting.PausedUntil, upcomingRuns)
+		} else if scheduler, _, exists := api.cron(setting.Name); exists {
+			setting.NextRuns = schedule.NextRuns(scheduler, setting, upcomingRuns)
+		}
+		if len(setting.NextRuns) > 0 {
+			setting.NextRun = &setting.NextRuns[0]
+		}
 
-func (api *CronAPI) UpdateSchedule(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPut {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
+		setting.LastRun, err = api.store.GetLastCronRun(setting.Name)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		setting.Running = schedule.IsJobRunning(setting.Name)
 	}
 
-	cronName := strings.TrimPrefix(r.URL.Path, "/api/crons/")
-	cronName = strings.TrimSuffix(cronName, "/schedule")
-
-	scheduler, exists := api.schedulers[cronName]
-	if !exists {
-		http.Error(w, "Invalid cron name", http.StatusBadRequest)
-		return
-	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
 
-	var req models.UpdateScheduleRequest
+// CreateCron adds a named message schedule that publishes to its own set of
+// connectors.
+func (api *CronAPI) CreateCron(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateCronRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	if err := validation.ValidateCronExpression(req.Schedule); err != nil {
+	if err := validation.ValidateCronCreate(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := api.checkAPIsExist(req.APIs); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	setting, err := api.store.GetCronSetting(cronName)
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	existing, err := api.store.GetCronSetting(req.Name)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	if setting == nil {
-		http.Error(w, "Cron not found", http.StatusNotFound)
+	if existing != nil {
+		http.Error(w, fmt.Sprintf("Cron '%s' already exists", req.Name), http.StatusConflict)
 		return
 	}
 
-	setting.Schedule = req.Schedule
-	_, err = api.store.UpdateCronSetting(setting.Name, setting.Schedule, setting.IsActive)
+	setting, err := api.store.CreateCronSetting(&req)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	scheduler.Stop()
-	scheduler.Clear()
-
-	if job, ok := api.jobs[cronName]; ok {
-		scheduler.Cron(setting.Schedule).Do(job, scheduler)
-		if setting.IsActive {
-			scheduler.StartAsync()
-		}
+	job := schedule.MessageScheduleJob(api.store, setting.Name)
+	scheduler := gocron.NewScheduler(time.UTC)
+	if err := schedule.StartScheduler(scheduler, api.store, setting, job); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
+	api.schedulers[setting.Name] = scheduler
+	api.jobs[setting.Name] = job
+	setting.NextRun = schedule.NextRun(scheduler)
+
+	api.audit(r, nil, setting)
 
-	response := models.CronResponse{
-		Status:  "success",
-		Message: "Schedule updated successfully",
-	}
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(setting)
 }
 
-func (api *CronAPI) UpdateStatus(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPut {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+// DeleteCron removes a named message schedule. The collect and message crons
+// can only be disabled, and the cron of a collect profile goes with its profile.
+func (api *CronAPI) DeleteCron(w http.ResponseWriter, r *http.Request) {
+	cronName := strings.TrimPrefix(r.URL.Path, "/api/crons/")
+
+	if cronName == models.JobCollect || cronName == models.JobMessage {
+		http.Error(w, fmt.Sprintf("The %s cron cannot be deleted", cronName), http.StatusBadRequest)
 		return
 	}
 
-	cronName := strings.TrimPrefix(r.URL.Path, "/api/crons/")
-	cronName = strings.TrimSuffix(cronName, "/status")
+	api.mu.Lock()
+	defer api.mu.Unlock()
 
 	scheduler, exists := api.schedulers[cronName]
 	if !exists {
-		http.Error(w, "Invalid cron name", http.StatusBadRequest)
-		return
-	}
-
-	var req models.UpdateStatusRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		http.Error(w, "Cron not found", http.StatusNotFound)
 		return
 	}
 
@@ -133,300 +166,1597 @@ func (api *CronAPI) UpdateStatus(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	if setting == nil {
-		http.Error(w, "Cron not found", http.StatusNotFound)
+	if setting != nil && setting.Job == models.JobCollect {
+		http.Error(w, fmt.Sprintf("The %s cron runs a collect profile; delete the profile instead", cronName), http.StatusBadRequest)
 		return
 	}
 
-	setting.IsActive = req.IsActive
-	updatedSetting, err := api.store.UpdateCronSetting(setting.Name, setting.Schedule, setting.IsActive)
-	if err != nil {
+	if err := api.store.DeleteCronSetting(cronName); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	scheduler.Stop()
 	scheduler.Clear()
+	delete(api.schedulers, cronName)
+	delete(api.jobs, cronName)
 
-	if updatedSetting.IsActive {
-		if job, ok := api.jobs[cronName]; ok {
-			scheduler.Cron(setting.Schedule).Do(job, scheduler)
-			scheduler.StartAsync()
-		}
-	}
+	api.audit(r, setting, nil)
 
 	response := models.CronResponse{
 		Status:  "success",
-		Message: "Status updated successfully",
+		Message: "Cron deleted successfully",
 	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
-func (api *CronAPI) UpdateCollectSettings(w http.ResponseWriter, r *http.Request) {
-	var settings store.CollectSettings
-	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+// UpdateCronAPIs replaces the connectors of a message schedule. It takes effect
+// from the next run; the schedule itself is unchanged.
+func (api *CronAPI) UpdateCronAPIs(w http.ResponseWriter, r *http.Request) {
+	cronName := strings.TrimPrefix(r.URL.Path, "/api/crons/")
+	cronName = strings.TrimSuffix(cronName, "/apis")
+
+	var req models.UpdateCronAPIsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	if settings.MaxRepos < 1 {
-		http.Error(w, "MaxRepos must be greater than 0", http.StatusBadRequest)
-		return
-	}
-	if settings.Since == "" {
-		http.Error(w, "Since cannot be empty", http.StatusBadRequest)
+	if err := validation.ValidateCronAPIs(req.APIs); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	if settings.SpokenLanguageCode == "" {
-		http.Error(w, "SpokenLanguageCode cannot be empty", http.StatusBadRequest)
+	if err := api.checkAPIsExist(req.APIs); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	if err := api.store.UpdateCollectSettings(&settings); err != nil {
+	setting, err := api.store.GetCronSetting(cronName)
+	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-
-	response := models.CronResponse{
-		Status:  "success",
-		Message: "Collect settings updated successfully",
+	if setting == nil {
+		http.Error(w, "Cron not found", http.StatusNotFound)
+		return
+	}
+	if setting.Job != models.JobMessage {
+		http.Error(w, fmt.Sprintf("The %s cron does not publish to connectors", cronName), http.StatusBadRequest)
+		return
 	}
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
-}
 
-func (api *CronAPI) GetCollectSettings(w http.ResponseWriter, r *http.Request) {
-	settings, err := api.store.GetCollectSettings()
+	updated, err := api.store.UpdateCronAPIs(cronName, req.APIs)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	api.audit(r, setting, updated)
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(settings)
+	json.NewEncoder(w).Encode(updated)
 }
 
-func (api *CronAPI) GetCronHistory(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
+// checkAPIsExist reports the first connector name with no API configuration.
+func (api *CronAPI) checkAPIsExist(apis []string) error {
+	for _, name := range apis {
+		config, err := api.store.GetAPIConfig(name)
+		if err != nil {
+			return err
+		}
+		if config == nil {
+			return fmt.Errorf("API config '%s' not found", name)
+		}
+	}
+	return nil
+}
+
+func (api *CronAPI) UpdateSchedule(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	cronName := r.URL.Query().Get("name")
-	pageStr := r.URL.Query().Get("page")
-	limitStr := r.URL.Query().Get("limit")
-	statusStr := r.URL.Query().Get("status")
-	sortOrder := r.URL.Query().Get("sort")
-	startDateStr := r.URL.Query().Get("start_date")
-	endDateStr := r.URL.Query().Get("end_date")
-
-	page, err := strconv.Atoi(pageStr)
-	if err != nil || page < 1 {
-		page = 1
-	}
+	cronName := strings.TrimPrefix(r.URL.Path, "/api/crons/")
+	cronName = strings.TrimSuffix(cronName, "/schedule")
 
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		limit = 20
+	var req models.UpdateScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
 	}
 
-	if sortOrder != "asc" && sortOrder != "desc" {
-		sortOrder = "desc"
+	if err := validation.ValidateCronExpression(req.Schedule); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
-
-	offset := (page - 1) * limit
-
-	var status *int
-	if statusStr != "" {
-		statusVal, err := strconv.Atoi(statusStr)
-		if err != nil || statusVal < 0 || statusVal > 2 {
-			http.Error(w, "Invalid status parameter: must be 0, 1, or 2", http.StatusBadRequest)
+	if req.Timezone != nil {
+		if err := validation.ValidateTimezone(*req.Timezone); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		status = &statusVal
 	}
 
-	startDate, endDate, err := validation.ParseDateRange(startDateStr, endDateStr)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	// Held from reading the setting to restarting its scheduler, so a
+	// concurrent change to the same cron cannot interleave with this one.
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	scheduler, exists := api.schedulers[cronName]
+	if !exists {
+		http.Error(w, "Invalid cron name", http.StatusBadRequest)
 		return
 	}
+	job := api.jobs[cronName]
 
-	totalCount, err := api.store.GetCronHistoryCount(cronName, status, startDate, endDate)
+	setting, err := api.store.GetCronSetting(cronName)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-
-	history, err := api.store.GetCronHistory(cronName, status, offset, limit, sortOrder, startDate, endDate)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if setting == nil {
+		http.Error(w, "Cron not found", http.StatusNotFound)
 		return
 	}
 
-	totalPages := (totalCount + limit - 1) / limit
-	if totalPages == 0 {
-		totalPages = 1
+	before := *setting
+
+	// A new schedule can leave too little room between runs for the current
+	// jitter, so the window is checked against it either way.
+	jitter := setting.JitterSeconds
+	if req.JitterSeconds != nil {
+		jitter = *req.JitterSeconds
+	}
+	if err := validation.ValidateCronJitter(req.Schedule, jitter); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
-	pagination := models.PaginationMetadata{
-		TotalCount:  totalCount,
-		CurrentPage: page,
-		TotalPages:  totalPages,
-		HasNext:     page < totalPages,
-		HasPrevious: page > 1,
+	setting.Schedule = req.Schedule
+	setting.JitterSeconds = jitter
+	if req.Timezone != nil {
+		setting.Timezone = *req.Timezone
+	}
+	if err := api.store.UpdateCronSchedule(setting.Name, setting.Schedule, setting.Timezone, setting.JitterSeconds); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
 
-	response := models.PaginatedCronHistoryResponse{
-		Data:       history,
-		Pagination: pagination,
+	if job != nil {
+		if err := schedule.StartScheduler(scheduler, api.store, setting, job); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 	}
 
+	api.audit(r, before, setting)
+
+	response := models.CronResponse{
+		Status:  "success",
+		Message: "Schedule updated successfully",
+	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
-func (api *CronAPI) GetPromptSettings(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
+func (api *CronAPI) UpdateStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	settings, err := api.store.GetPromptSettings()
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	cronName := strings.TrimPrefix(r.URL.Path, "/api/crons/")
+	cronName = strings.TrimSuffix(cronName, "/status")
+
+	var req models.UpdateStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(settings)
-}
+	api.mu.Lock()
+	defer api.mu.Unlock()
 
-func (api *CronAPI) UpdatePromptSettings(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPut {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	scheduler, exists := api.schedulers[cronName]
+	if !exists {
+		http.Error(w, "Invalid cron name", http.StatusBadRequest)
 		return
 	}
+	job := api.jobs[cronName]
 
-	var settings models.UpdatePromptSettingsRequest
-	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	setting, err := api.store.GetCronSetting(cronName)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-
-	if err := validation.ValidatePromptSettings(&settings); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if setting == nil {
+		http.Error(w, "Cron not found", http.StatusNotFound)
 		return
 	}
 
-	if err := api.store.UpdatePromptSettings(&settings); err != nil {
+	before := *setting
+	// Switching a cron on or off is the final word on it; a pending pause would
+	// otherwise resume a cron that was just switched off, or hold back one that
+	// was just switched on.
+	if err := api.store.UpdateCronStatus(setting.Name, req.IsActive); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	setting.IsActive = req.IsActive
+	setting.PausedUntil, setting.PauseReason = nil, ""
+
+	if job != nil {
+		if err := schedule.StartScheduler(scheduler, api.store, setting, job); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	api.audit(r, before, setting)
 
 	response := models.CronResponse{
 		Status:  "success",
-		Message: "Prompt settings updated successfully",
+		Message: "Status updated successfully",
 	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
-func (api *CronAPI) HandleCollectSettings(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodGet:
-		api.GetCollectSettings(w, r)
-	case http.MethodPut:
-		api.UpdateCollectSettings(w, r)
-	default:
+// PauseCron holds an active cron off its schedule until a given time (PUT), or
+// lifts the pause early (DELETE). The pause is stored with the cron, so a
+// restart keeps it and a pause that ended while the service was down is simply
+// over.
+func (api *CronAPI) PauseCron(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut && r.Method != http.MethodDelete {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
 	}
-}
 
-func (api *CronAPI) HandlePromptSettings(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodGet:
-		api.GetPromptSettings(w, r)
-	case http.MethodPut:
-		api.UpdatePromptSettings(w, r)
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-	}
-}
+	cronName := strings.TrimPrefix(r.URL.Path, "/api/crons/")
+	cronName = strings.TrimSuffix(cronName, "/pause")
+
+	var req models.PauseCronRequest
+	if r.Method == http.MethodPut {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if err := validation.ValidateCronPause(&req, time.Now()); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	scheduler, exists := api.schedulers[cronName]
+	if !exists {
+		http.Error(w, "Invalid cron name", http.StatusBadRequest)
+		return
+	}
+	job := api.jobs[cronName]
+
+	setting, err := api.store.GetCronSetting(cronName)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if setting == nil {
+		http.Error(w, "Cron not found", http.StatusNotFound)
+		return
+	}
+
+	before := *setting
+
+	var message string
+	if r.Method == http.MethodPut {
+		if !setting.IsActive {
+			http.Error(w, fmt.Sprintf("Cron '%s' is not active", cronName), http.StatusConflict)
+			return
+		}
+		until := req.Until
+		setting.PausedUntil, setting.PauseReason = &until, req.Reason
+		message = fmt.Sprintf("Cron paused until %s", until.Format(time.RFC3339))
+	} else {
+		if setting.PausedUntil == nil {
+			http.Error(w, fmt.Sprintf("Cron '%s' is not paused", cronName), http.StatusConflict)
+			return
+		}
+		setting.PausedUntil, setting.PauseReason = nil, ""
+		message = "Cron resumed"
+	}
+
+	if err := api.store.UpdateCronPause(setting.Name, setting.PausedUntil, setting.PauseReason); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if job != nil {
+		if err := schedule.StartScheduler(scheduler, api.store, setting, job); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	api.audit(r, before, setting)
+
+	response := models.CronResponse{
+		Status:  "success",
+		Message: message,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// RunCron starts a job immediately, outside its schedule. The job runs in the
+// background; the response carries the run ID it is recorded under in the cron
+// history. A dry run of a message schedule returns its preview instead.
+func (api *CronAPI) RunCron(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	cronName := strings.TrimPrefix(r.URL.Path, "/api/crons/")
+	cronName = strings.TrimSuffix(cronName, "/run")
+
+	scheduler, job, exists := api.cron(cronName)
+	if !exists {
+		http.Error(w, "Invalid cron name", http.StatusBadRequest)
+		return
+	}
+	if job == nil {
+		http.Error(w, "Cron not found", http.StatusNotFound)
+		return
+	}
+
+	// The body is optional: a plain POST starts the run.
+	var req models.RunJobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.DryRun {
+		setting, err := api.store.GetCronSetting(cronName)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if setting == nil || setting.Job != models.JobMessage {
+			http.Error(w, fmt.Sprintf("The %s cron does not publish; dry runs are only available for message schedules", cronName), http.StatusBadRequest)
+			return
+		}
+
+		preview, err := schedule.PreviewMessage(api.store, cronName)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(preview)
+		return
+	}
+
+	run, err := schedule.RunJobNow(cronName, job, scheduler)
+	if err != nil {
+		if errors.Is(err, schedule.ErrJobRunning) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := models.RunJobResponse{
+		Status:  "accepted",
+		Message: fmt.Sprintf("%s run started", cronName),
+		Name:    cronName,
+		RunID:   run.ID,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (api *CronAPI) UpdateCollectSettings(w http.ResponseWriter, r *http.Request) {
+	var settings store.CollectSettings
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if settings.MaxRepos < 1 {
+		http.Error(w, "MaxRepos must be greater than 0", http.StatusBadRequest)
+		return
+	}
+	if settings.Since == "" {
+		http.Error(w, "Since cannot be empty", http.StatusBadRequest)
+		return
+	}
+	if settings.SpokenLanguageCode == "" {
+		http.Error(w, "SpokenLanguageCode cannot be empty", http.StatusBadRequest)
+		return
+	}
+
+	before, err := api.store.GetCollectSettings()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := api.store.UpdateCollectSettings(&settings); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	after, err := api.store.GetCollectSettings()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	api.audit(r, before, after)
+
+	response := models.CronResponse{
+		Status:  "success",
+		Message: "Collect settings updated successfully",
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (api *CronAPI) GetCollectSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := api.store.GetCollectSettings()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+func (api *CronAPI) GetCronHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	cronName := r.URL.Query().Get("name")
+	pageStr := r.URL.Query().Get("page")
+	limitStr := r.URL.Query().Get("limit")
+	statusStr := r.URL.Query().Get("status")
+	sortOrder := r.URL.Query().Get("sort")
+	startDateStr := r.URL.Query().Get("start_date")
+	endDateStr := r.URL.Query().Get("end_date")
+
+	page, err := strconv.Atoi(pageStr)
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	if sortOrder != "asc" && sortOrder != "desc" {
+		sortOrder = "desc"
+	}
+
+	offset := (page - 1) * limit
+
+	var status *int
+	if statusStr != "" {
+		statusVal, err := strconv.Atoi(statusStr)
+		if err != nil || statusVal < models.CronStatusFailure || statusVal > models.CronStatusBlackout {
+			http.Error(w, "Invalid status parameter: must be 0, 1, 2, 3, or 4", http.StatusBadRequest)
+			return
+		}
+		status = &statusVal
+	}
+
+	startDate, endDate, err := validation.ParseDateRange(startDateStr, endDateStr)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	totalCount, err := api.store.GetCronHistoryCount(cronName, status, startDate, endDate)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	history, err := api.store.GetCronHistory(cronName, status, offset, limit, sortOrder, startDate, endDate)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	totalPages := (totalCount + limit - 1) / limit
+	if totalPages == 0 {
+		totalPages = 1
+	}
+
+	pagination := models.PaginationMetadata{
+		TotalCount:  totalCount,
+		CurrentPage: page,
+		TotalPages:  totalPages,
+		HasNext:     page < totalPages,
+		HasPrevious: page > 1,
+	}
+
+	response := models.PaginatedCronHistoryResponse{
+		Data:       history,
+		Pagination: pagination,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetDeliveries lists the delivery ledger: which connectors received which
+// repository, and how many attempts it took.
+func (api *CronAPI) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	url := r.URL.Query().Get("url")
+	apiName := r.URL.Query().Get("api")
+	status := r.URL.Query().Get("status")
+	pageStr := r.URL.Query().Get("page")
+	limitStr := r.URL.Query().Get("limit")
+
+	switch status {
+	case "", models.DeliveryStatusDelivered, models.DeliveryStatusFailed, models.DeliveryStatusAbandoned:
+	default:
+		http.Error(w, "Invalid status parameter: must be delivered, failed or abandoned", http.StatusBadRequest)
+		return
+	}
+
+	page, err := strconv.Atoi(pageStr)
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset := (page - 1) * limit
+
+	totalCount, err := api.store.GetDeliveryCount(url, apiName, status)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	deliveries, err := api.store.GetDeliveries(url, apiName, status, offset, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	totalPages := (totalCount + limit - 1) / limit
+	if totalPages == 0 {
+		totalPages = 1
+	}
+
+	response := models.PaginatedDeliveriesResponse{
+		Data: deliveries,
+		Pagination: models.PaginationMetadata{
+			TotalCount:  totalCount,
+			CurrentPage: page,
+			TotalPages:  totalPages,
+			HasNext:     page < totalPages,
+			HasPrevious: page > 1,
+		},
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// audit records a configuration change made by r in the audit log. before and
+// after are what changed, nil where it did not exist.
+//
+// The change is already saved and applied when it is recorded, so a failure is
+// logged rather than reported: an error response would have the client retry a
+// change that went through.
+func (api *CronAPI) audit(r *http.Request, before, after any) {
+	encodedBefore, err := json.Marshal(auditState(before))
+	if err != nil {
+		log.Errorf("Failed to encode audit entry for %s %s: %v", r.Method, r.URL.Path, err)
+		return
+	}
+	encodedAfter, err := json.Marshal(auditState(after))
+	if err != nil {
+		log.Errorf("Failed to encode audit entry for %s %s: %v", r.Method, r.URL.Path, err)
+		return
+	}
+
+	err = api.store.RecordAuditEntry(&models.AuditEntry{
+		Actor:    middleware.Actor(r),
+		Method:   r.Method,
+		Endpoint: r.URL.Path,
+		Before:   encodedBefore,
+		After:    encodedAfter,
+	})
+	if err != nil {
+		log.Errorf("Failed to record audit entry for %s %s: %v", r.Method, r.URL.Path, err)
+	}
+}
+
+// auditState is what the audit log keeps of a state. The audit log outlives
+// the config and is readable without the connectors scope, so API configs are
+// kept with the literal values of every field that may carry a credential - the
+// URL, the headers, the default JSON body and the body template - masked.
+func auditState(state any) any {
+	if config, ok := state.(*models.APIConfigModel); ok && config != nil {
+		masked := *config
+		masked.URL = apiExecutor.MaskURL(config.URL)
+		masked.Headers = apiExecutor.MaskHeaders(config.Headers)
+		masked.DefaultJSONBody = apiExecutor.MaskJSONBody(config.DefaultJSONBody)
+		masked.BodyTemplate = apiExecutor.MaskBodyTemplate(config.BodyTemplate)
+		return &masked
+	}
+	return state
+}
+
+// GetAuditLog lists the configuration changes made through the API, newest
+// first.
+func (api *CronAPI) GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	actor := r.URL.Query().Get("actor")
+	endpoint := r.URL.Query().Get("endpoint")
+	pageStr := r.URL.Query().Get("page")
+	limitStr := r.URL.Query().Get("limit")
+	startDateStr := r.URL.Query().Get("start_date")
+	endDateStr := r.URL.Query().Get("end_date")
+
+	page, err := strconv.Atoi(pageStr)
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset := (page - 1) * limit
+
+	startDate, endDate, err := validation.ParseDateRange(startDateStr, endDateStr)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	totalCount, err := api.store.GetAuditLogCount(actor, endpoint, startDate, endDate)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	entries, err := api.store.GetAuditLog(actor, endpoint, offset, limit, startDate, endDate)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if entries == nil {
+		entries = []models.AuditEntry{}
+	}
+
+	totalPages := (totalCount + limit - 1) / limit
+	if totalPages == 0 {
+		totalPages = 1
+	}
+
+	response := models.PaginatedAuditLogResponse{
+		Data: entries,
+		Pagination: models.PaginationMetadata{
+			TotalCount:  totalCount,
+			CurrentPage: page,
+			TotalPages:  totalPages,
+			HasNext:     page < totalPages,
+			HasPrevious: page > 1,
+		},
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (api *CronAPI) GetPromptSettings(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	settings, err := api.store.GetPromptSettings()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+func (api *CronAPI) UpdatePromptSettings(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var settings models.UpdatePromptSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := validation.ValidatePromptSettings(&settings); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	before, err := api.store.GetPromptSettings()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := api.store.UpdatePromptSettings(&settings, middleware.Actor(r)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	after, err := api.store.GetPromptSettings()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	api.audit(r, before, after)
+
+	response := models.CronResponse{
+		Status:  "success",
+		Message: "Prompt settings updated successfully",
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (api *CronAPI) HandleCollectSettings(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		api.GetCollectSettings(w, r)
+	case http.MethodPut:
+		api.UpdateCollectSettings(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// GetPromptRevisions lists the changes of the prompt settings, newest first.
+func (api *CronAPI) GetPromptRevisions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 20
+	}
+
+	offset := (page - 1) * limit
+
+	totalCount, err := api.store.GetPromptRevisionCount()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	revisions, err := api.store.GetPromptRevisions(offset, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if revisions == nil {
+		revisions = []models.PromptRevision{}
+	}
+
+	totalPages := (totalCount + limit - 1) / limit
+	if totalPages == 0 {
+		totalPages = 1
+	}
+
+	response := models.PaginatedPromptRevisionsResponse{
+		Data: revisions,
+		Pagination: models.PaginationMetadata{
+			TotalCount:  totalCount,
+			CurrentPage: page,
+			TotalPages:  totalPages,
+			HasNext:     page < totalPages,
+			HasPrevious: page > 1,
+		},
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// RollbackPromptSettings restores the prompt settings of an earlier revision.
+// The restore is itself recorded as a revision, so it can be undone the same
+// way.
+func (api *CronAPI) RollbackPromptSettings(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	rev, err := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, "/api/prompt-settings/rollback/"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid revision", http.StatusBadRequest)
+		return
+	}
+
+	before, err := api.store.GetPromptSettings()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	revision, err := api.store.RollbackPromptSettings(rev, middleware.Actor(r))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if revision != nil {
+		api.audit(r, before, revision.Settings)
+	}
+
+	response := models.CronResponse{
+		Status:  "success",
+		Message: fmt.Sprintf("Prompt settings rolled back to revision %d", rev),
+	}
+	if revision == nil {
+		response.Message = fmt.Sprintf("Prompt settings already match revision %d", rev)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (api *CronAPI) HandlePromptSettings(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		api.GetPromptSettings(w, r)
+	case http.MethodPut:
+		api.UpdatePromptSettings(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (api *CronAPI) GetImageSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := api.store.GetImageSettings()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+func (api *CronAPI) UpdateImageSettings(w http.ResponseWriter, r *http.Request) {
+	var req models.ImageOverrides
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := validation.ValidateImageOverrides(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	before, err := api.store.GetImageSettings()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// The new global settings must work on their own and under every
+	// connector's override of them.
+	global := req.Apply(*before)
+	if err := validation.ValidateImageSettings(global); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	configs, err := api.store.GetAllAPIConfigs()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for _, config := range configs {
+		if err := validation.ValidateImageSettings(config.ImageSettings.Apply(global)); err != nil {
+			http.Error(w, fmt.Sprintf("%s API image_settings: %v", config.Name, err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	settings, err := api.store.UpdateImageSettings(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	api.audit(r, before, settings)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+// validateConnectorImageSettings checks a connector's image override applied to
+// the global settings, and returns the status to answer with when it fails.
+func (api *CronAPI) validateConnectorImageSettings(overrides *models.ImageOverrides) (int, error) {
+	if overrides.IsEmpty() {
+		return 0, nil
+	}
+
+	global, err := api.store.GetImageSettings()
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if err := validation.ValidateImageSettings(overrides.Apply(*global)); err != nil {
+		return http.StatusBadRequest, fmt.Errorf("image_settings: %v", err)
+	}
+	return 0, nil
+}
+
+func (api *CronAPI) HandleImageSettings(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodOptions:
+		return
+	case http.MethodGet:
+		api.GetImageSettings(w, r)
+	case http.MethodPut:
+		api.UpdateImageSettings(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (api *CronAPI) GetAPIConfigs(w http.ResponseWriter, r *http.Request) {
+	configs, err := api.store.GetAllAPIConfigs()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(configs)
+}
+
+func (api *CronAPI) GetAPIConfig(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/api/api-configs/")
+
+	config, err := api.store.GetAPIConfig(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if config == nil {
+		http.Error(w, "API config not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(config)
+}
+
+func (api *CronAPI) CreateAPIConfig(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateAPIConfigRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := validation.ValidateAPIConfig(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if status, err := api.validateConnectorImageSettings(req.ImageSettings); err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	config, err := api.store.CreateAPIConfig(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := apiExecutor.ReloadAPIConfigs(api.store); err != nil {
+		http.Error(w, fmt.Sprintf("API config created but failed to reload: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	api.audit(r, nil, config)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(config)
+}
+
+func (api *CronAPI) UpdateAPIConfig(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/api/api-configs/")
+
+	var req models.UpdateAPIConfigRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := validation.ValidateAPIConfigUpdate(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if status, err := api.validateConnectorImageSettings(req.ImageSettings); err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	before, err := api.store.GetAPIConfig(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	config, err := api.store.UpdateAPIConfig(name, &req)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if err := apiExecutor.ReloadAPIConfigs(api.store); err != nil {
+		http.Error(w, fmt.Sprintf("API config updated but failed to reload: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	api.audit(r, before, config)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(config)
+}
+
+func (api *CronAPI) DeleteAPIConfig(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/api/api-configs/")
+
+	before, err := api.store.GetAPIConfig(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := api.store.DeleteAPIConfig(name); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
 
-func (api *CronAPI) GetAPIConfigs(w http.ResponseWriter, r *http.Request) {
-	configs, err := api.store.GetAllAPIConfigs()
+	if err := apiExecutor.ReloadAPIConfigs(api.store); err != nil {
+		http.Error(w, fmt.Sprintf("API config deleted but failed to reload: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	api.audit(r, before, nil)
+
+	response := models.CronResponse{
+		Status:  "success",
+		Message: "API config deleted successfully",
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// RetryMessagePost re-sends an already published repository to the APIs that
+// missed it. A partially successful message run marks the item as posted, so the
+// cron alone can never recover the connectors that failed.
+//
+// The response is always 200 once the retry has run: per-API outcomes carry the
+// individual failures, which are far more useful than a single status code.
+func (api *CronAPI) RetryMessagePost(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodOptions:
+		return
+	case http.MethodPost:
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.RetryMessageRequest
+	// The body is a short list of integration names and a url; anything larger is
+	// not a request this endpoint should read into memory.
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 8*1024)).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.DryRun {
+		preview, err := schedule.PreviewRetry(api.store, req.APIs, req.URL)
+		if err != nil {
+			if errors.Is(err, schedule.ErrInvalidRetryRequest) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(preview)
+		return
+	}
+
+	result, err := schedule.RetryMessagePost(api.store, req.APIs, req.URL)
 	if err != nil {
+		if errors.Is(err, schedule.ErrInvalidRetryRequest) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, schedule.ErrJobRunning) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(configs)
+	json.NewEncoder(w).Encode(result)
+}
+
+// PreviewMessage shows what the message job would send if it ran now, without
+// sending anything.
+func (api *CronAPI) PreviewMessage(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodOptions:
+		return
+	case http.MethodPost:
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	preview, err := schedule.PreviewMessage(api.store, "")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(preview)
+}
+
+func (api *CronAPI) HandleAPIConfigs(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodOptions:
+		return
+	case http.MethodGet:
+		api.GetAPIConfigs(w, r)
+	case http.MethodPost:
+		api.CreateAPIConfig(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (api *CronAPI) HandleAPIConfig(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodOptions:
+		return
+	case http.MethodGet:
+		api.GetAPIConfig(w, r)
+	case http.MethodPut:
+		api.UpdateAPIConfig(w, r)
+	case http.MethodDelete:
+		api.DeleteAPIConfig(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (api *CronAPI) HandleCrons(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodOptions:
+		return
+	case http.MethodGet:
+		api.GetCrons(w, r)
+	case http.MethodPost:
+		api.CreateCron(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleCron routes /api/crons/{name} and its /schedule, /status, /apis, /run
+// and /pause actions.
+func (api *CronAPI) HandleCron(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	_, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/crons/"), "/")
+	switch action {
+	case "schedule":
+		api.UpdateSchedule(w, r)
+	case "status":
+		api.UpdateStatus(w, r)
+	case "run":
+		api.RunCron(w, r)
+	case "pause":
+		api.PauseCron(w, r)
+	case "apis":
+		if r.Method != http.MethodPut {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		api.UpdateCronAPIs(w, r)
+	case "":
+		if r.Method != http.MethodDelete {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		api.DeleteCron(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// GetBlackoutWindows lists the blackout windows, flagging those in effect now.
+func (api *CronAPI) GetBlackoutWindows(w http.ResponseWriter, r *http.Request) {
+	windows, err := api.store.GetBlackoutWindows()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	for i := range windows {
+		windows[i].Active, _ = schedule.BlackoutCovers(windows[i], now)
+	}
+	if windows == nil {
+		windows = []models.BlackoutWindow{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(windows)
+}
+
+func (api *CronAPI) GetBlackoutWindow(w http.ResponseWriter, r *http.Request, id int64) {
+	window, err := api.store.GetBlackoutWindow(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if window == nil {
+		http.Error(w, "Blackout window not found", http.StatusNotFound)
+		return
+	}
+	window.Active, _ = schedule.BlackoutCovers(*window, time.Now())
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(window)
+}
+
+func (api *CronAPI) CreateBlackoutWindow(w http.ResponseWriter, r *http.Request) {
+	var req models.BlackoutWindowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := validation.ValidateBlackoutWindow(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	window, err := api.store.CreateBlackoutWindow(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	api.audit(r, nil, window)
+	window.Active, _ = schedule.BlackoutCovers(*window, time.Now())
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(window)
+}
+
+func (api *CronAPI) UpdateBlackoutWindow(w http.ResponseWriter, r *http.Request, id int64) {
+	var req models.BlackoutWindowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := validation.ValidateBlackoutWindow(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	before, err := api.store.GetBlackoutWindow(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	window, err := api.store.UpdateBlackoutWindow(id, &req)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	api.audit(r, before, window)
+	window.Active, _ = schedule.BlackoutCovers(*window, time.Now())
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(window)
+}
+
+func (api *CronAPI) DeleteBlackoutWindow(w http.ResponseWriter, r *http.Request, id int64) {
+	before, err := api.store.GetBlackoutWindow(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := api.store.DeleteBlackoutWindow(id); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	api.audit(r, before, nil)
+
+	response := models.CronResponse{
+		Status:  "success",
+		Message: "Blackout window deleted successfully",
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (api *CronAPI) HandleBlackouts(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodOptions:
+		return
+	case http.MethodGet:
+		api.GetBlackoutWindows(w, r)
+	case http.MethodPost:
+		api.CreateBlackoutWindow(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleBlackout routes /api/blackouts/{id}.
+func (api *CronAPI) HandleBlackout(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	id, err := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, "/api/blackouts/"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid blackout window id", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		api.GetBlackoutWindow(w, r, id)
+	case http.MethodPut:
+		api.UpdateBlackoutWindow(w, r, id)
+	case http.MethodDelete:
+		api.DeleteBlackoutWindow(w, r, id)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// GetCollectProfiles lists the collect profiles with the crons that run them.
+func (api *CronAPI) GetCollectProfiles(w http.ResponseWriter, r *http.Request) {
+	profiles, err := api.store.GetCollectProfiles()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	for i := range profiles {
+		if err := api.attachProfileCron(&profiles[i]); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	if profiles == nil {
+		profiles = []models.CollectProfile{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(profiles)
+}
+
+// attachProfileCron fills in the cron of a profile, with its next run.
+func (api *CronAPI) attachProfileCron(profile *models.CollectProfile) error {
+	setting, err := api.store.GetCronSetting(profile.Name)
+	if err != nil {
+		return err
+	}
+	if setting == nil {
+		return nil
+	}
+	if scheduler, _, exists := api.cron(setting.Name); exists {
+		setting.NextRun = schedule.NextRun(scheduler)
+	}
+	setting.Running = schedule.IsJobRunning(setting.Name)
+	profile.Cron = setting
+	return nil
 }
 
-func (api *CronAPI) GetAPIConfig(w http.ResponseWriter, r *http.Request) {
-	name := strings.TrimPrefix(r.URL.Path, "/api/api-configs/")
-
-	config, err := api.store.GetAPIConfig(name)
+func (api *CronAPI) GetCollectProfile(w http.ResponseWriter, r *http.Request, name string) {
+	profile, err := api.store.GetCollectProfile(name)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	if config == nil {
-		http.Error(w, "API config not found", http.StatusNotFound)
+	if profile == nil {
+		http.Error(w, "Collect profile not found", http.StatusNotFound)
+		return
+	}
+	if err := api.attachProfileCron(profile); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(config)
+	json.NewEncoder(w).Encode(profile)
 }
 
-func (api *CronAPI) CreateAPIConfig(w http.ResponseWriter, r *http.Request) {
-	var req models.CreateAPIConfigRequest
+// CreateCollectProfile adds a collect profile and starts the cron that runs it.
+func (api *CronAPI) CreateCollectProfile(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateCollectProfileRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	if err := validation.ValidateAPIConfig(&req); err != nil {
+	if err := validation.ValidateCollectProfileCreate(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := api.checkPromptVersionsExist(req.PromptVersions); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	config, err := api.store.CreateAPIConfig(&req)
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	existing, err := api.store.GetCronSetting(req.Name)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if existing != nil {
+		http.Error(w, fmt.Sprintf("Cron '%s' already exists", req.Name), http.StatusConflict)
+		return
+	}
 
-	if err := apiExecutor.ReloadAPIConfigs(api.store); err != nil {
-		http.Error(w, fmt.Sprintf("API config created but failed to reload: %v", err), http.StatusInternalServerError)
+	profile, err := api.store.CreateCollectProfile(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	setting, err := api.store.GetCronSetting(profile.Name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	job := schedule.CollectProfileJob(api.store, profile.Name)
+	scheduler := gocron.NewScheduler(time.UTC)
+	if err := schedule.StartScheduler(scheduler, api.store, setting, job); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	api.schedulers[profile.Name] = scheduler
+	api.jobs[profile.Name] = job
+	setting.NextRun = schedule.NextRun(scheduler)
+	profile.Cron = setting
+
+	api.audit(r, nil, profile)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(config)
+	json.NewEncoder(w).Encode(profile)
 }
 
-func (api *CronAPI) UpdateAPIConfig(w http.ResponseWriter, r *http.Request) {
-	name := strings.TrimPrefix(r.URL.Path, "/api/api-configs/")
-
-	var req models.UpdateAPIConfigRequest
+// UpdateCollectProfile changes what a profile collects. It takes effect from
+// the next run; the schedule is changed through /api/crons/{name}/schedule.
+func (api *CronAPI) UpdateCollectProfile(w http.ResponseWriter, r *http.Request, name string) {
+	var req models.UpdateCollectProfileRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	if err := validation.ValidateAPIConfigUpdate(&req); err != nil {
+	if err := validation.ValidateCollectProfileUpdate(&req); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if req.PromptVersions != nil {
+		if err := api.checkPromptVersionsExist(*req.PromptVersions); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
 
-	config, err := api.store.UpdateAPIConfig(name, &req)
+	before, err := api.store.GetCollectProfile(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	profile, err := api.store.UpdateCollectProfile(name, &req)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			http.Error(w, err.Error(), http.StatusNotFound)
@@ -436,19 +1766,30 @@ func (api *CronAPI) UpdateAPIConfig(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := apiExecutor.ReloadAPIConfigs(api.store); err != nil {
-		http.Error(w, fmt.Sprintf("API config updated but failed to reload: %v", err), http.StatusInternalServerError)
+	// Recorded before the cron is attached: the profile change is what the
+	// request made, and the cron is changed through its own endpoints.
+	api.audit(r, before, profile)
+	if err := api.attachProfileCron(profile); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(config)
+	json.NewEncoder(w).Encode(profile)
 }
 
-func (api *CronAPI) DeleteAPIConfig(w http.ResponseWriter, r *http.Request) {
-	name := strings.TrimPrefix(r.URL.Path, "/api/api-configs/")
+// DeleteCollectProfile removes a profile and stops its cron. Its history stays.
+func (api *CronAPI) DeleteCollectProfile(w http.ResponseWriter, r *http.Request, name string) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
 
-	if err := api.store.DeleteAPIConfig(name); err != nil {
+	before, err := api.store.GetCollectProfile(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := api.store.DeleteCollectProfile(name); err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			http.Error(w, err.Error(), http.StatusNotFound)
 		} else {
@@ -457,80 +1798,318 @@ func (api *CronAPI) DeleteAPIConfig(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := apiExecutor.ReloadAPIConfigs(api.store); err != nil {
-		http.Error(w, fmt.Sprintf("API config deleted but failed to reload: %v", err), http.StatusInternalServerError)
-		return
+	if scheduler, exists := api.schedulers[name]; exists {
+		scheduler.Stop()
+		scheduler.Clear()
+		delete(api.schedulers, name)
+		delete(api.jobs, name)
 	}
 
+	api.audit(r, before, nil)
+
 	response := models.CronResponse{
 		Status:  "success",
-		Message: "API config deleted successfully",
+		Message: "Collect profile deleted successfully",
 	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
-// RetryMessagePost re-sends an already published repository to the APIs that
-// missed it. A partially successful message run marks the item as posted, so the
-// cron alone can never recover the connectors that failed.
-//
-// The response is always 200 once the retry has run: per-API outcomes carry the
-// individual failures, which are far more useful than a single status code.
-func (api *CronAPI) RetryMessagePost(w http.ResponseWriter, r *http.Request) {
+func (api *CronAPI) HandleCollectProfiles(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodOptions:
 		return
+	case http.MethodGet:
+		api.GetCollectProfiles(w, r)
 	case http.MethodPost:
+		api.CreateCollectProfile(w, r)
 	default:
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// checkPromptVersionsExist reports the first assigned prompt version that does
+// not exist.
+func (api *CronAPI) checkPromptVersionsExist(versions []models.PromptVersionWeight) error {
+	for _, assigned := range versions {
+		version, err := api.store.GetPromptVersion(assigned.Name)
+		if err != nil {
+			return err
+		}
+		if version == nil {
+			return fmt.Errorf("prompt version '%s' not found", assigned.Name)
+		}
+	}
+	return nil
+}
+
+// HandleCollectProfile routes /api/collect-profiles/{name}.
+func (api *CronAPI) HandleCollectProfile(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
 		return
 	}
 
-	var req models.RetryMessageRequest
-	// The body is a short list of integration names and a url; anything larger is
-	// not a request this endpoint should read into memory.
-	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 8*1024)).Decode(&req); err != nil {
+	name := strings.TrimPrefix(r.URL.Path, "/api/collect-profiles/")
+	if name == "" || strings.Contains(name, "/") {
+		http.Error(w, "Collect profile not found", http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		api.GetCollectProfile(w, r, name)
+	case http.MethodPut:
+		api.UpdateCollectProfile(w, r, name)
+	case http.MethodDelete:
+		api.DeleteCollectProfile(w, r, name)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (api *CronAPI) GetPromptVersions(w http.ResponseWriter, r *http.Request) {
+	versions, err := api.store.GetPromptVersions()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if versions == nil {
+		versions = []models.PromptVersion{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(versions)
+}
+
+func (api *CronAPI) GetPromptVersion(w http.ResponseWriter, r *http.Request, name string) {
+	version, err := api.store.GetPromptVersion(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if version == nil {
+		http.Error(w, "Prompt version not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(version)
+}
+
+// CreatePromptVersion adds a prompt version. Versions cannot be changed
+// afterwards; a new prompt is a new version.
+func (api *CronAPI) CreatePromptVersion(w http.ResponseWriter, r *http.Request) {
+	var req models.CreatePromptVersionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	result, err := schedule.RetryMessagePost(api.store, req.APIs, req.URL)
+	if err := validation.ValidatePromptVersionCreate(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	existing, err := api.store.GetPromptVersion(req.Name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if existing != nil {
+		http.Error(w, fmt.Sprintf("Prompt version '%s' already exists", req.Name), http.StatusConflict)
+		return
+	}
+
+	version, err := api.store.CreatePromptVersion(&req)
 	if err != nil {
-		if errors.Is(err, schedule.ErrInvalidRetryRequest) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	api.audit(r, nil, version)
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(result)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(version)
 }
 
-func (api *CronAPI) HandleAPIConfigs(w http.ResponseWriter, r *http.Request) {
+func (api *CronAPI) DeletePromptVersion(w http.ResponseWriter, r *http.Request, name string) {
+	before, err := api.store.GetPromptVersion(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := api.store.DeletePromptVersion(name); err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case strings.Contains(err.Error(), "in use"):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	api.audit(r, before, nil)
+
+	response := models.CronResponse{
+		Status:  "success",
+		Message: "Prompt version deleted successfully",
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (api *CronAPI) HandlePromptVersions(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodOptions:
 		return
 	case http.MethodGet:
-		api.GetAPIConfigs(w, r)
+		api.GetPromptVersions(w, r)
 	case http.MethodPost:
-		api.CreateAPIConfig(w, r)
+		api.CreatePromptVersion(w, r)
 	default:
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 	}
 }
 
-func (api *CronAPI) HandleAPIConfig(w http.ResponseWriter, r *http.Request) {
+// HandlePromptVersion routes /api/prompt-versions/{name}.
+func (api *CronAPI) HandlePromptVersion(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, "/api/prompt-versions/")
+	if name == "" || strings.Contains(name, "/") {
+		http.Error(w, "Prompt version not found", http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		api.GetPromptVersion(w, r, name)
+	case http.MethodDelete:
+		api.DeletePromptVersion(w, r, name)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (api *CronAPI) GetAPITokens(w http.ResponseWriter, r *http.Request) {
+	tokens, err := api.store.GetAPITokens()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if tokens == nil {
+		tokens = []models.APIToken{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tokens)
+}
+
+// CreateAPIToken adds a named token. The token is only in this response; the
+// store keeps its hash, so a lost token has to be revoked and created again.
+func (api *CronAPI) CreateAPIToken(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateAPITokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := validation.ValidateAPITokenCreate(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	existing, err := api.store.GetAPIToken(req.Name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if existing != nil {
+		http.Error(w, fmt.Sprintf("API token '%s' already exists", req.Name), http.StatusConflict)
+		return
+	}
+
+	token, hash, err := middleware.NewToken()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	created, err := api.store.CreateAPIToken(&req, hash)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	api.audit(r, nil, created)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(models.CreateAPITokenResponse{APIToken: *created, Token: token})
+}
+
+// DeleteAPIToken revokes a named token.
+func (api *CronAPI) DeleteAPIToken(w http.ResponseWriter, r *http.Request, name string) {
+	before, err := api.store.GetAPIToken(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := api.store.DeleteAPIToken(name); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	api.audit(r, before, nil)
+
+	response := models.CronResponse{
+		Status:  "success",
+		Message: "API token revoked successfully",
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (api *CronAPI) HandleAPITokens(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodOptions:
 		return
 	case http.MethodGet:
-		api.GetAPIConfig(w, r)
-	case http.MethodPut:
-		api.UpdateAPIConfig(w, r)
+		api.GetAPITokens(w, r)
+	case http.MethodPost:
+		api.CreateAPIToken(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleAPIToken routes /api/tokens/{name}.
+func (api *CronAPI) HandleAPIToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, "/api/tokens/")
+	if name == "" || strings.Contains(name, "/") {
+		http.Error(w, "API token not found", http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
 	case http.MethodDelete:
-		api.DeleteAPIConfig(w, r)
+		api.DeleteAPIToken(w, r, name)
 	default:
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 	}
@@ -0,0 +1,332 @@
+package server
+
+import (
+	"content-maestro/internal/middleware"
+	"content-maestro/internal/models"
+	"content-maestro/internal/schedule"
+	"content-maestro/internal/store"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-co-op/gocron"
+)
+
+// newTestAPI serves the API the way main does, on a fresh store, behind
+// AuthMiddleware so changes are recorded under API_TOKEN's actor.
+func newTestAPI(t *testing.T) (*store.SQLiteStore, http.Handler) {
+	t.Helper()
+	t.Setenv("API_TOKEN", "admin-secret")
+
+	st, err := store.NewSQLiteStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteStore() error = %v", err)
+	}
+	t.Cleanup(func() { st.Close() })
+	if err := st.InitializeDefaultSettings(); err != nil {
+		t.Fatalf("InitializeDefaultSettings() error = %v", err)
+	}
+
+	schedulers := map[string]*gocron.Scheduler{}
+	t.Cleanup(func() {
+		for _, scheduler := range schedulers {
+			scheduler.Stop()
+		}
+	})
+	api := NewCronAPI(st, schedulers, models.JobRegistry{})
+
+	mux := http.NewServeMux()
+	for path, handler := range map[string]http.HandlerFunc{
+		"/api/crons":                     api.HandleCrons,
+		"/api/crons/":                    api.HandleCron,
+		"/api/collect-settings":          api.HandleCollectSettings,
+		"/api/collect-profiles":          api.HandleCollectProfiles,
+		"/api/collect-profiles/":         api.HandleCollectProfile,
+		"/api/prompt-settings":           api.HandlePromptSettings,
+		"/api/prompt-settings/rollback/": api.RollbackPromptSettings,
+		"/api/prompt-versions":           api.HandlePromptVersions,
+		"/api/prompt-versions/":          api.HandlePromptVersion,
+		"/api/image-settings":            api.HandleImageSettings,
+		"/api/api-configs":               api.HandleAPIConfigs,
+		"/api/api-configs/":              api.HandleAPIConfig,
+		"/api/blackouts":                 api.HandleBlackouts,
+		"/api/blackouts/":                api.HandleBlackout,
+		"/api/tokens":                    api.HandleAPITokens,
+		"/api/tokens/":                   api.HandleAPIToken,
+	} {
+		mux.Handle(path, middleware.AuthMiddleware(st, models.ScopeAdmin, handler))
+	}
+	return st, mux
+}
+
+func TestMutatingRoutesRecordAuditEntries(t *testing.T) {
+	st, handler := newTestAPI(t)
+
+	steps := []struct {
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{http.MethodPost, "/api/api-configs", `{"name": "telegram", "url": "https://api.example.com/telegram", "method": "POST", "content_type": "json", "timeout": 30, "success_code": 200, "enabled": true, "response_type": "json", "text_language": "en", "headers": "{\"X-Api-Key\": \"secret-key\"}"}`, http.StatusCreated},
+		{http.MethodPut, "/api/api-configs/telegram", `{"timeout": 60}`, http.StatusOK},
+		{http.MethodPost, "/api/crons", `{"name": "telegram-morning", "schedule": "0 9 1 1 *", "is_active": true, "apis": ["telegram"]}`, http.StatusCreated},
+		{http.MethodPut, "/api/crons/telegram-morning/apis", `{"apis": []}`, http.StatusOK},
+		{http.MethodPut, "/api/crons/telegram-morning/schedule", `{"schedule": "0 10 1 1 *"}`, http.StatusOK},
+		{http.MethodPut, "/api/crons/telegram-morning/pause", `{"until": "2099-01-01T00:00:00Z", "reason": "maintenance"}`, http.StatusOK},
+		{http.MethodDelete, "/api/crons/telegram-morning/pause", ``, http.StatusOK},
+		{http.MethodPut, "/api/crons/telegram-morning/status", `{"is_active": false}`, http.StatusOK},
+		{http.MethodDelete, "/api/crons/telegram-morning", ``, http.StatusOK},
+		{http.MethodPost, "/api/blackouts", `{"name": "incident", "kind": "range", "start": "2099-06-01T10:00:00Z", "end": "2099-06-01T14:00:00Z"}`, http.StatusCreated},
+		{http.MethodPut, "/api/blackouts/1", `{"name": "incident", "kind": "range", "start": "2099-06-01T10:00:00Z", "end": "2099-06-01T18:00:00Z"}`, http.StatusOK},
+		{http.MethodDelete, "/api/blackouts/1", ``, http.StatusOK},
+		{http.MethodPost, "/api/prompt-versions", `{"name": "concise", "content": "Describe the repository in two sentences."}`, http.StatusCreated},
+		{http.MethodPost, "/api/collect-profiles", `{"name": "rust-daily", "schedule": "30 8 * * *", "max_repos": 3, "since": "daily", "spoken_language_code": "en"}`, http.StatusCreated},
+		{http.MethodPut, "/api/collect-profiles/rust-daily", `{"max_repos": 5}`, http.StatusOK},
+		{http.MethodDelete, "/api/collect-profiles/rust-daily", ``, http.StatusOK},
+		{http.MethodDelete, "/api/prompt-versions/concise", ``, http.StatusOK},
+		{http.MethodPut, "/api/collect-settings", `{"max_repos": 10, "resource": "github", "since": "weekly", "spoken_language_code": "en", "period": "past_24_hours", "language": "All"}`, http.StatusOK},
+		{http.MethodPut, "/api/prompt-settings", `{"temperature": 0.5}`, http.StatusOK},
+		{http.MethodPost, "/api/prompt-settings/rollback/1", ``, http.StatusOK},
+		{http.MethodPut, "/api/image-settings", `{"theme": "Dark"}`, http.StatusOK},
+		{http.MethodPost, "/api/tokens", `{"name": "ops-bot", "scopes": ["crons"]}`, http.StatusCreated},
+		{http.MethodDelete, "/api/tokens/ops-bot", ``, http.StatusOK},
+		{http.MethodDelete, "/api/api-configs/telegram", ``, http.StatusOK},
+	}
+
+	for i, step := range steps {
+		name := step.method + " " + step.path
+		req := httptest.NewRequest(step.method, step.path, strings.NewReader(step.body))
+		req.Header.Set("Authorization", "Bearer admin-secret")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != step.status {
+			t.Fatalf("%s: expected status %d, got %d: %s", name, step.status, rec.Code, rec.Body.String())
+		}
+
+		count, err := st.GetAuditLogCount("", "", nil, nil)
+		if err != nil {
+			t.Fatalf("GetAuditLogCount() error = %v", err)
+		}
+		if count != i+1 {
+			t.Fatalf("%s: expected %d audit entries, got %d", name, i+1, count)
+		}
+
+		latest, err := st.GetAuditLog("", "", 0, 1, nil, nil)
+		if err != nil {
+			t.Fatalf("GetAuditLog() error = %v", err)
+		}
+		entry := latest[0]
+		if entry.Method != step.method || entry.Endpoint != step.path || entry.Actor != models.APITokenActor {
+			t.Errorf("%s: recorded %s %s by %q", name, entry.Method, entry.Endpoint, entry.Actor)
+		}
+	}
+}
+
+func TestAuditLogMasksAPIConfigHeaders(t *testing.T) {
+	st, handler := newTestAPI(t)
+
+	body := `{"name": "telegram", "url": "https://api.example.com/telegram", "method": "POST", "content_type": "json", "timeout": 30, "success_code": 200, "enabled": true, "response_type": "json", "text_language": "en", "headers": "{\"X-Api-Key\": \"secret-key\", \"X-Signature\": \"{env.SIGNATURE}\"}"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/api-configs", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer admin-secret")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+
+	entries, err := st.GetAuditLog("", "/api/api-configs", 0, 1, nil, nil)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("GetAuditLog() = %v, %v, want one entry", entries, err)
+	}
+	if strings.Contains(string(entries[0].After), "secret-key") {
+		t.Errorf("audit entry kept the header secret: %s", entries[0].After)
+	}
+
+	var after models.APIConfigModel
+	if err := json.Unmarshal(entries[0].After, &after); err != nil {
+		t.Fatalf("failed to decode audit entry: %v", err)
+	}
+	want := `{"X-Api-Key":"********","X-Signature":"{env.SIGNATURE}"}`
+	if after.Headers != want {
+		t.Errorf("recorded headers = %s, want %s", after.Headers, want)
+	}
+}
+
+func TestAuditLogMasksAPIConfigSecrets(t *testing.T) {
+	st, handler := newTestAPI(t)
+
+	body := `{"name": "telegram", "url": "https://api.telegram.org/bot123:url-token/sendMessage", "method": "POST", "content_type": "json", "timeout": 30, "success_code": 200, "enabled": true, "response_type": "json", "text_language": "en", "default_json_body": "{\"api_key\": \"body-key\", \"chat_id\": \"{env.CHAT_ID}\"}", "body_template": "{\"token\": \"template-token\", \"text\": {{json .Item.Text}}}"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/api-configs", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer admin-secret")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+
+	entries, err := st.GetAuditLog("", "/api/api-configs", 0, 1, nil, nil)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("GetAuditLog() = %v, %v, want one entry", entries, err)
+	}
+	for _, secret := range []string{"url-token", "body-key", "template-token"} {
+		if strings.Contains(string(entries[0].After), secret) {
+			t.Errorf("audit entry kept %q: %s", secret, entries[0].After)
+		}
+	}
+
+	var after models.APIConfigModel
+	if err := json.Unmarshal(entries[0].After, &after); err != nil {
+		t.Fatalf("failed to decode audit entry: %v", err)
+	}
+	if want := "https://api.telegram.org/********/********"; after.URL != want {
+		t.Errorf("recorded url = %s, want %s", after.URL, want)
+	}
+	if want := `{"api_key":"********","chat_id":"{env.CHAT_ID}"}`; after.DefaultJSONBody != want {
+		t.Errorf("recorded default_json_body = %s, want %s", after.DefaultJSONBody, want)
+	}
+	if want := "********"; after.BodyTemplate != want {
+		t.Errorf("recorded body_template = %s, want %s", after.BodyTemplate, want)
+	}
+}
+
+// The static provider is checked against the settings that take effect, not
+// only the fields a request sets: a static_file set earlier, by the global
+// settings, counts, and one removed there breaks a connector relying on it.
+func TestImageSettingsRequireStaticFile(t *testing.T) {
+	_, handler := newTestAPI(t)
+	t.Chdir(t.TempDir())
+	if err := os.WriteFile("card.png", []byte("png"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, step := range []struct {
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{http.MethodPut, "/api/image-settings", `{"provider": "static"}`, http.StatusBadRequest},
+		{http.MethodPost, "/api/api-configs", `{"name": "telegram", "url": "https://api.example.com/telegram", "method": "POST", "content_type": "json", "timeout": 30, "success_code": 200, "enabled": true, "response_type": "json", "text_language": "en", "image_settings": {"provider": "static"}}`, http.StatusBadRequest},
+		{http.MethodPut, "/api/image-settings", `{"provider": "static", "static_file": "card.png"}`, http.StatusOK},
+		{http.MethodPut, "/api/image-settings", `{"static_file": ""}`, http.StatusBadRequest},
+		{http.MethodPut, "/api/image-settings", `{"provider": "socialify"}`, http.StatusOK},
+		{http.MethodPost, "/api/api-configs", `{"name": "telegram", "url": "https://api.example.com/telegram", "method": "POST", "content_type": "json", "timeout": 30, "success_code": 200, "enabled": true, "response_type": "json", "text_language": "en", "image_settings": {"provider": "static"}}`, http.StatusCreated},
+		{http.MethodPut, "/api/image-settings", `{"static_file": ""}`, http.StatusBadRequest},
+		{http.MethodPut, "/api/api-configs/telegram", `{"image_settings": {"provider": "static", "static_file": ""}}`, http.StatusBadRequest},
+		{http.MethodPut, "/api/api-configs/telegram", `{"image_settings": {}}`, http.StatusOK},
+		{http.MethodPut, "/api/image-settings", `{"static_file": ""}`, http.StatusOK},
+	} {
+		req := httptest.NewRequest(step.method, step.path, strings.NewReader(step.body))
+		req.Header.Set("Authorization", "Bearer admin-secret")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != step.status {
+			t.Fatalf("%s %s %s: expected status %d, got %d: %s", step.method, step.path, step.body, step.status, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+func TestUpdateScheduleSavesTogether(t *testing.T) {
+	st, handler := newTestAPI(t)
+
+	for _, step := range []struct {
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{http.MethodPost, "/api/crons", `{"name": "telegram-morning", "schedule": "0 9 1 1 *", "is_active": true}`, http.StatusCreated},
+		{http.MethodPut, "/api/crons/telegram-morning/pause", `{"until": "2099-01-01T00:00:00Z", "reason": "maintenance"}`, http.StatusOK},
+		{http.MethodPut, "/api/crons/telegram-morning/schedule", `{"schedule": "0 10 * * *", "timezone": "Europe/Kyiv", "jitter_seconds": 600}`, http.StatusOK},
+		// Too much jitter for an hourly schedule: nothing of the request is saved.
+		{http.MethodPut, "/api/crons/telegram-morning/schedule", `{"schedule": "0 * * * *", "timezone": "UTC", "jitter_seconds": 3000}`, http.StatusBadRequest},
+	} {
+		req := httptest.NewRequest(step.method, step.path, strings.NewReader(step.body))
+		req.Header.Set("Authorization", "Bearer admin-secret")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != step.status {
+			t.Fatalf("%s %s: expected status %d, got %d: %s", step.method, step.path, step.status, rec.Code, rec.Body.String())
+		}
+	}
+
+	setting, err := st.GetCronSetting("telegram-morning")
+	if err != nil || setting == nil {
+		t.Fatalf("GetCronSetting() = %v, %v", setting, err)
+	}
+	if setting.Schedule != "0 10 * * *" || setting.Timezone != "Europe/Kyiv" || setting.JitterSeconds != 600 {
+		t.Errorf("saved schedule %q in %s with %ds jitter, want 0 10 * * * in Europe/Kyiv with 600s", setting.Schedule, setting.Timezone, setting.JitterSeconds)
+	}
+	if setting.PausedUntil == nil {
+		t.Error("a schedule change lifted the pause")
+	}
+
+	req := httptest.NewRequest(http.MethodPut, "/api/crons/telegram-morning/status", strings.NewReader(`{"is_active": false}`))
+	req.Header.Set("Authorization", "Bearer admin-secret")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	setting, err = st.GetCronSetting("telegram-morning")
+	if err != nil || setting == nil {
+		t.Fatalf("GetCronSetting() = %v, %v", setting, err)
+	}
+	if setting.IsActive || setting.PausedUntil != nil {
+		t.Errorf("active = %v, paused until %v, want switched off with the pause lifted", setting.IsActive, setting.PausedUntil)
+	}
+}
+
+func TestRunCronDryRun(t *testing.T) {
+	_, handler := newTestAPI(t)
+
+	for _, step := range []struct {
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{http.MethodPost, "/api/api-configs", `{"name": "telegram", "url": "https://api.example.com/telegram", "method": "POST", "content_type": "json", "timeout": 30, "success_code": 200, "enabled": false, "response_type": "json", "text_language": "en"}`, http.StatusCreated},
+		{http.MethodPost, "/api/crons", `{"name": "telegram-morning", "schedule": "0 9 1 1 *", "is_active": true, "apis": ["telegram"]}`, http.StatusCreated},
+		{http.MethodPost, "/api/collect-profiles", `{"name": "rust-daily", "schedule": "30 8 * * *", "max_repos": 3, "since": "daily", "spoken_language_code": "en"}`, http.StatusCreated},
+	} {
+		req := httptest.NewRequest(step.method, step.path, strings.NewReader(step.body))
+		req.Header.Set("Authorization", "Bearer admin-secret")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != step.status {
+			t.Fatalf("%s %s: expected status %d, got %d: %s", step.method, step.path, step.status, rec.Code, rec.Body.String())
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/crons/telegram-morning/run", strings.NewReader(`{"dry_run": true}`))
+	req.Header.Set("Authorization", "Bearer admin-secret")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var preview schedule.MessagePreview
+	if err := json.Unmarshal(rec.Body.Bytes(), &preview); err != nil {
+		t.Fatalf("failed to decode preview: %v", err)
+	}
+	if preview.Connectors == nil || len(preview.Connectors) != 0 {
+		t.Errorf("connectors = %+v, want none: the schedule's only connector is disabled", preview.Connectors)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/crons/rust-daily/run", strings.NewReader(`{"dry_run": true}`))
+	req.Header.Set("Authorization", "Bearer admin-secret")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("collect dry run: expected status %d, got %d: %s", http.StatusBadRequest, rec.Code, rec.Body.String())
+	}
+}
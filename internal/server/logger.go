@@ -0,0 +1,5 @@
+package server
+
+import "content-maestro/internal/logger"
+
+var log = logger.NewLogger()
@@ -2,88 +2,69 @@ package socialify
 
 import (
 	"content-maestro/internal/logger"
+	"content-maestro/internal/models"
+	"content-maestro/internal/render"
 	"fmt"
+	"image/png"
 	"io"
-	"math/rand"
+	"math/rand/v2"
 	"net/http"
 	"net/url"
 	"os"
+	"slices"
+	"strings"
 	"time"
 )
 
 var log = logger.NewLogger()
 
+// socialifyWidth is the width of the images socialify serves.
+const socialifyWidth = 1280
+
 // A timeout is required, not cosmetic: without one a hung upstream connection
 // bounds neither an attempt nor the caller, and the manual retry endpoint answers
 // an HTTP request synchronously.
 var SocialifyHTTPClient = &http.Client{Timeout: 30 * time.Second}
 
-type RetryConfig struct {
-	MaxRetries    int
-	RetryInterval time.Duration
-}
-
-var defaultConfig = RetryConfig{
-	MaxRetries:    5,
-	RetryInterval: 20 * time.Second,
-}
-
-var currentConfig = defaultConfig
-
-func SetRetryConfig(config RetryConfig) {
-	currentConfig = config
-}
-
-func ResetRetryConfig() {
-	currentConfig = defaultConfig
-}
-
-func Socialify(usernameRepo string, outputPath string) error {
-	return SocialifyWithConfig(usernameRepo, outputPath, currentConfig)
-}
-
-// SocialifyWithConfig runs the image generation with a caller-supplied retry
-// budget. Callers that answer a synchronous request use it to avoid the default
-// budget, which can block for minutes, without mutating the shared config.
-func SocialifyWithConfig(usernameRepo string, outputPath string, config RetryConfig) error {
-	log.Debug("Starting Socialify image parsing")
+// DefaultURL is the public socialify instance.
+const DefaultURL = "https://socialify.git.ci"
 
-	if config.MaxRetries < 1 {
-		config.MaxRetries = 1
+// ImageURL is the URL of the card for usernameRepo on the socialify instance at
+// baseURL, the public one when it is empty. The pattern is picked at random
+// from settings.Patterns.
+func ImageURL(baseURL, usernameRepo string, settings models.ImageSettings) string {
+	if baseURL == "" {
+		baseURL = DefaultURL
 	}
 
-	var lastErr error
-	for attempt := 1; attempt <= config.MaxRetries; attempt++ {
-		err := trySocialify(usernameRepo, outputPath)
-		if err == nil {
-			log.Debug("Socialify image parsing finished")
-			return nil
-		}
-
-		lastErr = err
-		if attempt < config.MaxRetries {
-			log.Errorf("Attempt %d failed: %v. Retrying in %s...", attempt, err, config.RetryInterval)
-			time.Sleep(config.RetryInterval)
+	query := url.Values{}
+	query.Set("theme", settings.Theme)
+	query.Set("font", settings.Font)
+	if len(settings.Patterns) > 0 {
+		query.Set("pattern", settings.Patterns[rand.IntN(len(settings.Patterns))])
+	}
+	if settings.Logo != "" {
+		query.Set("logo", settings.Logo)
+	}
+	for _, field := range models.ImageFields {
+		if slices.Contains(settings.Fields, field) {
+			query.Set(field, "1")
+		} else {
+			query.Set(field, "0")
 		}
 	}
 
-	log.Debugf("All %d attempts failed. Last error: %v", config.MaxRetries, lastErr)
-	return lastErr
+	// Socialify serves PNG at its own size; other formats and sizes are
+	// converted locally.
+	return fmt.Sprintf("%s/%s/png?%s", strings.TrimRight(baseURL, "/"), usernameRepo, query.Encode())
 }
 
-func trySocialify(usernameRepo string, outputPath string) error {
-	patternsArray := []string{"Diagonal Stripes", "Charlie Brown", "Brick Wall", "Circuit Board", "Formal Invitation"}
-
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
-	randomPattern := patternsArray[rng.Intn(len(patternsArray))]
-
-	escapedPattern := url.QueryEscape(randomPattern)
-	socialifyUrl := fmt.Sprintf(
-		"https://socialify.git.ci/%s/png?description=0&font=Jost&forks=1&issues=1&language=1&name=1&owner=1&pattern=%s&pulls=1&stargazers=1&theme=Light",
-		usernameRepo, escapedPattern,
-	)
+// Fetch downloads the card for usernameRepo into outputPath in a single
+// attempt; retrying is up to the caller.
+func Fetch(baseURL, usernameRepo, outputPath string, settings models.ImageSettings) error {
+	log.Debug("Starting Socialify image parsing")
 
-	req, err := http.NewRequest("GET", socialifyUrl, nil)
+	req, err := http.NewRequest("GET", ImageURL(baseURL, usernameRepo, settings), nil)
 	if err != nil {
 		log.Error(err)
 		return err
@@ -105,6 +86,20 @@ func trySocialify(usernameRepo string, outputPath string) error {
 		return err
 	}
 
+	if needsConversion(settings) {
+		img, err := png.Decode(response.Body)
+		if err != nil {
+			err = fmt.Errorf("failed to decode socialify image: %w", err)
+			log.Error(err)
+			return err
+		}
+		if err := render.Save(img, outputPath, settings.Format, settings.Width); err != nil {
+			log.Error(err)
+			return err
+		}
+		return nil
+	}
+
 	file, err := os.Create(outputPath)
 	if err != nil {
 		log.Error(err)
@@ -118,5 +113,12 @@ func trySocialify(usernameRepo string, outputPath string) error {
 		return err
 	}
 
+	log.Debug("Socialify image parsing finished")
 	return nil
 }
+
+// needsConversion reports whether the PNG socialify serves has to be converted
+// to meet the settings.
+func needsConversion(settings models.ImageSettings) bool {
+	return settings.Format != models.ImageFormatPNG || settings.Width != socialifyWidth
+}
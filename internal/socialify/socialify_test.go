@@ -2,26 +2,25 @@ package socialify
 
 import (
 	"bytes"
+	"content-maestro/internal/models"
+	"image"
+	"image/jpeg"
+	"image/png"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"slices"
 	"testing"
-	"time"
 )
 
 func setupTestEnvironment(t *testing.T) func() {
-	SetRetryConfig(RetryConfig{
-		MaxRetries:    2,
-		RetryInterval: 100 * time.Millisecond,
-	})
-
 	err := os.MkdirAll("./tmp/gh_project_img", 0755)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	return func() {
-		ResetRetryConfig()
 		os.RemoveAll("./tmp")
 	}
 }
@@ -38,7 +37,7 @@ func (m *mockTransport) RoundTrip(*http.Request) (*http.Response, error) {
 	return m.response, nil
 }
 
-func TestSocialify(t *testing.T) {
+func TestFetch(t *testing.T) {
 	cleanup := setupTestEnvironment(t)
 	defer cleanup()
 
@@ -82,14 +81,14 @@ func TestSocialify(t *testing.T) {
 			SocialifyHTTPClient = client
 			defer func() { SocialifyHTTPClient = oldClient }()
 
-			err := Socialify(tt.usernameRepo, "./tmp/gh_project_img/image.png")
+			err := Fetch("", tt.usernameRepo, "./tmp/gh_project_img/image.png", models.DefaultImageSettings())
 
 			if (err != nil) != tt.expectedError {
-				t.Errorf("Socialify() error = %v, expectedError %v", err, tt.expectedError)
+				t.Errorf("Fetch() error = %v, expectedError %v", err, tt.expectedError)
 			} else if tt.expectedError {
 				t.Logf("Expected error received: %v", err)
 			} else {
-				t.Logf("Successfully executed Socialify for %s", tt.usernameRepo)
+				t.Logf("Successfully fetched the image for %s", tt.usernameRepo)
 			}
 
 			if !tt.expectedError {
@@ -107,21 +106,15 @@ func TestSocialify(t *testing.T) {
 	}
 }
 
-func TestSocialifyInvalidPath(t *testing.T) {
-	t.Log("Starting TestSocialifyInvalidPath test")
-
-	SetRetryConfig(RetryConfig{
-		MaxRetries:    1,
-		RetryInterval: 100 * time.Millisecond,
-	})
-	defer ResetRetryConfig()
+func TestFetchInvalidPath(t *testing.T) {
+	t.Log("Starting TestFetchInvalidPath test")
 
 	originalDir := "./tmp/gh_project_img"
 	if err := os.RemoveAll(originalDir); err != nil {
 		t.Logf("Error removing directory (if exists): %v", err)
 	}
 
-	err := Socialify("test/repo", "./tmp/gh_project_img/image.png")
+	err := Fetch("", "test/repo", "./tmp/gh_project_img/image.png", models.DefaultImageSettings())
 
 	if err == nil {
 		t.Error("Expected error when directory doesn't exist, got nil")
@@ -141,3 +134,91 @@ func TestSocialifyInvalidPath(t *testing.T) {
 		t.Log("Verified directory still doesn't exist after test")
 	}
 }
+
+func TestImageURL(t *testing.T) {
+	settings := models.ImageSettings{
+		Theme:    "Dark",
+		Font:     "Inter",
+		Patterns: []string{"Plus", "Circuit Board"},
+		Logo:     "https://example.com/logo.svg",
+		Fields:   []string{"name", "description", "stargazers"},
+		Format:   models.ImageFormatPNG,
+		Width:    1280,
+	}
+
+	parsed, err := url.Parse(ImageURL("", "test/repo", settings))
+	if err != nil {
+		t.Fatalf("ImageURL() is not a URL: %v", err)
+	}
+	if parsed.Path != "/test/repo/png" {
+		t.Errorf("path = %s, want /test/repo/png", parsed.Path)
+	}
+
+	query := parsed.Query()
+	want := map[string]string{
+		"theme": "Dark", "font": "Inter", "logo": "https://example.com/logo.svg",
+		"name": "1", "description": "1", "stargazers": "1",
+		"owner": "0", "language": "0", "forks": "0", "issues": "0", "pulls": "0",
+	}
+	for key, value := range want {
+		if got := query.Get(key); got != value {
+			t.Errorf("%s = %q, want %q", key, got, value)
+		}
+	}
+	if pattern := query.Get("pattern"); !slices.Contains(settings.Patterns, pattern) {
+		t.Errorf("pattern = %q, want one of %v", pattern, settings.Patterns)
+	}
+
+	parsed, err = url.Parse(ImageURL("https://socialify.example.com/", "test/repo", settings))
+	if err != nil {
+		t.Fatalf("ImageURL() is not a URL: %v", err)
+	}
+	if parsed.Host != "socialify.example.com" || parsed.Path != "/test/repo/png" {
+		t.Errorf("self-hosted URL = %s, want socialify.example.com/test/repo/png", parsed)
+	}
+
+	settings.Logo = ""
+	if parsed, _ := url.Parse(ImageURL("", "test/repo", settings)); parsed.Query().Has("logo") {
+		t.Error("an empty logo should be left out")
+	}
+}
+
+func TestFetchConvertsImage(t *testing.T) {
+	cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	var served bytes.Buffer
+	if err := png.Encode(&served, image.NewRGBA(image.Rect(0, 0, 1280, 640))); err != nil {
+		t.Fatal(err)
+	}
+
+	oldClient := SocialifyHTTPClient
+	SocialifyHTTPClient = &http.Client{Transport: &mockTransport{response: &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(&served),
+	}}}
+	defer func() { SocialifyHTTPClient = oldClient }()
+
+	settings := models.DefaultImageSettings()
+	settings.Format = models.ImageFormatJPEG
+	settings.Width = 640
+
+	path := "./tmp/gh_project_img/image.jpg"
+	if err := Fetch("", "test/repo", path, settings); err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	config, err := jpeg.DecodeConfig(file)
+	if err != nil {
+		t.Fatalf("image is not a JPEG: %v", err)
+	}
+	if config.Width != 640 || config.Height != 320 {
+		t.Errorf("image is %dx%d, want 640x320", config.Width, config.Height)
+	}
+}
@@ -0,0 +1,112 @@
+package store
+
+import (
+	"content-maestro/internal/models"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteStore_APIConfigHeaders(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	created, err := store.CreateAPIConfig(&models.CreateAPIConfigRequest{
+		Name: "mastodon", URL: "https://mastodon.social/api/v1/statuses", Method: "POST",
+		ContentType: "json", Timeout: 30, SuccessCode: 200, Enabled: true,
+		Headers: `{"Idempotency-Key": "{env.MASTODON_KEY}"}`,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `{"Idempotency-Key": "{env.MASTODON_KEY}"}`, created.Headers)
+
+	headers := `{"X-Trace": "maestro"}`
+	updated, err := store.UpdateAPIConfig("mastodon", &models.UpdateAPIConfigRequest{Headers: &headers})
+	require.NoError(t, err)
+	assert.Equal(t, headers, updated.Headers)
+
+	timeout := 60
+	updated, err = store.UpdateAPIConfig("mastodon", &models.UpdateAPIConfigRequest{Timeout: &timeout})
+	require.NoError(t, err)
+	assert.Equal(t, headers, updated.Headers, "an update without headers keeps them")
+}
+
+// Configs migrated from YAML before the headers column existed lost their
+// headers; adding the column restores them from the file.
+func TestSQLiteStore_HeadersBackfilledFromYAML(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(yamlAPIConfigPath), 0o755))
+	require.NoError(t, os.WriteFile(yamlAPIConfigPath, []byte(`apis:
+  slack:
+    url: "https://hooks.slack.com/services/x"
+    method: "POST"
+    content_type: "json"
+    headers:
+      X-Source: "{env.SOURCE}"
+`), 0o644))
+
+	dbPath := filepath.Join(dir, "old.db")
+	db, err := sql.Open("sqlite", dbPath)
+	require.NoError(t, err)
+	_, err = db.Exec(`
+		CREATE TABLE api_configs (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL UNIQUE,
+			url TEXT NOT NULL,
+			method TEXT NOT NULL,
+			auth_type TEXT,
+			token_env_var TEXT,
+			token_header TEXT,
+			content_type TEXT NOT NULL,
+			timeout INTEGER NOT NULL DEFAULT 30,
+			success_code INTEGER NOT NULL DEFAULT 200,
+			enabled INTEGER NOT NULL DEFAULT 1,
+			response_type TEXT,
+			text_language TEXT,
+			socialify_image INTEGER NOT NULL DEFAULT 0,
+			default_json_body TEXT,
+			updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO api_configs (name, url, method, auth_type, token_env_var, token_header,
+		content_type, response_type, text_language, default_json_body)
+		VALUES ('slack', 'https://hooks.slack.com/services/x', 'POST', '', '', '', 'json', '', '', '')`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	store, err := NewSQLiteStore(dbPath)
+	require.NoError(t, err)
+	defer store.Close()
+
+	config, err := store.GetAPIConfig("slack")
+	require.NoError(t, err)
+	require.NotNil(t, config)
+	assert.JSONEq(t, `{"X-Source": "{env.SOURCE}"}`, config.Headers)
+	assert.Equal(t, models.DefaultRetryMaxAttempts, config.RetryMaxAttempts)
+}
+
+func TestSQLiteStore_APIConfigSuccessRule(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	created, err := store.CreateAPIConfig(&models.CreateAPIConfigRequest{
+		Name: "telegram", URL: "https://example.com", Method: "POST",
+		ContentType: "json", Timeout: 30, SuccessCode: 200,
+		SuccessCodes: []int{200, 201}, SuccessField: "$.status", SuccessValue: "ok",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{200, 201}, created.SuccessCodes)
+	assert.Equal(t, "$.status", created.SuccessField)
+	assert.Equal(t, "ok", created.SuccessValue)
+
+	cleared := []int{}
+	updated, err := store.UpdateAPIConfig("telegram", &models.UpdateAPIConfigRequest{SuccessCodes: &cleared})
+	require.NoError(t, err)
+	assert.Empty(t, updated.SuccessCodes)
+	assert.Equal(t, "$.status", updated.SuccessField)
+}
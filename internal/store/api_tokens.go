@@ -0,0 +1,94 @@
+package store
+
+import (
+	"content-maestro/internal/models"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const apiTokenColumns = "name, scopes, created_at"
+
+func scanAPIToken(row rowScanner) (*models.APIToken, error) {
+	var t models.APIToken
+	var scopes string
+	if err := row.Scan(&t.Name, &scopes, &t.CreatedAt); err != nil {
+		return nil, err
+	}
+	t.Scopes = parseNameList(scopes)
+	return &t, nil
+}
+
+func (s *SQLiteStore) GetAPITokens() ([]models.APIToken, error) {
+	rows, err := s.db.Query("SELECT " + apiTokenColumns + " FROM api_tokens ORDER BY name ASC")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api tokens: %v", err)
+	}
+	defer rows.Close()
+
+	var tokens []models.APIToken
+	for rows.Next() {
+		token, err := scanAPIToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan api token: %v", err)
+		}
+		tokens = append(tokens, *token)
+	}
+	return tokens, rows.Err()
+}
+
+func (s *SQLiteStore) GetAPIToken(name string) (*models.APIToken, error) {
+	query := "SELECT " + apiTokenColumns + " FROM api_tokens WHERE name = ?"
+	token, err := scanAPIToken(s.db.QueryRow(query, name))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api token: %v", err)
+	}
+	return token, nil
+}
+
+// GetAPITokenByHash returns the token whose hash is hash, or nil if no token
+// has it.
+func (s *SQLiteStore) GetAPITokenByHash(hash string) (*models.APIToken, error) {
+	query := "SELECT " + apiTokenColumns + " FROM api_tokens WHERE token_hash = ?"
+	token, err := scanAPIToken(s.db.QueryRow(query, hash))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api token: %v", err)
+	}
+	return token, nil
+}
+
+// CreateAPIToken stores a named token by the hash of the token. The token
+// itself is never stored.
+func (s *SQLiteStore) CreateAPIToken(req *models.CreateAPITokenRequest, hash string) (*models.APIToken, error) {
+	query := "INSERT INTO api_tokens (name, token_hash, scopes, created_at) VALUES (?, ?, ?, ?)"
+	if _, err := s.db.Exec(query, req.Name, hash, strings.Join(req.Scopes, ","), time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to create api token: %v", err)
+	}
+	return s.GetAPIToken(req.Name)
+}
+
+// DeleteAPIToken revokes a named token. Requests made with it are rejected from
+// then on.
+func (s *SQLiteStore) DeleteAPIToken(name string) error {
+	result, err := s.db.Exec("DELETE FROM api_tokens WHERE name = ?", name)
+	if err != nil {
+		return fmt.Errorf("failed to delete api token: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("api token '%s' not found", name)
+	}
+
+	return nil
+}
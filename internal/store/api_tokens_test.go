@@ -0,0 +1,55 @@
+package store
+
+import (
+	"content-maestro/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteStore_APITokens(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	created, err := store.CreateAPIToken(&models.CreateAPITokenRequest{
+		Name:   "ops-bot",
+		Scopes: []string{models.ScopeCrons, models.ScopeRetry},
+	}, "hash-of-ops-bot")
+	require.NoError(t, err)
+	assert.Equal(t, "ops-bot", created.Name)
+	assert.Equal(t, []string{"crons", "retry"}, created.Scopes)
+	assert.False(t, created.CreatedAt.IsZero())
+
+	_, err = store.CreateAPIToken(&models.CreateAPITokenRequest{Name: "ops-bot", Scopes: []string{models.ScopeReadOnly}}, "another-hash")
+	assert.Error(t, err, "names are unique")
+	_, err = store.CreateAPIToken(&models.CreateAPITokenRequest{Name: "dashboard", Scopes: []string{models.ScopeReadOnly}}, "hash-of-ops-bot")
+	assert.Error(t, err, "hashes are unique")
+
+	_, err = store.CreateAPIToken(&models.CreateAPITokenRequest{Name: "dashboard", Scopes: []string{models.ScopeReadOnly}}, "hash-of-dashboard")
+	require.NoError(t, err)
+
+	tokens, err := store.GetAPITokens()
+	require.NoError(t, err)
+	require.Len(t, tokens, 2)
+	assert.Equal(t, "dashboard", tokens[0].Name)
+	assert.Equal(t, "ops-bot", tokens[1].Name)
+
+	found, err := store.GetAPITokenByHash("hash-of-ops-bot")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, "ops-bot", found.Name)
+
+	missing, err := store.GetAPITokenByHash("unknown")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	require.NoError(t, store.DeleteAPIToken("ops-bot"))
+	revoked, err := store.GetAPITokenByHash("hash-of-ops-bot")
+	require.NoError(t, err)
+	assert.Nil(t, revoked, "a revoked token no longer authenticates")
+
+	err = store.DeleteAPIToken("ops-bot")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
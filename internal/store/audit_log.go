@@ -0,0 +1,103 @@
+package store
+
+import (
+	"content-maestro/internal/models"
+	"fmt"
+	"time"
+)
+
+const auditEntryColumns = "id, actor, method, endpoint, before_state, after_state, created_at"
+
+func scanAuditEntry(row rowScanner) (*models.AuditEntry, error) {
+	var e models.AuditEntry
+	var before, after string
+	if err := row.Scan(&e.ID, &e.Actor, &e.Method, &e.Endpoint, &before, &after, &e.CreatedAt); err != nil {
+		return nil, err
+	}
+	e.Before = []byte(before)
+	e.After = []byte(after)
+	return &e, nil
+}
+
+// auditLogFilter builds the WHERE clause shared by the audit log queries.
+// endpoint matches entries whose endpoint starts with it, so a collection
+// path covers the changes of all its items.
+func auditLogFilter(actor, endpoint string, startDate, endDate *time.Time) (string, []any) {
+	where := " WHERE 1=1"
+	args := []any{}
+
+	if actor != "" {
+		where += " AND actor = ?"
+		args = append(args, actor)
+	}
+	if endpoint != "" {
+		where += " AND substr(endpoint, 1, length(?)) = ?"
+		args = append(args, endpoint, endpoint)
+	}
+	if startDate != nil {
+		where += " AND created_at >= ?"
+		args = append(args, *startDate)
+	}
+	if endDate != nil {
+		where += " AND created_at <= ?"
+		args = append(args, endDate.Add(24*time.Hour-time.Nanosecond))
+	}
+	return where, args
+}
+
+// auditState stores a missing state as JSON null, so every stored state can be
+// served as it is.
+func auditState(state []byte) string {
+	if len(state) == 0 {
+		return "null"
+	}
+	return string(state)
+}
+
+func (s *SQLiteStore) RecordAuditEntry(entry *models.AuditEntry) error {
+	createdAt := entry.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO audit_log (actor, method, endpoint, before_state, after_state, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)`
+	if _, err := s.db.Exec(query, entry.Actor, entry.Method, entry.Endpoint, auditState(entry.Before), auditState(entry.After), createdAt); err != nil {
+		return fmt.Errorf("failed to record audit entry: %v", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) GetAuditLogCount(actor, endpoint string, startDate, endDate *time.Time) (int, error) {
+	where, args := auditLogFilter(actor, endpoint, startDate, endDate)
+
+	var count int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM audit_log"+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count audit log: %v", err)
+	}
+	return count, nil
+}
+
+// GetAuditLog lists audit entries, newest first.
+func (s *SQLiteStore) GetAuditLog(actor, endpoint string, offset, limit int, startDate, endDate *time.Time) ([]models.AuditEntry, error) {
+	where, args := auditLogFilter(actor, endpoint, startDate, endDate)
+	query := "SELECT " + auditEntryColumns + " FROM audit_log" + where + " ORDER BY id DESC LIMIT ? OFFSET ?"
+	args = append(args, limit, offset)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit log: %v", err)
+	}
+	defer rows.Close()
+
+	var entries []models.AuditEntry
+	for rows.Next() {
+		entry, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %v", err)
+		}
+		entries = append(entries, *entry)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,87 @@
+package store
+
+import (
+	"content-maestro/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteStore_AuditLog(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	yesterday := time.Now().Add(-24 * time.Hour)
+	require.NoError(t, store.RecordAuditEntry(&models.AuditEntry{
+		Actor:     "api_token",
+		Method:    "PUT",
+		Endpoint:  "/api/crons/collect/schedule",
+		Before:    []byte(`{"schedule":"0 * * * *"}`),
+		After:     []byte(`{"schedule":"30 * * * *"}`),
+		CreatedAt: yesterday,
+	}))
+	require.NoError(t, store.RecordAuditEntry(&models.AuditEntry{
+		Actor:    "api_token",
+		Method:   "POST",
+		Endpoint: "/api/api-configs",
+		After:    []byte(`{"name":"telegram"}`),
+	}))
+	require.NoError(t, store.RecordAuditEntry(&models.AuditEntry{
+		Actor:    "ops",
+		Method:   "DELETE",
+		Endpoint: "/api/api-configs/telegram",
+		Before:   []byte(`{"name":"telegram"}`),
+	}))
+
+	count, err := store.GetAuditLogCount("", "", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	entries, err := store.GetAuditLog("", "", 0, 10, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, "/api/api-configs/telegram", entries[0].Endpoint, "newest first")
+	assert.JSONEq(t, `{"name":"telegram"}`, string(entries[0].Before))
+	assert.JSONEq(t, `null`, string(entries[0].After), "a missing state is stored as null")
+	assert.JSONEq(t, `null`, string(entries[1].Before))
+
+	t.Run("filters by actor", func(t *testing.T) {
+		entries, err := store.GetAuditLog("ops", "", 0, 10, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "DELETE", entries[0].Method)
+	})
+
+	t.Run("filters by endpoint prefix", func(t *testing.T) {
+		count, err := store.GetAuditLogCount("", "/api/api-configs", nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		entries, err := store.GetAuditLog("", "/api/crons/", 0, 10, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.JSONEq(t, `{"schedule":"30 * * * *"}`, string(entries[0].After))
+	})
+
+	t.Run("filters by date", func(t *testing.T) {
+		today := time.Now().Truncate(24 * time.Hour)
+		count, err := store.GetAuditLogCount("", "", &today, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		before := yesterday.Truncate(24 * time.Hour)
+		entries, err := store.GetAuditLog("", "", 0, 10, nil, &before)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "/api/crons/collect/schedule", entries[0].Endpoint)
+	})
+
+	t.Run("paginates", func(t *testing.T) {
+		entries, err := store.GetAuditLog("", "", 2, 2, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "/api/crons/collect/schedule", entries[0].Endpoint)
+	})
+}
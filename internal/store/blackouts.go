@@ -0,0 +1,124 @@
+package store
+
+import (
+	"content-maestro/internal/models"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const blackoutColumns = "id, name, kind, starts, ends, weekdays, timezone, enabled, created_at, updated_at"
+
+func scanBlackoutWindow(row rowScanner) (*models.BlackoutWindow, error) {
+	var w models.BlackoutWindow
+	var weekdays string
+	var enabled int
+	if err := row.Scan(&w.ID, &w.Name, &w.Kind, &w.Start, &w.End, &weekdays, &w.Timezone, &enabled,
+		&w.CreatedAt, &w.UpdatedAt); err != nil {
+		return nil, err
+	}
+	w.Weekdays = parseNameList(weekdays)
+	w.Enabled = enabled == 1
+	return &w, nil
+}
+
+func (s *SQLiteStore) GetBlackoutWindows() ([]models.BlackoutWindow, error) {
+	rows, err := s.db.Query("SELECT " + blackoutColumns + " FROM blackout_windows ORDER BY id ASC")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blackout windows: %v", err)
+	}
+	defer rows.Close()
+
+	var windows []models.BlackoutWindow
+	for rows.Next() {
+		window, err := scanBlackoutWindow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan blackout window: %v", err)
+		}
+		windows = append(windows, *window)
+	}
+	return windows, rows.Err()
+}
+
+func (s *SQLiteStore) GetBlackoutWindow(id int64) (*models.BlackoutWindow, error) {
+	query := "SELECT " + blackoutColumns + " FROM blackout_windows WHERE id = ?"
+	window, err := scanBlackoutWindow(s.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blackout window: %v", err)
+	}
+	return window, nil
+}
+
+func (s *SQLiteStore) CreateBlackoutWindow(req *models.BlackoutWindowRequest) (*models.BlackoutWindow, error) {
+	now := time.Now()
+	query := `
+		INSERT INTO blackout_windows (name, kind, starts, ends, weekdays, timezone, enabled, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := s.db.Exec(query, req.Name, req.Kind, req.Start, req.End, strings.Join(req.Weekdays, ","),
+		blackoutTimezone(req), boolToInt(blackoutEnabled(req)), now, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create blackout window: %v", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blackout window id: %v", err)
+	}
+	return s.GetBlackoutWindow(id)
+}
+
+// UpdateBlackoutWindow replaces a blackout window. The fields of a window depend
+// on its kind, so it is always replaced as a whole.
+func (s *SQLiteStore) UpdateBlackoutWindow(id int64, req *models.BlackoutWindowRequest) (*models.BlackoutWindow, error) {
+	query := `
+		UPDATE blackout_windows
+		SET name = ?, kind = ?, starts = ?, ends = ?, weekdays = ?, timezone = ?, enabled = ?, updated_at = ?
+		WHERE id = ?`
+	result, err := s.db.Exec(query, req.Name, req.Kind, req.Start, req.End, strings.Join(req.Weekdays, ","),
+		blackoutTimezone(req), boolToInt(blackoutEnabled(req)), time.Now(), id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update blackout window: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return nil, fmt.Errorf("blackout window %d not found", id)
+	}
+
+	return s.GetBlackoutWindow(id)
+}
+
+func (s *SQLiteStore) DeleteBlackoutWindow(id int64) error {
+	result, err := s.db.Exec("DELETE FROM blackout_windows WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete blackout window: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("blackout window %d not found", id)
+	}
+
+	return nil
+}
+
+func blackoutTimezone(req *models.BlackoutWindowRequest) string {
+	if req.Timezone == "" {
+		return models.DefaultTimezone
+	}
+	return req.Timezone
+}
+
+func blackoutEnabled(req *models.BlackoutWindowRequest) bool {
+	return req.Enabled == nil || *req.Enabled
+}
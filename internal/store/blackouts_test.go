@@ -0,0 +1,55 @@
+package store
+
+import (
+	"content-maestro/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteStore_BlackoutWindows(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	windows, err := store.GetBlackoutWindows()
+	require.NoError(t, err)
+	assert.Empty(t, windows)
+
+	created, err := store.CreateBlackoutWindow(&models.BlackoutWindowRequest{
+		Name: "weekend", Kind: models.BlackoutKindWeekly, Weekdays: []string{"sat", "sun"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sat", "sun"}, created.Weekdays)
+	assert.Equal(t, models.DefaultTimezone, created.Timezone)
+	assert.True(t, created.Enabled)
+	assert.False(t, created.CreatedAt.IsZero())
+
+	disabled := false
+	updated, err := store.UpdateBlackoutWindow(created.ID, &models.BlackoutWindowRequest{
+		Name: "Christmas", Kind: models.BlackoutKindYearly, Start: "12-24", End: "12-26",
+		Timezone: "Europe/Kyiv", Enabled: &disabled,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Christmas", updated.Name)
+	assert.Equal(t, "12-24", updated.Start)
+	assert.Equal(t, "12-26", updated.End)
+	assert.Empty(t, updated.Weekdays)
+	assert.Equal(t, "Europe/Kyiv", updated.Timezone)
+	assert.False(t, updated.Enabled)
+
+	_, err = store.UpdateBlackoutWindow(created.ID+1, &models.BlackoutWindowRequest{Name: "x", Kind: models.BlackoutKindWeekly})
+	assert.ErrorContains(t, err, "not found")
+
+	windows, err = store.GetBlackoutWindows()
+	require.NoError(t, err)
+	require.Len(t, windows, 1)
+	assert.Equal(t, created.ID, windows[0].ID)
+
+	require.NoError(t, store.DeleteBlackoutWindow(created.ID))
+	assert.ErrorContains(t, store.DeleteBlackoutWindow(created.ID), "not found")
+
+	window, err := store.GetBlackoutWindow(created.ID)
+	require.NoError(t, err)
+	assert.Nil(t, window)
+}
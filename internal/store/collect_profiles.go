@@ -0,0 +1,243 @@
+package store
+
+import (
+	"content-maestro/internal/models"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const collectProfileColumns = "name, max_repos, resource, since, spoken_language_code, period, language, prompt, prompt_versions, created_at, updated_at"
+
+func scanCollectProfile(row rowScanner) (*models.CollectProfile, error) {
+	var p models.CollectProfile
+	var prompt, promptVersions string
+	if err := row.Scan(&p.Name, &p.MaxRepos, &p.Resource, &p.Since, &p.SpokenLanguageCode, &p.Period, &p.Language,
+		&prompt, &promptVersions, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if prompt != "" {
+		var override models.UpdatePromptSettingsRequest
+		if err := json.Unmarshal([]byte(prompt), &override); err != nil {
+			// A malformed override must not stop the profile from collecting.
+			fmt.Printf("Failed to decode prompt override of collect profile %s: %v\n", p.Name, err)
+		} else {
+			p.Prompt = &override
+		}
+	}
+	if promptVersions != "" {
+		if err := json.Unmarshal([]byte(promptVersions), &p.PromptVersions); err != nil {
+			fmt.Printf("Failed to decode prompt versions of collect profile %s: %v\n", p.Name, err)
+			p.PromptVersions = nil
+		}
+	}
+	return &p, nil
+}
+
+// encodePromptOverride stores a prompt override as JSON, and an override that
+// sets nothing as no override at all.
+func encodePromptOverride(prompt *models.UpdatePromptSettingsRequest) (string, error) {
+	if prompt == nil || *prompt == (models.UpdatePromptSettingsRequest{}) {
+		return "", nil
+	}
+	encoded, err := json.Marshal(prompt)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode prompt override: %v", err)
+	}
+	return string(encoded), nil
+}
+
+// encodePromptVersions stores the prompt versions assigned to a profile as JSON.
+func encodePromptVersions(versions []models.PromptVersionWeight) (string, error) {
+	if len(versions) == 0 {
+		return "", nil
+	}
+	encoded, err := json.Marshal(versions)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode prompt versions: %v", err)
+	}
+	return string(encoded), nil
+}
+
+func (s *SQLiteStore) GetCollectProfiles() ([]models.CollectProfile, error) {
+	rows, err := s.db.Query("SELECT " + collectProfileColumns + " FROM collect_profiles ORDER BY name ASC")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collect profiles: %v", err)
+	}
+	defer rows.Close()
+
+	var profiles []models.CollectProfile
+	for rows.Next() {
+		profile, err := scanCollectProfile(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan collect profile: %v", err)
+		}
+		profiles = append(profiles, *profile)
+	}
+	return profiles, rows.Err()
+}
+
+func (s *SQLiteStore) GetCollectProfile(name string) (*models.CollectProfile, error) {
+	query := "SELECT " + collectProfileColumns + " FROM collect_profiles WHERE name = ?"
+	profile, err := scanCollectProfile(s.db.QueryRow(query, name))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collect profile: %v", err)
+	}
+	return profile, nil
+}
+
+// CreateCollectProfile adds a profile and the collect cron that runs it, both
+// under the profile's name, or neither.
+func (s *SQLiteStore) CreateCollectProfile(req *models.CreateCollectProfileRequest) (*models.CollectProfile, error) {
+	prompt, err := encodePromptOverride(req.Prompt)
+	if err != nil {
+		return nil, err
+	}
+	promptVersions, err := encodePromptVersions(req.PromptVersions)
+	if err != nil {
+		return nil, err
+	}
+
+	timezone := req.Timezone
+	if timezone == "" {
+		timezone = models.DefaultTimezone
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	_, err = tx.Exec(`
+		INSERT INTO cron_settings (name, schedule, is_active, updated_at, job, timezone, jitter_seconds)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		req.Name, req.Schedule, boolToInt(req.IsActive), now, models.JobCollect, timezone, req.JitterSeconds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create collect profile cron: %v", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO collect_profiles (name, max_repos, resource, since, spoken_language_code, period, language, prompt, prompt_versions, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		req.Name, req.MaxRepos, orDefault(req.Resource, DefaultResource), req.Since, req.SpokenLanguageCode,
+		orDefault(req.Period, DefaultPeriod), orDefault(req.Language, DefaultLanguage), prompt, promptVersions, now, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create collect profile: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit collect profile: %v", err)
+	}
+	return s.GetCollectProfile(req.Name)
+}
+
+// UpdateCollectProfile changes the fields of a profile that are set in req.
+func (s *SQLiteStore) UpdateCollectProfile(name string, req *models.UpdateCollectProfileRequest) (*models.CollectProfile, error) {
+	profile, err := s.GetCollectProfile(name)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return nil, fmt.Errorf("collect profile '%s' not found", name)
+	}
+
+	if req.MaxRepos != nil {
+		profile.MaxRepos = *req.MaxRepos
+	}
+	if req.Resource != nil {
+		profile.Resource = *req.Resource
+	}
+	if req.Since != nil {
+		profile.Since = *req.Since
+	}
+	if req.SpokenLanguageCode != nil {
+		profile.SpokenLanguageCode = *req.SpokenLanguageCode
+	}
+	if req.Period != nil {
+		profile.Period = *req.Period
+	}
+	if req.Language != nil {
+		profile.Language = *req.Language
+	}
+	if req.Prompt != nil {
+		profile.Prompt = req.Prompt
+	}
+	if req.PromptVersions != nil {
+		profile.PromptVersions = *req.PromptVersions
+	}
+
+	prompt, err := encodePromptOverride(profile.Prompt)
+	if err != nil {
+		return nil, err
+	}
+	promptVersions, err := encodePromptVersions(profile.PromptVersions)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE collect_profiles
+		SET max_repos = ?, resource = ?, since = ?, spoken_language_code = ?, period = ?, language = ?, prompt = ?,
+			prompt_versions = ?, updated_at = ?
+		WHERE name = ?`
+	result, err := s.db.Exec(query, profile.MaxRepos, profile.Resource, profile.Since, profile.SpokenLanguageCode,
+		profile.Period, profile.Language, prompt, promptVersions, time.Now(), name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update collect profile: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return nil, fmt.Errorf("collect profile '%s' not found", name)
+	}
+
+	return s.GetCollectProfile(name)
+}
+
+// DeleteCollectProfile removes a profile and its cron. The history of its runs
+// is kept.
+func (s *SQLiteStore) DeleteCollectProfile(name string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("DELETE FROM collect_profiles WHERE name = ?", name)
+	if err != nil {
+		return fmt.Errorf("failed to delete collect profile: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("collect profile '%s' not found", name)
+	}
+
+	if _, err := tx.Exec("DELETE FROM cron_settings WHERE name = ? AND job = ?", name, models.JobCollect); err != nil {
+		return fmt.Errorf("failed to delete collect profile cron: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit collect profile deletion: %v", err)
+	}
+	return nil
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
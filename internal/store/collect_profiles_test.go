@@ -0,0 +1,100 @@
+package store
+
+import (
+	"content-maestro/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteStore_CollectProfiles(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	profiles, err := store.GetCollectProfiles()
+	require.NoError(t, err)
+	assert.Empty(t, profiles)
+
+	model := "gpt-4o"
+	created, err := store.CreateCollectProfile(&models.CreateCollectProfileRequest{
+		Name: "go-weekly", Schedule: "0 9 * * 1", IsActive: true, JitterSeconds: 60,
+		MaxRepos: 3, Since: "weekly", SpokenLanguageCode: "en", Language: "Go",
+		Prompt: &models.UpdatePromptSettingsRequest{Model: &model},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultResource, created.Resource)
+	assert.Equal(t, DefaultPeriod, created.Period)
+	assert.Equal(t, "Go", created.Language)
+	require.NotNil(t, created.Prompt)
+	assert.Equal(t, "gpt-4o", *created.Prompt.Model)
+	assert.Nil(t, created.Prompt.Content)
+	assert.False(t, created.CreatedAt.IsZero())
+
+	// The profile is run by a collect cron of the same name.
+	setting, err := store.GetCronSetting("go-weekly")
+	require.NoError(t, err)
+	require.NotNil(t, setting)
+	assert.Equal(t, models.JobCollect, setting.Job)
+	assert.Equal(t, "0 9 * * 1", setting.Schedule)
+	assert.True(t, setting.IsActive)
+	assert.Equal(t, models.DefaultTimezone, setting.Timezone)
+	assert.Equal(t, 60, setting.JitterSeconds)
+
+	// A name taken by a cron leaves neither row behind.
+	_, err = store.CreateCollectProfile(&models.CreateCollectProfileRequest{
+		Name: "message", Schedule: "0 9 * * *", MaxRepos: 1, Since: "daily", SpokenLanguageCode: "en",
+	})
+	assert.Error(t, err)
+	profile, err := store.GetCollectProfile("message")
+	require.NoError(t, err)
+	assert.Nil(t, profile)
+
+	maxRepos := 10
+	since := "daily"
+	updated, err := store.UpdateCollectProfile("go-weekly", &models.UpdateCollectProfileRequest{MaxRepos: &maxRepos, Since: &since})
+	require.NoError(t, err)
+	assert.Equal(t, 10, updated.MaxRepos)
+	assert.Equal(t, "daily", updated.Since)
+	assert.Equal(t, "Go", updated.Language)
+	require.NotNil(t, updated.Prompt, "an update without prompt keeps the override")
+
+	updated, err = store.UpdateCollectProfile("go-weekly", &models.UpdateCollectProfileRequest{Prompt: &models.UpdatePromptSettingsRequest{}})
+	require.NoError(t, err)
+	assert.Nil(t, updated.Prompt, "an empty prompt removes the override")
+
+	_, err = store.UpdateCollectProfile("rust-daily", &models.UpdateCollectProfileRequest{MaxRepos: &maxRepos})
+	assert.ErrorContains(t, err, "not found")
+
+	profiles, err = store.GetCollectProfiles()
+	require.NoError(t, err)
+	require.Len(t, profiles, 1)
+	assert.Equal(t, "go-weekly", profiles[0].Name)
+
+	require.NoError(t, store.DeleteCollectProfile("go-weekly"))
+	assert.ErrorContains(t, store.DeleteCollectProfile("go-weekly"), "not found")
+
+	setting, err = store.GetCronSetting("go-weekly")
+	require.NoError(t, err)
+	assert.Nil(t, setting)
+
+	setting, err = store.GetCronSetting("message")
+	require.NoError(t, err)
+	assert.NotNil(t, setting, "a failed create must not touch the cron it collided with")
+}
+
+func TestSQLiteStore_CronHistoryProfile(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	require.NoError(t, store.LogCronHistory(&models.CronHistory{Name: "go-weekly", Success: models.CronStatusSuccess, Profile: "go-weekly", PromptVersion: "concise"}))
+	require.NoError(t, store.LogCronHistory(&models.CronHistory{Name: "collect", Success: models.CronStatusSuccess}))
+
+	history, err := store.GetCronHistory("", nil, 0, 10, "asc", nil, nil)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, "go-weekly", history[0].Profile)
+	assert.Equal(t, "concise", history[0].PromptVersion)
+	assert.Empty(t, history[1].Profile)
+	assert.Empty(t, history[1].PromptVersion)
+}
@@ -0,0 +1,196 @@
+package store
+
+import (
+	"content-maestro/internal/models"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// RecordDeliveryAttempt adds one attempt to the ledger entry of a repository and
+// a connector. A delivered entry stays delivered: a later failed re-send does
+// not change the fact that the connector already has the item. A success also
+// takes the entry out of the retry queue; a failure leaves any queued retry in
+// place, so a failed manual re-send does not cancel the automatic one. Manual
+// attempts are also counted on their own, so they can be left out of the retry
+// budget. The post reference is only replaced by a newer non-empty one.
+func (s *SQLiteStore) RecordDeliveryAttempt(attempt *models.DeliveryAttempt) error {
+	if attempt.URL == "" || attempt.APIName == "" {
+		return fmt.Errorf("delivery url and api name cannot be empty")
+	}
+
+	now := time.Now()
+	manualAttempts := 0
+	if attempt.Manual {
+		manualAttempts = 1
+	}
+	status := models.DeliveryStatusFailed
+	var deliveredAt any
+	if attempt.Success {
+		status = models.DeliveryStatusDelivered
+		deliveredAt = now
+	}
+
+	query := `
+		INSERT INTO deliveries (url, api_name, status, attempts, manual_attempts, last_error, first_attempt_at,
+			last_attempt_at, delivered_at, post_id, post_url)
+		VALUES (?, ?, ?, 1, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(url, api_name) DO UPDATE
+		SET attempts = deliveries.attempts + 1,
+			manual_attempts = deliveries.manual_attempts + excluded.manual_attempts,
+			status = CASE WHEN deliveries.status = ? THEN deliveries.status ELSE excluded.status END,
+			last_error = excluded.last_error,
+			last_attempt_at = excluded.last_attempt_at,
+			delivered_at = COALESCE(deliveries.delivered_at, excluded.delivered_at),
+			next_attempt_at = CASE WHEN excluded.status = ? THEN NULL ELSE deliveries.next_attempt_at END,
+			post_id = CASE WHEN excluded.post_id != '' THEN excluded.post_id ELSE deliveries.post_id END,
+			post_url = CASE WHEN excluded.post_url != '' THEN excluded.post_url ELSE deliveries.post_url END`
+	_, err := s.db.Exec(query, attempt.URL, attempt.APIName, status, manualAttempts, attempt.Error, now, now, deliveredAt,
+		attempt.PostID, attempt.PostURL, models.DeliveryStatusDelivered, models.DeliveryStatusDelivered)
+	if err != nil {
+		return fmt.Errorf("failed to record delivery attempt: %v", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) GetDelivery(url, apiName string) (*models.Delivery, error) {
+	query := "SELECT " + deliveryColumns + " FROM deliveries WHERE url = ? AND api_name = ?"
+	delivery, err := scanDelivery(s.db.QueryRow(query, url, apiName))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get delivery: %v", err)
+	}
+	return delivery, nil
+}
+
+func (s *SQLiteStore) GetDeliveryCount(url, apiName, status string) (int, error) {
+	where, args := deliveryFilter(url, apiName, status)
+
+	var count int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM deliveries"+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count deliveries: %v", err)
+	}
+	return count, nil
+}
+
+func (s *SQLiteStore) GetDeliveries(url, apiName, status string, offset, limit int) ([]models.Delivery, error) {
+	where, args := deliveryFilter(url, apiName, status)
+	query := "SELECT " + deliveryColumns + " FROM deliveries" + where + " ORDER BY last_attempt_at DESC LIMIT ? OFFSET ?"
+	args = append(args, limit, offset)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deliveries: %v", err)
+	}
+	defer rows.Close()
+
+	var deliveries []models.Delivery
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan delivery: %v", err)
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+// ScheduleDeliveryRetry queues a failed delivery for another attempt at the
+// given time. Delivered entries are left alone: there is nothing to retry.
+func (s *SQLiteStore) ScheduleDeliveryRetry(url, apiName string, at time.Time) error {
+	result, err := s.db.Exec(`
+		UPDATE deliveries SET status = ?, next_attempt_at = ?
+		WHERE url = ? AND api_name = ? AND status != ?`,
+		models.DeliveryStatusFailed, at.UTC(), url, apiName, models.DeliveryStatusDelivered)
+	if err != nil {
+		return fmt.Errorf("failed to schedule delivery retry: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("no failed delivery of %s to %s", url, apiName)
+	}
+	return nil
+}
+
+// AbandonDelivery takes a failed delivery out of the retry queue for good.
+func (s *SQLiteStore) AbandonDelivery(url, apiName, reason string) error {
+	_, err := s.db.Exec(`
+		UPDATE deliveries SET status = ?, next_attempt_at = NULL, last_error = ?
+		WHERE url = ? AND api_name = ? AND status != ?`,
+		models.DeliveryStatusAbandoned, reason, url, apiName, models.DeliveryStatusDelivered)
+	if err != nil {
+		return fmt.Errorf("failed to abandon delivery: %v", err)
+	}
+	return nil
+}
+
+// GetDueDeliveryRetries returns the queued deliveries whose next attempt is due,
+// oldest first.
+func (s *SQLiteStore) GetDueDeliveryRetries(now time.Time, limit int) ([]models.Delivery, error) {
+	rows, err := s.db.Query(`
+		SELECT `+deliveryColumns+`
+		FROM deliveries
+		WHERE status = ? AND next_attempt_at IS NOT NULL AND next_attempt_at <= ?
+		ORDER BY next_attempt_at ASC LIMIT ?`,
+		models.DeliveryStatusFailed, now.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due delivery retries: %v", err)
+	}
+	defer rows.Close()
+
+	var deliveries []models.Delivery
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan delivery: %v", err)
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+func deliveryFilter(url, apiName, status string) (string, []any) {
+	where := " WHERE 1=1"
+	args := []any{}
+
+	if url != "" {
+		where += " AND url = ?"
+		args = append(args, url)
+	}
+	if apiName != "" {
+		where += " AND api_name = ?"
+		args = append(args, apiName)
+	}
+	if status != "" {
+		where += " AND status = ?"
+		args = append(args, status)
+	}
+	return where, args
+}
+
+const deliveryColumns = `url, api_name, status, attempts, manual_attempts, last_error, first_attempt_at,
+	last_attempt_at, delivered_at, next_attempt_at, post_id, post_url`
+
+func scanDelivery(row rowScanner) (*models.Delivery, error) {
+	var d models.Delivery
+	var lastError sql.NullString
+	var deliveredAt, nextAttemptAt sql.NullTime
+	if err := row.Scan(&d.URL, &d.APIName, &d.Status, &d.Attempts, &d.ManualAttempts, &lastError,
+		&d.FirstAttemptAt, &d.LastAttemptAt, &deliveredAt, &nextAttemptAt, &d.PostID, &d.PostURL); err != nil {
+		return nil, err
+	}
+	d.LastError = lastError.String
+	if deliveredAt.Valid {
+		d.DeliveredAt = &deliveredAt.Time
+	}
+	if nextAttemptAt.Valid {
+		d.NextAttemptAt = &nextAttemptAt.Time
+	}
+	return &d, nil
+}
@@ -0,0 +1,207 @@
+package store
+
+import (
+	"content-maestro/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDeliveryURL = "https://github.com/resemble-ai/chatterbox"
+
+func TestSQLiteStore_RecordDeliveryAttempt(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	require.NoError(t, store.RecordDeliveryAttempt(&models.DeliveryAttempt{
+		URL: testDeliveryURL, APIName: "threads", Error: "API request failed with status 502",
+	}))
+
+	delivery, err := store.GetDelivery(testDeliveryURL, "threads")
+	require.NoError(t, err)
+	require.NotNil(t, delivery)
+	assert.Equal(t, models.DeliveryStatusFailed, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, "API request failed with status 502", delivery.LastError)
+	assert.Nil(t, delivery.DeliveredAt)
+
+	require.NoError(t, store.RecordDeliveryAttempt(&models.DeliveryAttempt{
+		URL: testDeliveryURL, APIName: "threads", Success: true,
+	}))
+
+	delivery, err = store.GetDelivery(testDeliveryURL, "threads")
+	require.NoError(t, err)
+	assert.Equal(t, models.DeliveryStatusDelivered, delivery.Status)
+	assert.Equal(t, 2, delivery.Attempts)
+	assert.Empty(t, delivery.LastError)
+	require.NotNil(t, delivery.DeliveredAt)
+}
+
+// A connector that once received an item keeps it, whatever a later re-send
+// reports.
+func TestSQLiteStore_RecordDeliveryAttempt_DeliveredIsSticky(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	require.NoError(t, store.RecordDeliveryAttempt(&models.DeliveryAttempt{
+		URL: testDeliveryURL, APIName: "bluesky", Success: true,
+	}))
+	first, err := store.GetDelivery(testDeliveryURL, "bluesky")
+	require.NoError(t, err)
+
+	require.NoError(t, store.RecordDeliveryAttempt(&models.DeliveryAttempt{
+		URL: testDeliveryURL, APIName: "bluesky", Error: "timeout",
+	}))
+
+	delivery, err := store.GetDelivery(testDeliveryURL, "bluesky")
+	require.NoError(t, err)
+	assert.Equal(t, models.DeliveryStatusDelivered, delivery.Status)
+	assert.Equal(t, 2, delivery.Attempts)
+	require.NotNil(t, delivery.DeliveredAt)
+	assert.True(t, first.DeliveredAt.Equal(*delivery.DeliveredAt))
+}
+
+func TestSQLiteStore_RecordDeliveryAttempt_CountsManualAttempts(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	require.NoError(t, store.RecordDeliveryAttempt(&models.DeliveryAttempt{
+		URL: testDeliveryURL, APIName: "threads", Error: "timeout",
+	}))
+	require.NoError(t, store.RecordDeliveryAttempt(&models.DeliveryAttempt{
+		URL: testDeliveryURL, APIName: "threads", Error: "timeout", Manual: true,
+	}))
+
+	delivery, err := store.GetDelivery(testDeliveryURL, "threads")
+	require.NoError(t, err)
+	assert.Equal(t, 2, delivery.Attempts)
+	assert.Equal(t, 1, delivery.ManualAttempts)
+}
+
+func TestSQLiteStore_RecordDeliveryAttempt_RequiresKey(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	assert.Error(t, store.RecordDeliveryAttempt(&models.DeliveryAttempt{APIName: "threads"}))
+	assert.Error(t, store.RecordDeliveryAttempt(&models.DeliveryAttempt{URL: testDeliveryURL}))
+}
+
+func TestSQLiteStore_GetDelivery_NotFound(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	delivery, err := store.GetDelivery(testDeliveryURL, "threads")
+	assert.NoError(t, err)
+	assert.Nil(t, delivery)
+}
+
+func TestSQLiteStore_GetDeliveries(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	require.NoError(t, store.RecordDeliveryAttempt(&models.DeliveryAttempt{URL: testDeliveryURL, APIName: "threads", Success: true}))
+	require.NoError(t, store.RecordDeliveryAttempt(&models.DeliveryAttempt{URL: testDeliveryURL, APIName: "bluesky", Error: "boom"}))
+	require.NoError(t, store.RecordDeliveryAttempt(&models.DeliveryAttempt{URL: "https://github.com/other/repo", APIName: "threads", Success: true}))
+
+	count, err := store.GetDeliveryCount(testDeliveryURL, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	count, err = store.GetDeliveryCount("", "threads", models.DeliveryStatusDelivered)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	deliveries, err := store.GetDeliveries("", "", models.DeliveryStatusFailed, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, "bluesky", deliveries[0].APIName)
+
+	deliveries, err = store.GetDeliveries("", "", "", 0, 2)
+	require.NoError(t, err)
+	assert.Len(t, deliveries, 2)
+}
+
+func TestSQLiteStore_DeliveryRetryQueue(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	for _, apiName := range []string{"threads", "bluesky"} {
+		require.NoError(t, store.RecordDeliveryAttempt(&models.DeliveryAttempt{
+			URL: testDeliveryURL, APIName: apiName, Error: "timeout",
+		}))
+	}
+
+	now := time.Now()
+	require.NoError(t, store.ScheduleDeliveryRetry(testDeliveryURL, "threads", now.Add(-time.Minute)))
+	require.NoError(t, store.ScheduleDeliveryRetry(testDeliveryURL, "bluesky", now.Add(time.Hour)))
+
+	due, err := store.GetDueDeliveryRetries(now, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, "threads", due[0].APIName)
+	require.NotNil(t, due[0].NextAttemptAt)
+
+	// A failed attempt keeps the entry queued; a successful one takes it out.
+	require.NoError(t, store.RecordDeliveryAttempt(&models.DeliveryAttempt{
+		URL: testDeliveryURL, APIName: "threads", Error: "timeout",
+	}))
+	delivery, err := store.GetDelivery(testDeliveryURL, "threads")
+	require.NoError(t, err)
+	assert.NotNil(t, delivery.NextAttemptAt)
+
+	require.NoError(t, store.RecordDeliveryAttempt(&models.DeliveryAttempt{
+		URL: testDeliveryURL, APIName: "threads", Success: true,
+	}))
+	delivery, err = store.GetDelivery(testDeliveryURL, "threads")
+	require.NoError(t, err)
+	assert.Nil(t, delivery.NextAttemptAt)
+
+	due, err = store.GetDueDeliveryRetries(now, 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+
+	assert.Error(t, store.ScheduleDeliveryRetry(testDeliveryURL, "threads", now),
+		"a delivered entry has nothing to retry")
+}
+
+func TestSQLiteStore_AbandonDelivery(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	require.NoError(t, store.RecordDeliveryAttempt(&models.DeliveryAttempt{
+		URL: testDeliveryURL, APIName: "threads", Error: "timeout",
+	}))
+	require.NoError(t, store.ScheduleDeliveryRetry(testDeliveryURL, "threads", time.Now().Add(-time.Minute)))
+	require.NoError(t, store.AbandonDelivery(testDeliveryURL, "threads", "retry budget spent"))
+
+	delivery, err := store.GetDelivery(testDeliveryURL, "threads")
+	require.NoError(t, err)
+	assert.Equal(t, models.DeliveryStatusAbandoned, delivery.Status)
+	assert.Equal(t, "retry budget spent", delivery.LastError)
+	assert.Nil(t, delivery.NextAttemptAt)
+
+	due, err := store.GetDueDeliveryRetries(time.Now(), 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+}
+
+func TestSQLiteStore_RecordDeliveryAttempt_KeepsPostReference(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	require.NoError(t, store.RecordDeliveryAttempt(&models.DeliveryAttempt{
+		URL: testDeliveryURL, APIName: "telegram", Success: true,
+		PostID: "1327", PostURL: "https://t.me/think_root/1327",
+	}))
+	// A later re-send that reports no post must not erase the link.
+	require.NoError(t, store.RecordDeliveryAttempt(&models.DeliveryAttempt{
+		URL: testDeliveryURL, APIName: "telegram", Success: true,
+	}))
+
+	delivery, err := store.GetDelivery(testDeliveryURL, "telegram")
+	require.NoError(t, err)
+	assert.Equal(t, "1327", delivery.PostID)
+	assert.Equal(t, "https://t.me/think_root/1327", delivery.PostURL)
+}
@@ -0,0 +1,87 @@
+package store
+
+import (
+	"content-maestro/internal/models"
+	"fmt"
+	"strings"
+	"time"
+)
+
+func (s *SQLiteStore) GetImageSettings() (*models.ImageSettings, error) {
+	var settings models.ImageSettings
+	var patterns, fields string
+	err := s.db.QueryRow(`
+		SELECT provider, socialify_url, static_file, theme, font, patterns, logo, fields, format, width, updated_at
+		FROM image_settings
+		WHERE id = 1
+	`).Scan(&settings.Provider, &settings.SocialifyURL, &settings.StaticFile, &settings.Theme, &settings.Font,
+		&patterns, &settings.Logo, &fields, &settings.Format, &settings.Width, &settings.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get image settings: %v", err)
+	}
+	settings.Patterns = parseNameList(patterns)
+	settings.Fields = parseNameList(fields)
+	return &settings, nil
+}
+
+func (s *SQLiteStore) UpdateImageSettings(settings *models.ImageOverrides) (*models.ImageSettings, error) {
+	query := `UPDATE image_settings SET updated_at = ?`
+	args := []interface{}{time.Now()}
+
+	if settings.Provider != nil {
+		query += ", provider = ?"
+		args = append(args, *settings.Provider)
+	}
+
+	if settings.SocialifyURL != nil {
+		query += ", socialify_url = ?"
+		args = append(args, *settings.SocialifyURL)
+	}
+
+	if settings.StaticFile != nil {
+		query += ", static_file = ?"
+		args = append(args, *settings.StaticFile)
+	}
+
+	if settings.Theme != nil {
+		query += ", theme = ?"
+		args = append(args, *settings.Theme)
+	}
+
+	if settings.Font != nil {
+		query += ", font = ?"
+		args = append(args, *settings.Font)
+	}
+
+	if settings.Patterns != nil {
+		query += ", patterns = ?"
+		args = append(args, strings.Join(*settings.Patterns, ","))
+	}
+
+	if settings.Logo != nil {
+		query += ", logo = ?"
+		args = append(args, *settings.Logo)
+	}
+
+	if settings.Fields != nil {
+		query += ", fields = ?"
+		args = append(args, strings.Join(*settings.Fields, ","))
+	}
+
+	if settings.Format != nil {
+		query += ", format = ?"
+		args = append(args, *settings.Format)
+	}
+
+	if settings.Width != nil {
+		query += ", width = ?"
+		args = append(args, *settings.Width)
+	}
+
+	query += " WHERE id = 1"
+
+	if _, err := s.db.Exec(query, args...); err != nil {
+		return nil, fmt.Errorf("failed to update image settings: %v", err)
+	}
+	return s.GetImageSettings()
+}
@@ -0,0 +1,63 @@
+package store
+
+import (
+	"content-maestro/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteStore_ImageSettings(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	settings, err := store.GetImageSettings()
+	require.NoError(t, err)
+	defaults := models.DefaultImageSettings()
+	assert.Equal(t, defaults.Theme, settings.Theme)
+	assert.Equal(t, defaults.Patterns, settings.Patterns)
+	assert.Equal(t, defaults.Fields, settings.Fields)
+	assert.Equal(t, defaults.Width, settings.Width)
+
+	theme := "Dark"
+	patterns := []string{"Plus"}
+	updated, err := store.UpdateImageSettings(&models.ImageOverrides{Theme: &theme, Patterns: &patterns})
+	require.NoError(t, err)
+	assert.Equal(t, "Dark", updated.Theme)
+	assert.Equal(t, []string{"Plus"}, updated.Patterns)
+	assert.Equal(t, defaults.Font, updated.Font, "settings left out keep their value")
+	assert.Equal(t, models.ImageProviderSocialify, updated.Provider)
+
+	provider := models.ImageProviderSocialify
+	socialifyURL := "https://socialify.example.com"
+	updated, err = store.UpdateImageSettings(&models.ImageOverrides{Provider: &provider, SocialifyURL: &socialifyURL})
+	require.NoError(t, err)
+	assert.Equal(t, socialifyURL, updated.SocialifyURL)
+	assert.Empty(t, updated.StaticFile)
+}
+
+func TestSQLiteStore_APIConfigImageSettings(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	format := "jpeg"
+	created, err := store.CreateAPIConfig(&models.CreateAPIConfigRequest{
+		Name: "x", URL: "https://x.example.com", Method: "POST",
+		ContentType: "multipart", Timeout: 30, SuccessCode: 200, Enabled: true,
+		ImageSettings: &models.ImageOverrides{Format: &format},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, created.ImageSettings)
+	assert.Equal(t, "jpeg", *created.ImageSettings.Format)
+	assert.Nil(t, created.ImageSettings.Theme)
+
+	timeout := 60
+	updated, err := store.UpdateAPIConfig("x", &models.UpdateAPIConfigRequest{Timeout: &timeout})
+	require.NoError(t, err)
+	require.NotNil(t, updated.ImageSettings, "an update without image settings keeps them")
+
+	updated, err = store.UpdateAPIConfig("x", &models.UpdateAPIConfigRequest{ImageSettings: &models.ImageOverrides{}})
+	require.NoError(t, err)
+	assert.Nil(t, updated.ImageSettings, "an empty override removes it")
+}
@@ -0,0 +1,174 @@
+package store
+
+import (
+	"content-maestro/internal/models"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const promptRevisionColumns = "rev, actor, created_at, settings, changes, rolled_back_from"
+
+func scanPromptRevision(row rowScanner) (*models.PromptRevision, error) {
+	var r models.PromptRevision
+	var settings, changes string
+	var rolledBackFrom sql.NullInt64
+	if err := row.Scan(&r.Rev, &r.Actor, &r.CreatedAt, &settings, &changes, &rolledBackFrom); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(settings), &r.Settings); err != nil {
+		return nil, fmt.Errorf("failed to decode settings of prompt revision %d: %v", r.Rev, err)
+	}
+	if err := json.Unmarshal([]byte(changes), &r.Changes); err != nil {
+		return nil, fmt.Errorf("failed to decode changes of prompt revision %d: %v", r.Rev, err)
+	}
+	if r.Changes == nil {
+		r.Changes = []models.PromptChange{}
+	}
+	if rolledBackFrom.Valid {
+		r.RolledBackFrom = &rolledBackFrom.Int64
+	}
+	return &r, nil
+}
+
+// promptChanges lists the fields that differ between two prompt settings, by
+// their JSON names.
+func promptChanges(before, after *models.PromptSettings) []models.PromptChange {
+	var changes []models.PromptChange
+	add := func(field string, from, to any) {
+		if from != to {
+			changes = append(changes, models.PromptChange{Field: field, From: from, To: to})
+		}
+	}
+	add("use_direct_url", before.UseDirectURL, after.UseDirectURL)
+	add("llm_provider", before.LlmProvider, after.LlmProvider)
+	add("temperature", before.Temperature, after.Temperature)
+	add("content", before.Content, after.Content)
+	add("model", before.Model, after.Model)
+	add("llm_output_language", before.LlmOutputLanguage, after.LlmOutputLanguage)
+	return changes
+}
+
+func insertPromptRevision(tx *sql.Tx, settings *models.PromptSettings, changes []models.PromptChange, actor string, rolledBackFrom *int64) (int64, error) {
+	encodedSettings, err := json.Marshal(settings)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode prompt revision: %v", err)
+	}
+	if changes == nil {
+		changes = []models.PromptChange{}
+	}
+	encodedChanges, err := json.Marshal(changes)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode prompt revision: %v", err)
+	}
+
+	var rolledBack any
+	if rolledBackFrom != nil {
+		rolledBack = *rolledBackFrom
+	}
+
+	query := `
+		INSERT INTO prompt_revisions (actor, created_at, settings, changes, rolled_back_from)
+		VALUES (?, ?, ?, ?, ?)`
+	result, err := tx.Exec(query, actor, time.Now(), string(encodedSettings), string(encodedChanges), rolledBack)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record prompt revision: %v", err)
+	}
+	return result.LastInsertId()
+}
+
+// recordPromptRevision appends the revision from before to after, if anything
+// changed. The first tracked change also records the settings it started from,
+// so even that change can be rolled back.
+func recordPromptRevision(tx *sql.Tx, before, after *models.PromptSettings, actor string, rolledBackFrom *int64) (*models.PromptRevision, error) {
+	changes := promptChanges(before, after)
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	var count int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM prompt_revisions").Scan(&count); err != nil {
+		return nil, fmt.Errorf("failed to count prompt revisions: %v", err)
+	}
+	if count == 0 {
+		if _, err := insertPromptRevision(tx, before, nil, "", nil); err != nil {
+			return nil, err
+		}
+	}
+
+	rev, err := insertPromptRevision(tx, after, changes, actor, rolledBackFrom)
+	if err != nil {
+		return nil, err
+	}
+
+	query := "SELECT " + promptRevisionColumns + " FROM prompt_revisions WHERE rev = ?"
+	revision, err := scanPromptRevision(tx.QueryRow(query, rev))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt revision: %v", err)
+	}
+	return revision, nil
+}
+
+func (s *SQLiteStore) GetPromptRevisionCount() (int, error) {
+	var count int
+	if err := s.db.QueryRow("SELECT COUNT(*) FROM prompt_revisions").Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count prompt revisions: %v", err)
+	}
+	return count, nil
+}
+
+// GetPromptRevisions lists the prompt revisions, newest first.
+func (s *SQLiteStore) GetPromptRevisions(offset, limit int) ([]models.PromptRevision, error) {
+	query := "SELECT " + promptRevisionColumns + " FROM prompt_revisions ORDER BY rev DESC LIMIT ? OFFSET ?"
+	rows, err := s.db.Query(query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt revisions: %v", err)
+	}
+	defer rows.Close()
+
+	var revisions []models.PromptRevision
+	for rows.Next() {
+		revision, err := scanPromptRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan prompt revision: %v", err)
+		}
+		revisions = append(revisions, *revision)
+	}
+	return revisions, rows.Err()
+}
+
+func (s *SQLiteStore) GetPromptRevision(rev int64) (*models.PromptRevision, error) {
+	query := "SELECT " + promptRevisionColumns + " FROM prompt_revisions WHERE rev = ?"
+	revision, err := scanPromptRevision(s.db.QueryRow(query, rev))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt revision: %v", err)
+	}
+	return revision, nil
+}
+
+// RollbackPromptSettings restores the prompt settings of a revision, recording
+// the restore as a new revision by actor. It returns nil when the settings
+// already match the revision.
+func (s *SQLiteStore) RollbackPromptSettings(rev int64, actor string) (*models.PromptRevision, error) {
+	target, err := s.GetPromptRevision(rev)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, fmt.Errorf("prompt revision %d not found", rev)
+	}
+
+	settings := target.Settings
+	return s.updatePromptSettings(&models.UpdatePromptSettingsRequest{
+		UseDirectURL:      &settings.UseDirectURL,
+		LlmProvider:       &settings.LlmProvider,
+		Temperature:       &settings.Temperature,
+		Content:           &settings.Content,
+		Model:             &settings.Model,
+		LlmOutputLanguage: &settings.LlmOutputLanguage,
+	}, actor, &rev)
+}
@@ -0,0 +1,73 @@
+package store
+
+import (
+	"content-maestro/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteStore_PromptRevisions(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	count, err := store.GetPromptRevisionCount()
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	original, err := store.GetPromptSettings()
+	require.NoError(t, err)
+
+	content := "A bad edit."
+	temperature := 1.5
+	require.NoError(t, store.UpdatePromptSettings(&models.UpdatePromptSettingsRequest{Content: &content, Temperature: &temperature}, "api_token"))
+
+	// An update that changes nothing records nothing.
+	require.NoError(t, store.UpdatePromptSettings(&models.UpdatePromptSettingsRequest{Content: &content}, "api_token"))
+
+	revisions, err := store.GetPromptRevisions(0, 10)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+
+	edit, baseline := revisions[0], revisions[1]
+	assert.Equal(t, "api_token", edit.Actor)
+	assert.Equal(t, "A bad edit.", edit.Settings.Content)
+	assert.ElementsMatch(t, []models.PromptChange{
+		{Field: "temperature", From: original.Temperature, To: 1.5},
+		{Field: "content", From: original.Content, To: "A bad edit."},
+	}, edit.Changes)
+	assert.Nil(t, edit.RolledBackFrom)
+
+	// The first change also records where it started from.
+	assert.Empty(t, baseline.Actor)
+	assert.Empty(t, baseline.Changes)
+	assert.Equal(t, original.Content, baseline.Settings.Content)
+
+	restored, err := store.RollbackPromptSettings(baseline.Rev, "api_token")
+	require.NoError(t, err)
+	require.NotNil(t, restored)
+	require.NotNil(t, restored.RolledBackFrom)
+	assert.Equal(t, baseline.Rev, *restored.RolledBackFrom)
+
+	settings, err := store.GetPromptSettings()
+	require.NoError(t, err)
+	assert.Equal(t, original.Content, settings.Content)
+	assert.Equal(t, original.Temperature, settings.Temperature)
+
+	again, err := store.RollbackPromptSettings(baseline.Rev, "api_token")
+	require.NoError(t, err)
+	assert.Nil(t, again, "rolling back to the current settings records nothing")
+
+	_, err = store.RollbackPromptSettings(99, "api_token")
+	assert.ErrorContains(t, err, "not found")
+
+	count, err = store.GetPromptRevisionCount()
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	page, err := store.GetPromptRevisions(1, 1)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, edit.Rev, page[0].Rev)
+}
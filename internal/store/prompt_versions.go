@@ -0,0 +1,96 @@
+package store
+
+import (
+	"content-maestro/internal/models"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+const promptVersionColumns = "name, content, model, temperature, created_at"
+
+func scanPromptVersion(row rowScanner) (*models.PromptVersion, error) {
+	var v models.PromptVersion
+	var temperature sql.NullFloat64
+	if err := row.Scan(&v.Name, &v.Content, &v.Model, &temperature, &v.CreatedAt); err != nil {
+		return nil, err
+	}
+	if temperature.Valid {
+		v.Temperature = &temperature.Float64
+	}
+	return &v, nil
+}
+
+func (s *SQLiteStore) GetPromptVersions() ([]models.PromptVersion, error) {
+	rows, err := s.db.Query("SELECT " + promptVersionColumns + " FROM prompt_versions ORDER BY created_at ASC, name ASC")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt versions: %v", err)
+	}
+	defer rows.Close()
+
+	var versions []models.PromptVersion
+	for rows.Next() {
+		version, err := scanPromptVersion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan prompt version: %v", err)
+		}
+		versions = append(versions, *version)
+	}
+	return versions, rows.Err()
+}
+
+func (s *SQLiteStore) GetPromptVersion(name string) (*models.PromptVersion, error) {
+	query := "SELECT " + promptVersionColumns + " FROM prompt_versions WHERE name = ?"
+	version, err := scanPromptVersion(s.db.QueryRow(query, name))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt version: %v", err)
+	}
+	return version, nil
+}
+
+func (s *SQLiteStore) CreatePromptVersion(req *models.CreatePromptVersionRequest) (*models.PromptVersion, error) {
+	var temperature any
+	if req.Temperature != nil {
+		temperature = *req.Temperature
+	}
+
+	query := "INSERT INTO prompt_versions (name, content, model, temperature, created_at) VALUES (?, ?, ?, ?, ?)"
+	if _, err := s.db.Exec(query, req.Name, req.Content, req.Model, temperature, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to create prompt version: %v", err)
+	}
+	return s.GetPromptVersion(req.Name)
+}
+
+// DeletePromptVersion removes a prompt version. A version still assigned to a
+// collect profile is kept, so no profile is left pointing at nothing.
+func (s *SQLiteStore) DeletePromptVersion(name string) error {
+	profiles, err := s.GetCollectProfiles()
+	if err != nil {
+		return err
+	}
+	for _, profile := range profiles {
+		for _, assigned := range profile.PromptVersions {
+			if assigned.Name == name {
+				return fmt.Errorf("prompt version '%s' is in use by collect profile '%s'", name, profile.Name)
+			}
+		}
+	}
+
+	result, err := s.db.Exec("DELETE FROM prompt_versions WHERE name = ?", name)
+	if err != nil {
+		return fmt.Errorf("failed to delete prompt version: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("prompt version '%s' not found", name)
+	}
+
+	return nil
+}
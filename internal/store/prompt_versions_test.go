@@ -0,0 +1,70 @@
+package store
+
+import (
+	"content-maestro/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteStore_PromptVersions(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	versions, err := store.GetPromptVersions()
+	require.NoError(t, err)
+	assert.Empty(t, versions)
+
+	concise, err := store.CreatePromptVersion(&models.CreatePromptVersionRequest{Name: "concise", Content: "Be brief."})
+	require.NoError(t, err)
+	assert.Equal(t, "Be brief.", concise.Content)
+	assert.Empty(t, concise.Model)
+	assert.Nil(t, concise.Temperature)
+	assert.False(t, concise.CreatedAt.IsZero())
+
+	temperature := 0.9
+	detailed, err := store.CreatePromptVersion(&models.CreatePromptVersionRequest{
+		Name: "detailed", Content: "Explain in depth.", Model: "gpt-4o", Temperature: &temperature,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "gpt-4o", detailed.Model)
+	require.NotNil(t, detailed.Temperature)
+	assert.Equal(t, 0.9, *detailed.Temperature)
+
+	_, err = store.CreatePromptVersion(&models.CreatePromptVersionRequest{Name: "concise", Content: "Again."})
+	assert.Error(t, err)
+
+	versions, err = store.GetPromptVersions()
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+
+	// A version assigned to a profile stays until the profile lets go of it.
+	_, err = store.CreateCollectProfile(&models.CreateCollectProfileRequest{
+		Name: "go-weekly", Schedule: "0 9 * * 1", MaxRepos: 3, Since: "weekly", SpokenLanguageCode: "en",
+		PromptVersions: []models.PromptVersionWeight{{Name: "concise", Weight: 70}, {Name: "detailed", Weight: 30}},
+	})
+	require.NoError(t, err)
+	profile, err := store.GetCollectProfile("go-weekly")
+	require.NoError(t, err)
+	assert.Equal(t, []models.PromptVersionWeight{{Name: "concise", Weight: 70}, {Name: "detailed", Weight: 30}}, profile.PromptVersions)
+
+	assert.ErrorContains(t, store.DeletePromptVersion("concise"), "in use")
+
+	assigned := []models.PromptVersionWeight{{Name: "detailed"}}
+	profile, err = store.UpdateCollectProfile("go-weekly", &models.UpdateCollectProfileRequest{PromptVersions: &assigned})
+	require.NoError(t, err)
+	assert.Equal(t, assigned, profile.PromptVersions)
+
+	require.NoError(t, store.DeletePromptVersion("concise"))
+	assert.ErrorContains(t, store.DeletePromptVersion("concise"), "not found")
+
+	version, err := store.GetPromptVersion("concise")
+	require.NoError(t, err)
+	assert.Nil(t, version)
+
+	none := []models.PromptVersionWeight{}
+	profile, err = store.UpdateCollectProfile("go-weekly", &models.UpdateCollectProfileRequest{PromptVersions: &none})
+	require.NoError(t, err)
+	assert.Empty(t, profile.PromptVersions)
+}
@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -28,6 +30,10 @@ func boolToInt(b bool) int {
 	return 0
 }
 
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
 type SQLiteStore struct {
 	db *sql.DB
 }
@@ -63,12 +69,22 @@ func createTablesIfNotExist(db *sql.DB) error {
 			name TEXT NOT NULL UNIQUE,
 			schedule TEXT NOT NULL,
 			is_active INTEGER NOT NULL DEFAULT 1,
-			updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+			updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			job TEXT NOT NULL DEFAULT '',
+			apis TEXT NOT NULL DEFAULT '',
+			timezone TEXT NOT NULL DEFAULT 'UTC',
+			paused_until DATETIME,
+			pause_reason TEXT NOT NULL DEFAULT '',
+			jitter_seconds INTEGER NOT NULL DEFAULT 0
 		)`)
 	if err != nil {
 		return fmt.Errorf("failed to create cron_settings table: %v", err)
 	}
 
+	if err := migrateCronSettingsSchema(db); err != nil {
+		return fmt.Errorf("failed to migrate cron_settings schema: %v", err)
+	}
+
 	_, err = db.Exec(`
 		CREATE TABLE IF NOT EXISTS cron_history (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -76,7 +92,13 @@ func createTablesIfNotExist(db *sql.DB) error {
 			timestamp DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
 			status INTEGER NOT NULL,
 			output TEXT,
-			details TEXT
+			details TEXT,
+			run_id TEXT NOT NULL DEFAULT '',
+			manual INTEGER NOT NULL DEFAULT 0,
+			scheduled_at DATETIME,
+			fired_at DATETIME,
+			profile TEXT NOT NULL DEFAULT '',
+			prompt_version TEXT NOT NULL DEFAULT ''
 		)`)
 	if err != nil {
 		return fmt.Errorf("failed to create cron_history table: %v", err)
@@ -108,16 +130,20 @@ func createTablesIfNotExist(db *sql.DB) error {
 		return fmt.Errorf("failed to migrate cron_history details: %v", err)
 	}
 
+	if err := migrateCronHistoryRuns(db); err != nil {
+		return fmt.Errorf("failed to migrate cron_history runs: %v", err)
+	}
+
 	_, err = db.Exec(`
-		INSERT OR IGNORE INTO cron_settings (name, schedule, is_active, updated_at)
-		VALUES ('collect', '13 13 * * 6', 0, CURRENT_TIMESTAMP)`)
+		INSERT OR IGNORE INTO cron_settings (name, schedule, is_active, updated_at, job)
+		VALUES ('collect', '13 13 * * 6', 0, CURRENT_TIMESTAMP, 'collect')`)
 	if err != nil {
 		return fmt.Errorf("failed to insert default collect setting: %v", err)
 	}
 
 	_, err = db.Exec(`
-		INSERT OR IGNORE INTO cron_settings (name, schedule, is_active, updated_at)
-		VALUES ('message', '12 12 * * *', 0, CURRENT_TIMESTAMP)`)
+		INSERT OR IGNORE INTO cron_settings (name, schedule, is_active, updated_at, job)
+		VALUES ('message', '12 12 * * *', 0, CURRENT_TIMESTAMP, 'message')`)
 	if err != nil {
 		return fmt.Errorf("failed to insert default message setting: %v", err)
 	}
@@ -145,6 +171,44 @@ func createTablesIfNotExist(db *sql.DB) error {
 		return fmt.Errorf("failed to create prompt table: %v", err)
 	}
 
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS prompt_revisions (
+			rev INTEGER PRIMARY KEY AUTOINCREMENT,
+			actor TEXT NOT NULL DEFAULT '',
+			created_at DATETIME NOT NULL,
+			settings TEXT NOT NULL,
+			changes TEXT NOT NULL,
+			rolled_back_from INTEGER
+		)`)
+	if err != nil {
+		return fmt.Errorf("failed to create prompt_revisions table: %v", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS audit_log (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			actor TEXT NOT NULL DEFAULT '',
+			method TEXT NOT NULL,
+			endpoint TEXT NOT NULL,
+			before_state TEXT NOT NULL,
+			after_state TEXT NOT NULL,
+			created_at DATETIME NOT NULL
+		)`)
+	if err != nil {
+		return fmt.Errorf("failed to create audit_log table: %v", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS api_tokens (
+			name TEXT PRIMARY KEY,
+			token_hash TEXT NOT NULL UNIQUE,
+			scopes TEXT NOT NULL,
+			created_at DATETIME NOT NULL
+		)`)
+	if err != nil {
+		return fmt.Errorf("failed to create api_tokens table: %v", err)
+	}
+
 	_, err = db.Exec(`
 		INSERT INTO prompt (use_direct_url, llm_provider, temperature, content, model, llm_output_language, updated_at)
 		SELECT 1, 'openrouter', 0.2, 'Ти — AI асистент, що спеціалізується на створенні коротких описів GitHub-репозиторіїв українською мовою. Твоя відповідь **ПОВИННА** суворо відповідати **КОЖНІЙ** з наведених нижче вимог. Будь-яке відхилення, особливо щодо довжини тексту, є неприпустимим. Твоя основна задача — створювати описи на основі наданих URL.
@@ -183,16 +247,139 @@ func createTablesIfNotExist(db *sql.DB) error {
 			text_language TEXT,
 			socialify_image INTEGER NOT NULL DEFAULT 0,
 			default_json_body TEXT,
+			retry_max_attempts INTEGER NOT NULL DEFAULT 3,
+			body_template TEXT NOT NULL DEFAULT '',
+			headers TEXT NOT NULL DEFAULT '',
+			success_codes TEXT NOT NULL DEFAULT '',
+			success_field TEXT NOT NULL DEFAULT '',
+			success_value TEXT NOT NULL DEFAULT '',
+			post_id_field TEXT NOT NULL DEFAULT '',
+			post_url_field TEXT NOT NULL DEFAULT '',
+			image_settings TEXT NOT NULL DEFAULT '',
 			updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
 		)`)
 	if err != nil {
 		return fmt.Errorf("failed to create api_configs table: %v", err)
 	}
 
+	if err := migrateAPIConfigsSchema(db); err != nil {
+		return fmt.Errorf("failed to migrate api_configs schema: %v", err)
+	}
+
 	if err := migrateYAMLToDatabase(db); err != nil {
 		return fmt.Errorf("failed to migrate YAML to database: %v", err)
 	}
 
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS deliveries (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			url TEXT NOT NULL,
+			api_name TEXT NOT NULL,
+			status TEXT NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			manual_attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT,
+			first_attempt_at DATETIME NOT NULL,
+			last_attempt_at DATETIME NOT NULL,
+			delivered_at DATETIME,
+			next_attempt_at DATETIME,
+			post_id TEXT NOT NULL DEFAULT '',
+			post_url TEXT NOT NULL DEFAULT '',
+			UNIQUE(url, api_name)
+		)`)
+	if err != nil {
+		return fmt.Errorf("failed to create deliveries table: %v", err)
+	}
+
+	if err := migrateDeliveriesSchema(db); err != nil {
+		return fmt.Errorf("failed to migrate deliveries schema: %v", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS blackout_windows (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			starts TEXT NOT NULL DEFAULT '',
+			ends TEXT NOT NULL DEFAULT '',
+			weekdays TEXT NOT NULL DEFAULT '',
+			timezone TEXT NOT NULL DEFAULT 'UTC',
+			enabled INTEGER NOT NULL DEFAULT 1,
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL
+		)`)
+	if err != nil {
+		return fmt.Errorf("failed to create blackout_windows table: %v", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS collect_profiles (
+			name TEXT PRIMARY KEY,
+			max_repos INTEGER NOT NULL,
+			resource TEXT NOT NULL,
+			since TEXT NOT NULL,
+			spoken_language_code TEXT NOT NULL,
+			period TEXT NOT NULL,
+			language TEXT NOT NULL,
+			prompt TEXT NOT NULL DEFAULT '',
+			prompt_versions TEXT NOT NULL DEFAULT '',
+			created_at DATETIME NOT NULL,
+			updated_at DATETIME NOT NULL
+		)`)
+	if err != nil {
+		return fmt.Errorf("failed to create collect_profiles table: %v", err)
+	}
+
+	if err := migrateCollectProfilesSchema(db); err != nil {
+		return fmt.Errorf("failed to migrate collect_profiles schema: %v", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS prompt_versions (
+			name TEXT PRIMARY KEY,
+			content TEXT NOT NULL,
+			model TEXT NOT NULL DEFAULT '',
+			temperature REAL,
+			created_at DATETIME NOT NULL
+		)`)
+	if err != nil {
+		return fmt.Errorf("failed to create prompt_versions table: %v", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS image_settings (
+			id INTEGER PRIMARY KEY,
+			provider TEXT NOT NULL DEFAULT 'socialify',
+			socialify_url TEXT NOT NULL DEFAULT '',
+			static_file TEXT NOT NULL DEFAULT '',
+			theme TEXT NOT NULL,
+			font TEXT NOT NULL,
+			patterns TEXT NOT NULL,
+			logo TEXT NOT NULL DEFAULT '',
+			fields TEXT NOT NULL,
+			format TEXT NOT NULL,
+			width INTEGER NOT NULL,
+			updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`)
+	if err != nil {
+		return fmt.Errorf("failed to create image_settings table: %v", err)
+	}
+
+	if err := migrateImageSettingsSchema(db); err != nil {
+		return fmt.Errorf("failed to migrate image_settings schema: %v", err)
+	}
+
+	defaults := models.DefaultImageSettings()
+	_, err = db.Exec(`
+		INSERT INTO image_settings (id, theme, font, patterns, logo, fields, format, width, updated_at)
+		SELECT 1, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP
+		WHERE NOT EXISTS (SELECT 1 FROM image_settings)`,
+		defaults.Theme, defaults.Font, strings.Join(defaults.Patterns, ","), defaults.Logo,
+		strings.Join(defaults.Fields, ","), defaults.Format, defaults.Width)
+	if err != nil {
+		return fmt.Errorf("failed to insert default image settings: %v", err)
+	}
+
 	return nil
 }
 
@@ -234,6 +421,56 @@ func migrateCollectSettingsSchema(db *sql.DB) error {
 	return nil
 }
 
+// migrateCronSettingsSchema adds the job and connector list of a schedule. Rows
+// from before there were named schedules are the collect and message crons,
+// which run the job of the same name.
+func migrateCronSettingsSchema(db *sql.DB) error {
+	columns, err := tableColumns(db, "cron_settings")
+	if err != nil {
+		return err
+	}
+
+	if !columns["job"] {
+		if _, err := db.Exec("ALTER TABLE cron_settings ADD COLUMN job TEXT NOT NULL DEFAULT ''"); err != nil {
+			return fmt.Errorf("failed to add job column: %v", err)
+		}
+		if _, err := db.Exec("UPDATE cron_settings SET job = name WHERE job = ''"); err != nil {
+			return fmt.Errorf("failed to backfill job column: %v", err)
+		}
+	}
+
+	if !columns["apis"] {
+		if _, err := db.Exec("ALTER TABLE cron_settings ADD COLUMN apis TEXT NOT NULL DEFAULT ''"); err != nil {
+			return fmt.Errorf("failed to add apis column: %v", err)
+		}
+	}
+
+	if !columns["timezone"] {
+		if _, err := db.Exec("ALTER TABLE cron_settings ADD COLUMN timezone TEXT NOT NULL DEFAULT 'UTC'"); err != nil {
+			return fmt.Errorf("failed to add timezone column: %v", err)
+		}
+	}
+
+	if !columns["paused_until"] {
+		if _, err := db.Exec("ALTER TABLE cron_settings ADD COLUMN paused_until DATETIME"); err != nil {
+			return fmt.Errorf("failed to add paused_until column: %v", err)
+		}
+	}
+	if !columns["pause_reason"] {
+		if _, err := db.Exec("ALTER TABLE cron_settings ADD COLUMN pause_reason TEXT NOT NULL DEFAULT ''"); err != nil {
+			return fmt.Errorf("failed to add pause_reason column: %v", err)
+		}
+	}
+
+	if !columns["jitter_seconds"] {
+		if _, err := db.Exec("ALTER TABLE cron_settings ADD COLUMN jitter_seconds INTEGER NOT NULL DEFAULT 0"); err != nil {
+			return fmt.Errorf("failed to add jitter_seconds column: %v", err)
+		}
+	}
+
+	return nil
+}
+
 func migrateCronHistorySuccessToStatus(db *sql.DB) error {
 	columns, err := cronHistoryColumns(db)
 	if err != nil {
@@ -268,8 +505,167 @@ func migrateCronHistoryDetails(db *sql.DB) error {
 	return nil
 }
 
+func migrateCronHistoryRuns(db *sql.DB) error {
+	columns, err := cronHistoryColumns(db)
+	if err != nil {
+		return err
+	}
+
+	if !columns["run_id"] {
+		if _, err := db.Exec("ALTER TABLE cron_history ADD COLUMN run_id TEXT NOT NULL DEFAULT ''"); err != nil {
+			return fmt.Errorf("failed to add run_id column: %v", err)
+		}
+	}
+
+	if !columns["manual"] {
+		if _, err := db.Exec("ALTER TABLE cron_history ADD COLUMN manual INTEGER NOT NULL DEFAULT 0"); err != nil {
+			return fmt.Errorf("failed to add manual column: %v", err)
+		}
+	}
+
+	if !columns["scheduled_at"] {
+		if _, err := db.Exec("ALTER TABLE cron_history ADD COLUMN scheduled_at DATETIME"); err != nil {
+			return fmt.Errorf("failed to add scheduled_at column: %v", err)
+		}
+	}
+
+	if !columns["fired_at"] {
+		if _, err := db.Exec("ALTER TABLE cron_history ADD COLUMN fired_at DATETIME"); err != nil {
+			return fmt.Errorf("failed to add fired_at column: %v", err)
+		}
+	}
+
+	if !columns["profile"] {
+		if _, err := db.Exec("ALTER TABLE cron_history ADD COLUMN profile TEXT NOT NULL DEFAULT ''"); err != nil {
+			return fmt.Errorf("failed to add profile column: %v", err)
+		}
+	}
+
+	if !columns["prompt_version"] {
+		if _, err := db.Exec("ALTER TABLE cron_history ADD COLUMN prompt_version TEXT NOT NULL DEFAULT ''"); err != nil {
+			return fmt.Errorf("failed to add prompt_version column: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func migrateCollectProfilesSchema(db *sql.DB) error {
+	columns, err := tableColumns(db, "collect_profiles")
+	if err != nil {
+		return err
+	}
+
+	if !columns["prompt_versions"] {
+		if _, err := db.Exec("ALTER TABLE collect_profiles ADD COLUMN prompt_versions TEXT NOT NULL DEFAULT ''"); err != nil {
+			return fmt.Errorf("failed to add prompt_versions column: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func migrateAPIConfigsSchema(db *sql.DB) error {
+	columns, err := tableColumns(db, "api_configs")
+	if err != nil {
+		return err
+	}
+
+	if !columns["retry_max_attempts"] {
+		if _, err := db.Exec("ALTER TABLE api_configs ADD COLUMN retry_max_attempts INTEGER NOT NULL DEFAULT 3"); err != nil {
+			return fmt.Errorf("failed to add retry_max_attempts column: %v", err)
+		}
+	}
+
+	if !columns["body_template"] {
+		if _, err := db.Exec("ALTER TABLE api_configs ADD COLUMN body_template TEXT NOT NULL DEFAULT ''"); err != nil {
+			return fmt.Errorf("failed to add body_template column: %v", err)
+		}
+	}
+
+	if !columns["headers"] {
+		if _, err := db.Exec("ALTER TABLE api_configs ADD COLUMN headers TEXT NOT NULL DEFAULT ''"); err != nil {
+			return fmt.Errorf("failed to add headers column: %v", err)
+		}
+		if err := backfillHeadersFromYAML(db); err != nil {
+			return err
+		}
+	}
+
+	for _, column := range []string{"success_codes", "success_field", "success_value", "post_id_field", "post_url_field", "image_settings"} {
+		if columns[column] {
+			continue
+		}
+		if _, err := db.Exec("ALTER TABLE api_configs ADD COLUMN " + column + " TEXT NOT NULL DEFAULT ''"); err != nil {
+			return fmt.Errorf("failed to add %s column: %v", column, err)
+		}
+	}
+
+	return nil
+}
+
+func migrateImageSettingsSchema(db *sql.DB) error {
+	columns, err := tableColumns(db, "image_settings")
+	if err != nil {
+		return err
+	}
+
+	if !columns["provider"] {
+		if _, err := db.Exec("ALTER TABLE image_settings ADD COLUMN provider TEXT NOT NULL DEFAULT 'socialify'"); err != nil {
+			return fmt.Errorf("failed to add provider column: %v", err)
+		}
+	}
+
+	for _, column := range []string{"socialify_url", "static_file"} {
+		if columns[column] {
+			continue
+		}
+		if _, err := db.Exec("ALTER TABLE image_settings ADD COLUMN " + column + " TEXT NOT NULL DEFAULT ''"); err != nil {
+			return fmt.Errorf("failed to add %s column: %v", column, err)
+		}
+	}
+
+	return nil
+}
+
+func migrateDeliveriesSchema(db *sql.DB) error {
+	columns, err := tableColumns(db, "deliveries")
+	if err != nil {
+		return err
+	}
+
+	if !columns["next_attempt_at"] {
+		if _, err := db.Exec("ALTER TABLE deliveries ADD COLUMN next_attempt_at DATETIME"); err != nil {
+			return fmt.Errorf("failed to add next_attempt_at column: %v", err)
+		}
+	}
+
+	for _, column := range []string{"post_id", "post_url"} {
+		if columns[column] {
+			continue
+		}
+		if _, err := db.Exec("ALTER TABLE deliveries ADD COLUMN " + column + " TEXT NOT NULL DEFAULT ''"); err != nil {
+			return fmt.Errorf("failed to add %s column: %v", column, err)
+		}
+	}
+
+	if !columns["manual_attempts"] {
+		if _, err := db.Exec("ALTER TABLE deliveries ADD COLUMN manual_attempts INTEGER NOT NULL DEFAULT 0"); err != nil {
+			return fmt.Errorf("failed to add manual_attempts column: %v", err)
+		}
+	}
+
+	return nil
+}
+
 func cronHistoryColumns(db *sql.DB) (map[string]bool, error) {
-	rows, err := db.Query("PRAGMA table_info(cron_history)")
+	return tableColumns(db, "cron_history")
+}
+
+// tableColumns lists the columns of a table, so schema migrations can add only
+// what an older database is missing.
+func tableColumns(db *sql.DB, table string) (map[string]bool, error) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
 	if err != nil {
 		return nil, fmt.Errorf("failed to query table info: %v", err)
 	}
@@ -290,28 +686,63 @@ func cronHistoryColumns(db *sql.DB) (map[string]bool, error) {
 	return columns, rows.Err()
 }
 
+// nullableTime stores a missing time as NULL.
+func nullableTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return *t
+}
+
 func (s *SQLiteStore) Close() error {
 	return s.db.Close()
 }
 
-func (s *SQLiteStore) GetCronSetting(name string) (*models.CronSetting, error) {
+const cronSettingColumns = "name, schedule, is_active, updated_at, job, apis, timezone, paused_until, pause_reason, jitter_seconds"
+
+func scanCronSetting(row rowScanner) (*models.CronSetting, error) {
 	var setting models.CronSetting
 	var isActive int
-	query := "SELECT name, schedule, is_active, updated_at FROM cron_settings WHERE name = ?"
-	err := s.db.QueryRow(query, name).Scan(&setting.Name, &setting.Schedule, &isActive, &setting.UpdatedAt)
+	var apis string
+	var pausedUntil sql.NullTime
+	if err := row.Scan(&setting.Name, &setting.Schedule, &isActive, &setting.UpdatedAt, &setting.Job, &apis, &setting.Timezone,
+		&pausedUntil, &setting.PauseReason, &setting.JitterSeconds); err != nil {
+		return nil, err
+	}
+	setting.IsActive = isActive == 1
+	setting.APIs = parseNameList(apis)
+	if pausedUntil.Valid {
+		setting.PausedUntil = &pausedUntil.Time
+	}
+	return &setting, nil
+}
+
+// parseNameList reads a connector list stored as "telegram,bluesky".
+func parseNameList(value string) []string {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (s *SQLiteStore) GetCronSetting(name string) (*models.CronSetting, error) {
+	query := "SELECT " + cronSettingColumns + " FROM cron_settings WHERE name = ?"
+	setting, err := scanCronSetting(s.db.QueryRow(query, name))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get cron setting: %v", err)
 	}
-	setting.IsActive = isActive == 1
-	return &setting, nil
+	return setting, nil
 }
 
 func (s *SQLiteStore) GetAllCronSettings() ([]models.CronSetting, error) {
 	var settings []models.CronSetting
-	query := "SELECT name, schedule, is_active, updated_at FROM cron_settings ORDER BY id ASC"
+	query := "SELECT " + cronSettingColumns + " FROM cron_settings ORDER BY id ASC"
 	rows, err := s.db.Query(query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all cron settings: %v", err)
@@ -319,17 +750,18 @@ func (s *SQLiteStore) GetAllCronSettings() ([]models.CronSetting, error) {
 	defer rows.Close()
 
 	for rows.Next() {
-		var setting models.CronSetting
-		var isActive int
-		if err := rows.Scan(&setting.Name, &setting.Schedule, &isActive, &setting.UpdatedAt); err != nil {
+		setting, err := scanCronSetting(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan cron setting: %v", err)
 		}
-		setting.IsActive = isActive == 1
-		settings = append(settings, setting)
+		settings = append(settings, *setting)
 	}
 	return settings, nil
 }
 
+// UpdateCronSetting changes the schedule and state of a cron. A cron created
+// through it runs the job of the same name, which is how the collect and message
+// defaults are set up.
 func (s *SQLiteStore) UpdateCronSetting(name string, schedule string, isActive bool) (*models.CronSetting, error) {
 	isActiveInt := boolToInt(isActive)
 
@@ -341,17 +773,149 @@ func (s *SQLiteStore) UpdateCronSetting(name string, schedule string, isActive b
 	}
 
 	query := `
-		INSERT INTO cron_settings (name, schedule, is_active, updated_at)
-		VALUES (?, ?, ?, ?)
+		INSERT INTO cron_settings (name, schedule, is_active, updated_at, job)
+		VALUES (?, ?, ?, ?, ?)
 		ON CONFLICT(name) DO UPDATE
 		SET schedule = excluded.schedule, is_active = excluded.is_active, updated_at = excluded.updated_at`
-	_, err := s.db.Exec(query, name, schedule, isActiveInt, setting.UpdatedAt)
+	_, err := s.db.Exec(query, name, schedule, isActiveInt, setting.UpdatedAt, name)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update cron setting: %v", err)
 	}
 	return &setting, nil
 }
 
+// CreateCronSetting adds a named message schedule.
+func (s *SQLiteStore) CreateCronSetting(req *models.CreateCronRequest) (*models.CronSetting, error) {
+	setting := models.CronSetting{
+		Name:          req.Name,
+		Schedule:      req.Schedule,
+		IsActive:      req.IsActive,
+		UpdatedAt:     time.Now(),
+		Job:           models.JobMessage,
+		APIs:          req.APIs,
+		Timezone:      req.Timezone,
+		JitterSeconds: req.JitterSeconds,
+	}
+	if setting.Timezone == "" {
+		setting.Timezone = models.DefaultTimezone
+	}
+
+	query := `
+		INSERT INTO cron_settings (name, schedule, is_active, updated_at, job, apis, timezone, jitter_seconds)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := s.db.Exec(query, setting.Name, setting.Schedule, boolToInt(setting.IsActive), setting.UpdatedAt,
+		setting.Job, strings.Join(setting.APIs, ","), setting.Timezone, setting.JitterSeconds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cron setting: %v", err)
+	}
+	return &setting, nil
+}
+
+// UpdateCronAPIs replaces the connectors a message schedule publishes to.
+func (s *SQLiteStore) UpdateCronAPIs(name string, apis []string) (*models.CronSetting, error) {
+	query := "UPDATE cron_settings SET apis = ?, updated_at = ? WHERE name = ?"
+	result, err := s.db.Exec(query, strings.Join(apis, ","), time.Now(), name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update cron apis: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return nil, fmt.Errorf("cron '%s' not found", name)
+	}
+
+	return s.GetCronSetting(name)
+}
+
+// UpdateCronSchedule changes when a cron runs - its schedule, the zone it is
+// read in and how far runs may stray from it - in one write, so a failure
+// leaves none of them changed.
+func (s *SQLiteStore) UpdateCronSchedule(name, schedule, timezone string, jitterSeconds int) error {
+	result, err := s.db.Exec("UPDATE cron_settings SET schedule = ?, timezone = ?, jitter_seconds = ?, updated_at = ? WHERE name = ?",
+		schedule, timezone, jitterSeconds, time.Now(), name)
+	if err != nil {
+		return fmt.Errorf("failed to update cron schedule: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("cron '%s' not found", name)
+	}
+
+	return nil
+}
+
+// UpdateCronStatus switches a cron on or off and lifts any pause in the same
+// write: switching is the final word on a cron, and a pending pause would
+// otherwise resume one just switched off.
+func (s *SQLiteStore) UpdateCronStatus(name string, isActive bool) error {
+	result, err := s.db.Exec("UPDATE cron_settings SET is_active = ?, paused_until = NULL, pause_reason = '', updated_at = ? WHERE name = ?",
+		boolToInt(isActive), time.Now(), name)
+	if err != nil {
+		return fmt.Errorf("failed to update cron status: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("cron '%s' not found", name)
+	}
+
+	return nil
+}
+
+// UpdateCronPause pauses a cron until the given time, or lifts its pause when
+// until is nil.
+func (s *SQLiteStore) UpdateCronPause(name string, until *time.Time, reason string) error {
+	var pausedUntil any
+	if until != nil {
+		pausedUntil = until.UTC()
+	} else {
+		reason = ""
+	}
+
+	result, err := s.db.Exec("UPDATE cron_settings SET paused_until = ?, pause_reason = ?, updated_at = ? WHERE name = ?",
+		pausedUntil, reason, time.Now(), name)
+	if err != nil {
+		return fmt.Errorf("failed to update cron pause: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("cron '%s' not found", name)
+	}
+
+	return nil
+}
+
+func (s *SQLiteStore) DeleteCronSetting(name string) error {
+	result, err := s.db.Exec("DELETE FROM cron_settings WHERE name = ?", name)
+	if err != nil {
+		return fmt.Errorf("failed to delete cron setting: %v", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %v", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("cron '%s' not found", name)
+	}
+
+	return nil
+}
+
 func (s *SQLiteStore) InitializeDefaultSettings() error {
 	defaults := []models.CronSetting{
 		{
@@ -400,12 +964,19 @@ func (s *SQLiteStore) LogCronExecution(name string, status int, output string) e
 }
 
 func (s *SQLiteStore) LogCronExecutionDetails(name string, status int, output string, details *models.MessageRunDetails) error {
+	return s.LogCronHistory(&models.CronHistory{Name: name, Success: status, Output: output, Details: details})
+}
+
+// LogCronHistory records one run of a job, including the run ID and whether it
+// was triggered by hand. The timestamp is always the time of the call.
+func (s *SQLiteStore) LogCronHistory(entry *models.CronHistory) error {
+	name, status, output := entry.Name, entry.Success, entry.Output
 	if name == "" {
 		return fmt.Errorf("cron job name cannot be empty")
 	}
 
-	if status < 0 || status > 2 {
-		return fmt.Errorf("invalid status value: %d (must be 0, 1, or 2)", status)
+	if status < models.CronStatusFailure || status > models.CronStatusBlackout {
+		return fmt.Errorf("invalid status value: %d (must be 0, 1, 2, 3, or 4)", status)
 	}
 
 	const maxOutputLength = 10000
@@ -416,16 +987,19 @@ func (s *SQLiteStore) LogCronExecutionDetails(name string, status int, output st
 	timestamp := time.Now()
 
 	var encodedDetails any
-	if details != nil {
-		encoded, err := json.Marshal(details)
+	if entry.Details != nil {
+		encoded, err := json.Marshal(entry.Details)
 		if err != nil {
 			return fmt.Errorf("failed to encode cron execution details: %v", err)
 		}
 		encodedDetails = string(encoded)
 	}
 
-	query := "INSERT INTO cron_history (name, timestamp, status, output, details) VALUES (?, ?, ?, ?, ?)"
-	_, err := s.db.Exec(query, name, timestamp, status, output, encodedDetails)
+	query := `
+		INSERT INTO cron_history (name, timestamp, status, output, details, run_id, manual, scheduled_at, fired_at, profile, prompt_version)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := s.db.Exec(query, name, timestamp, status, output, encodedDetails, entry.RunID, boolToInt(entry.Manual),
+		nullableTime(entry.ScheduledAt), nullableTime(entry.FiredAt), entry.Profile, entry.PromptVersion)
 	if err != nil {
 		fmt.Printf("Failed to log cron execution to database: %v\n", err)
 		fmt.Printf("Attempted to log: name=%s, status=%d, timestamp=%v, output_length=%d\n",
@@ -439,6 +1013,27 @@ func (s *SQLiteStore) LogCronExecutionDetails(name string, status int, output st
 	return nil
 }
 
+// GetLastCronRun returns the latest recorded run of a cron, or nil when it has
+// none. Skipped and blacked-out runs are passed over: they say nothing about how
+// the job fared.
+func (s *SQLiteStore) GetLastCronRun(name string) (*models.CronRunSummary, error) {
+	var run models.CronRunSummary
+	var manual int
+	query := `
+		SELECT run_id, timestamp, status, manual FROM cron_history
+		WHERE name = ? AND status NOT IN (?, ?)
+		ORDER BY timestamp DESC, id DESC LIMIT 1`
+	err := s.db.QueryRow(query, name, models.CronStatusSkipped, models.CronStatusBlackout).Scan(&run.RunID, &run.Timestamp, &run.Status, &manual)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last cron run: %v", err)
+	}
+	run.Manual = manual == 1
+	return &run, nil
+}
+
 func (s *SQLiteStore) GetCronHistoryCount(name string, status *int, startDate, endDate *time.Time) (int, error) {
 	query := "SELECT COUNT(*) FROM cron_history WHERE 1=1"
 	args := []any{}
@@ -469,7 +1064,7 @@ func (s *SQLiteStore) GetCronHistoryCount(name string, status *int, startDate, e
 }
 
 func (s *SQLiteStore) GetCronHistory(name string, status *int, offset, limit int, sortOrder string, startDate, endDate *time.Time) ([]models.CronHistory, error) {
-	query := "SELECT name, timestamp, status, output, details FROM cron_history WHERE 1=1"
+	query := "SELECT name, timestamp, status, output, details, run_id, manual, scheduled_at, fired_at, profile, prompt_version FROM cron_history WHERE 1=1"
 	args := []any{}
 
 	if name != "" {
@@ -509,9 +1104,18 @@ func (s *SQLiteStore) GetCronHistory(name string, status *int, offset, limit int
 		var h models.CronHistory
 		// details is NULL for every run recorded before the column existed.
 		var details sql.NullString
-		if err := rows.Scan(&h.Name, &h.Timestamp, &h.Success, &h.Output, &details); err != nil {
+		var manual int
+		var scheduledAt, firedAt sql.NullTime
+		if err := rows.Scan(&h.Name, &h.Timestamp, &h.Success, &h.Output, &details, &h.RunID, &manual, &scheduledAt, &firedAt, &h.Profile, &h.PromptVersion); err != nil {
 			return nil, fmt.Errorf("failed to scan cron history: %v", err)
 		}
+		h.Manual = manual == 1
+		if scheduledAt.Valid {
+			h.ScheduledAt = &scheduledAt.Time
+		}
+		if firedAt.Valid {
+			h.FiredAt = &firedAt.Time
+		}
 		if details.Valid && details.String != "" {
 			var parsed models.MessageRunDetails
 			if err := json.Unmarshal([]byte(details.String), &parsed); err != nil {
@@ -565,14 +1169,16 @@ func (s *SQLiteStore) UpdateCollectSettings(settings *CollectSettings) error {
 	return err
 }
 
-func (s *SQLiteStore) GetPromptSettings() (*models.PromptSettings, error) {
-	var settings models.PromptSettings
-	var useDirectURL int
-	err := s.db.QueryRow(`
+const promptSettingsQuery = `
 		SELECT use_direct_url, llm_provider, temperature, content, model, llm_output_language, updated_at
 		FROM prompt
 		WHERE id = 1
-	`).Scan(&useDirectURL, &settings.LlmProvider, &settings.Temperature, &settings.Content, &settings.Model, &settings.LlmOutputLanguage, &settings.UpdatedAt)
+	`
+
+func scanPromptSettings(row rowScanner) (*models.PromptSettings, error) {
+	var settings models.PromptSettings
+	var useDirectURL int
+	err := row.Scan(&useDirectURL, &settings.LlmProvider, &settings.Temperature, &settings.Content, &settings.Model, &settings.LlmOutputLanguage, &settings.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -580,7 +1186,32 @@ func (s *SQLiteStore) GetPromptSettings() (*models.PromptSettings, error) {
 	return &settings, nil
 }
 
-func (s *SQLiteStore) UpdatePromptSettings(settings *models.UpdatePromptSettingsRequest) error {
+func (s *SQLiteStore) GetPromptSettings() (*models.PromptSettings, error) {
+	return scanPromptSettings(s.db.QueryRow(promptSettingsQuery))
+}
+
+// UpdatePromptSettings changes the prompt settings that are set and records the
+// change as a revision made by actor.
+func (s *SQLiteStore) UpdatePromptSettings(settings *models.UpdatePromptSettingsRequest, actor string) error {
+	_, err := s.updatePromptSettings(settings, actor, nil)
+	return err
+}
+
+// updatePromptSettings applies an update and appends the revision it makes,
+// both or neither. It returns nil when the update changed nothing, which
+// records no revision.
+func (s *SQLiteStore) updatePromptSettings(settings *models.UpdatePromptSettingsRequest, actor string, rolledBackFrom *int64) (*models.PromptRevision, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	before, err := scanPromptSettings(tx.QueryRow(promptSettingsQuery))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt settings: %v", err)
+	}
+
 	query := `UPDATE prompt SET updated_at = ?`
 	args := []interface{}{time.Now()}
 
@@ -616,100 +1247,237 @@ func (s *SQLiteStore) UpdatePromptSettings(settings *models.UpdatePromptSettings
 
 	query += " WHERE id = 1"
 
-	_, err := s.db.Exec(query, args...)
-	return err
+	if _, err := tx.Exec(query, args...); err != nil {
+		return nil, err
+	}
+
+	after, err := scanPromptSettings(tx.QueryRow(promptSettingsQuery))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt settings: %v", err)
+	}
+
+	revision, err := recordPromptRevision(tx, before, after, actor, rolledBackFrom)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit prompt settings: %v", err)
+	}
+	return revision, nil
 }
 
-func migrateYAMLToDatabase(db *sql.DB) error {
-	var count int
-	err := db.QueryRow("SELECT COUNT(*) FROM api_configs").Scan(&count)
+// yamlAPIEndpoint is an entry of apis-config.yml, the file API configs lived in
+// before they moved to the database.
+type yamlAPIEndpoint struct {
+	URL             string            `yaml:"url"`
+	Method          string            `yaml:"method"`
+	Headers         map[string]string `yaml:"headers"`
+	AuthType        string            `yaml:"auth_type"`
+	TokenEnvVar     string            `yaml:"token_env_var"`
+	TokenHeader     string            `yaml:"token_header"`
+	ContentType     string            `yaml:"content_type"`
+	Timeout         int               `yaml:"timeout"`
+	SuccessCode     int               `yaml:"success_code"`
+	Enabled         bool              `yaml:"enabled"`
+	ResponseType    string            `yaml:"response_type"`
+	TextLanguage    string            `yaml:"text_language"`
+	SocialifyImage  bool              `yaml:"socialify_image"`
+	DefaultJSONBody map[string]string `yaml:"default_json_body"`
+}
+
+const yamlAPIConfigPath = "./internal/api/apis-config.yml"
+
+// readYAMLAPIConfigs returns the endpoints of apis-config.yml, or nil when the
+// file does not exist.
+func readYAMLAPIConfigs() (map[string]yamlAPIEndpoint, error) {
+	if _, err := os.Stat(yamlAPIConfigPath); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(yamlAPIConfigPath)
 	if err != nil {
-		return fmt.Errorf("failed to check api_configs table: %v", err)
+		return nil, fmt.Errorf("failed to read YAML config: %v", err)
 	}
 
-	if count > 0 {
-		return nil
+	var config struct {
+		APIs map[string]yamlAPIEndpoint `yaml:"apis"`
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML config: %v", err)
 	}
 
-	yamlPath := "./internal/api/apis-config.yml"
-	if _, err := os.Stat(yamlPath); os.IsNotExist(err) {
-		return nil
+	return config.APIs, nil
+}
+
+// marshalStringMap stores a string map the way api_configs keeps them: as a
+// JSON object, or an empty string when there is nothing to store.
+func marshalStringMap(values map[string]string) (string, error) {
+	if len(values) == 0 {
+		return "", nil
 	}
 
-	data, err := os.ReadFile(yamlPath)
+	jsonBytes, err := json.Marshal(values)
 	if err != nil {
-		return fmt.Errorf("failed to read YAML config: %v", err)
+		return "", err
 	}
+	return string(jsonBytes), nil
+}
 
-	type YAMLAPIEndpoint struct {
-		URL             string            `yaml:"url"`
-		Method          string            `yaml:"method"`
-		AuthType        string            `yaml:"auth_type"`
-		TokenEnvVar     string            `yaml:"token_env_var"`
-		TokenHeader     string            `yaml:"token_header"`
-		ContentType     string            `yaml:"content_type"`
-		Timeout         int               `yaml:"timeout"`
-		SuccessCode     int               `yaml:"success_code"`
-		Enabled         bool              `yaml:"enabled"`
-		ResponseType    string            `yaml:"response_type"`
-		TextLanguage    string            `yaml:"text_language"`
-		SocialifyImage  bool              `yaml:"socialify_image"`
-		DefaultJSONBody map[string]string `yaml:"default_json_body"`
+func migrateYAMLToDatabase(db *sql.DB) error {
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM api_configs").Scan(&count)
+	if err != nil {
+		return fmt.Errorf("failed to check api_configs table: %v", err)
 	}
 
-	type YAMLAPIConfig struct {
-		APIs map[string]YAMLAPIEndpoint `yaml:"apis"`
+	if count > 0 {
+		return nil
 	}
 
-	var config YAMLAPIConfig
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return fmt.Errorf("failed to parse YAML config: %v", err)
+	apis, err := readYAMLAPIConfigs()
+	if err != nil || apis == nil {
+		return err
 	}
 
-	for name, endpoint := range config.APIs {
-		var defaultJSONBodyStr string
-		if len(endpoint.DefaultJSONBody) > 0 {
-			jsonBytes, err := json.Marshal(endpoint.DefaultJSONBody)
-			if err != nil {
-				return fmt.Errorf("failed to marshal default_json_body for %s: %v", name, err)
-			}
-			defaultJSONBodyStr = string(jsonBytes)
+	for name, endpoint := range apis {
+		defaultJSONBodyStr, err := marshalStringMap(endpoint.DefaultJSONBody)
+		if err != nil {
+			return fmt.Errorf("failed to marshal default_json_body for %s: %v", name, err)
+		}
+
+		headersStr, err := marshalStringMap(endpoint.Headers)
+		if err != nil {
+			return fmt.Errorf("failed to marshal headers for %s: %v", name, err)
 		}
 
 		query := `
 			INSERT INTO api_configs (name, url, method, auth_type, token_env_var, token_header,
 				content_type, timeout, success_code, enabled, response_type, text_language,
-				socialify_image, default_json_body, updated_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)`
+				socialify_image, default_json_body, headers, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)`
 
-		_, err := db.Exec(query, name, endpoint.URL, endpoint.Method, endpoint.AuthType,
+		_, err = db.Exec(query, name, endpoint.URL, endpoint.Method, endpoint.AuthType,
 			endpoint.TokenEnvVar, endpoint.TokenHeader, endpoint.ContentType, endpoint.Timeout,
 			endpoint.SuccessCode, boolToInt(endpoint.Enabled), endpoint.ResponseType,
-			endpoint.TextLanguage, boolToInt(endpoint.SocialifyImage), defaultJSONBodyStr)
+			endpoint.TextLanguage, boolToInt(endpoint.SocialifyImage), defaultJSONBodyStr, headersStr)
 		if err != nil {
 			return fmt.Errorf("failed to insert API config %s: %v", name, err)
 		}
 	}
 
-	fmt.Printf("Successfully migrated %d API configurations from YAML to database\n", len(config.APIs))
+	fmt.Printf("Successfully migrated %d API configurations from YAML to database\n", len(apis))
 	return nil
 }
 
-func (s *SQLiteStore) GetAPIConfig(name string) (*models.APIConfigModel, error) {
+// backfillHeadersFromYAML restores the headers of configs that were migrated
+// from apis-config.yml before the headers column existed: the migration
+// dropped them, so those connectors have been running without them since.
+func backfillHeadersFromYAML(db *sql.DB) error {
+	apis, err := readYAMLAPIConfigs()
+	if err != nil || apis == nil {
+		return err
+	}
+
+	for name, endpoint := range apis {
+		headersStr, err := marshalStringMap(endpoint.Headers)
+		if err != nil {
+			return fmt.Errorf("failed to marshal headers for %s: %v", name, err)
+		}
+		if headersStr == "" {
+			continue
+		}
+
+		if _, err := db.Exec("UPDATE api_configs SET headers = ? WHERE name = ? AND headers = ''", headersStr, name); err != nil {
+			return fmt.Errorf("failed to backfill headers for %s: %v", name, err)
+		}
+	}
+
+	return nil
+}
+
+// apiConfigColumns is the column list scanAPIConfig reads, shared so a new
+// column is added in one place.
+const apiConfigColumns = `id, name, url, method, auth_type, token_env_var, token_header,
+		content_type, timeout, success_code, enabled, response_type, text_language,
+		socialify_image, default_json_body, headers, success_codes, success_field, success_value,
+		post_id_field, post_url_field, retry_max_attempts, body_template, image_settings, updated_at`
+
+func scanAPIConfig(row rowScanner) (*models.APIConfigModel, error) {
 	var config models.APIConfigModel
 	var enabled, socialifyImage int
+	var successCodes, imageSettings string
+
+	if err := row.Scan(&config.ID, &config.Name, &config.URL, &config.Method,
+		&config.AuthType, &config.TokenEnvVar, &config.TokenHeader, &config.ContentType,
+		&config.Timeout, &config.SuccessCode, &enabled, &config.ResponseType,
+		&config.TextLanguage, &socialifyImage, &config.DefaultJSONBody, &config.Headers,
+		&successCodes, &config.SuccessField, &config.SuccessValue,
+		&config.PostIDField, &config.PostURLField, &config.RetryMaxAttempts, &config.BodyTemplate, &imageSettings, &config.UpdatedAt); err != nil {
+		return nil, err
+	}
 
-	query := `SELECT id, name, url, method, auth_type, token_env_var, token_header,
-		content_type, timeout, success_code, enabled, response_type, text_language,
-		socialify_image, default_json_body, updated_at
-		FROM api_configs WHERE name = ?`
+	config.Enabled = enabled == 1
+	config.SocialifyImage = socialifyImage == 1
 
-	err := s.db.QueryRow(query, name).Scan(
-		&config.ID, &config.Name, &config.URL, &config.Method, &config.AuthType,
-		&config.TokenEnvVar, &config.TokenHeader, &config.ContentType, &config.Timeout,
-		&config.SuccessCode, &enabled, &config.ResponseType, &config.TextLanguage,
-		&socialifyImage, &config.DefaultJSONBody, &config.UpdatedAt)
+	codes, err := parseStatusCodes(successCodes)
+	if err != nil {
+		return nil, err
+	}
+	config.SuccessCodes = codes
+
+	if imageSettings != "" {
+		if err := json.Unmarshal([]byte(imageSettings), &config.ImageSettings); err != nil {
+			return nil, fmt.Errorf("invalid image settings of %s: %v", config.Name, err)
+		}
+	}
 
+	return &config, nil
+}
+
+// formatImageOverrides stores a connector's image overrides as a JSON object,
+// or as an empty string when it has none.
+func formatImageOverrides(overrides *models.ImageOverrides) (string, error) {
+	if overrides.IsEmpty() {
+		return "", nil
+	}
+	data, err := json.Marshal(overrides)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode image settings: %v", err)
+	}
+	return string(data), nil
+}
+
+// formatStatusCodes stores a status code list as "200,201"; parseStatusCodes
+// reads it back.
+func formatStatusCodes(codes []int) string {
+	parts := make([]string, len(codes))
+	for i, code := range codes {
+		parts[i] = strconv.Itoa(code)
+	}
+	return strings.Join(parts, ",")
+}
+
+func parseStatusCodes(value string) ([]int, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var codes []int
+	for _, part := range strings.Split(value, ",") {
+		code, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("invalid success code %q: %v", part, err)
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+func (s *SQLiteStore) GetAPIConfig(name string) (*models.APIConfigModel, error) {
+	query := "SELECT " + apiConfigColumns + " FROM api_configs WHERE name = ?"
+
+	config, err := scanAPIConfig(s.db.QueryRow(query, name))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -717,19 +1485,13 @@ func (s *SQLiteStore) GetAPIConfig(name string) (*models.APIConfigModel, error)
 		return nil, fmt.Errorf("failed to get API config: %v", err)
 	}
 
-	config.Enabled = enabled == 1
-	config.SocialifyImage = socialifyImage == 1
-
-	return &config, nil
+	return config, nil
 }
 
 func (s *SQLiteStore) GetAllAPIConfigs() ([]models.APIConfigModel, error) {
 	var configs []models.APIConfigModel
 
-	query := `SELECT id, name, url, method, auth_type, token_env_var, token_header,
-		content_type, timeout, success_code, enabled, response_type, text_language,
-		socialify_image, default_json_body, updated_at
-		FROM api_configs ORDER BY name`
+	query := "SELECT " + apiConfigColumns + " FROM api_configs ORDER BY name"
 
 	rows, err := s.db.Query(query)
 	if err != nil {
@@ -738,36 +1500,41 @@ func (s *SQLiteStore) GetAllAPIConfigs() ([]models.APIConfigModel, error) {
 	defer rows.Close()
 
 	for rows.Next() {
-		var config models.APIConfigModel
-		var enabled, socialifyImage int
-
-		if err := rows.Scan(&config.ID, &config.Name, &config.URL, &config.Method,
-			&config.AuthType, &config.TokenEnvVar, &config.TokenHeader, &config.ContentType,
-			&config.Timeout, &config.SuccessCode, &enabled, &config.ResponseType,
-			&config.TextLanguage, &socialifyImage, &config.DefaultJSONBody, &config.UpdatedAt); err != nil {
+		config, err := scanAPIConfig(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan API config: %v", err)
 		}
 
-		config.Enabled = enabled == 1
-		config.SocialifyImage = socialifyImage == 1
-
-		configs = append(configs, config)
+		configs = append(configs, *config)
 	}
 
 	return configs, nil
 }
 
 func (s *SQLiteStore) CreateAPIConfig(config *models.CreateAPIConfigRequest) (*models.APIConfigModel, error) {
+	retryMaxAttempts := models.DefaultRetryMaxAttempts
+	if config.RetryMaxAttempts != nil {
+		retryMaxAttempts = *config.RetryMaxAttempts
+	}
+
+	imageSettings, err := formatImageOverrides(config.ImageSettings)
+	if err != nil {
+		return nil, err
+	}
+
 	query := `
 		INSERT INTO api_configs (name, url, method, auth_type, token_env_var, token_header,
 			content_type, timeout, success_code, enabled, response_type, text_language,
-			socialify_image, default_json_body, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)`
+			socialify_image, default_json_body, headers, success_codes, success_field, success_value,
+			post_id_field, post_url_field, retry_max_attempts, body_template, image_settings, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)`
 
-	_, err := s.db.Exec(query, config.Name, config.URL, config.Method, config.AuthType,
+	_, err = s.db.Exec(query, config.Name, config.URL, config.Method, config.AuthType,
 		config.TokenEnvVar, config.TokenHeader, config.ContentType, config.Timeout,
 		config.SuccessCode, boolToInt(config.Enabled), config.ResponseType,
-		config.TextLanguage, boolToInt(config.SocialifyImage), config.DefaultJSONBody)
+		config.TextLanguage, boolToInt(config.SocialifyImage), config.DefaultJSONBody,
+		config.Headers, formatStatusCodes(config.SuccessCodes), config.SuccessField, config.SuccessValue,
+		config.PostIDField, config.PostURLField, retryMaxAttempts, config.BodyTemplate, imageSettings)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to create API config: %v", err)
@@ -845,6 +1612,55 @@ func (s *SQLiteStore) UpdateAPIConfig(name string, config *models.UpdateAPIConfi
 		args = append(args, *config.DefaultJSONBody)
 	}
 
+	if config.Headers != nil {
+		query += ", headers = ?"
+		args = append(args, *config.Headers)
+	}
+
+	if config.SuccessCodes != nil {
+		query += ", success_codes = ?"
+		args = append(args, formatStatusCodes(*config.SuccessCodes))
+	}
+
+	if config.SuccessField != nil {
+		query += ", success_field = ?"
+		args = append(args, *config.SuccessField)
+	}
+
+	if config.SuccessValue != nil {
+		query += ", success_value = ?"
+		args = append(args, *config.SuccessValue)
+	}
+
+	if config.PostIDField != nil {
+		query += ", post_id_field = ?"
+		args = append(args, *config.PostIDField)
+	}
+
+	if config.PostURLField != nil {
+		query += ", post_url_field = ?"
+		args = append(args, *config.PostURLField)
+	}
+
+	if config.RetryMaxAttempts != nil {
+		query += ", retry_max_attempts = ?"
+		args = append(args, *config.RetryMaxAttempts)
+	}
+
+	if config.BodyTemplate != nil {
+		query += ", body_template = ?"
+		args = append(args, *config.BodyTemplate)
+	}
+
+	if config.ImageSettings != nil {
+		imageSettings, err := formatImageOverrides(config.ImageSettings)
+		if err != nil {
+			return nil, err
+		}
+		query += ", image_settings = ?"
+		args = append(args, imageSettings)
+	}
+
 	query += " WHERE name = ?"
 	args = append(args, name)
 
@@ -2,6 +2,8 @@ package store
 
 import (
 	"content-maestro/internal/models"
+	"database/sql"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -98,6 +100,182 @@ func TestSQLiteStore_UpdateCronSetting_NewSetting(t *testing.T) {
 	assert.NotNil(t, setting)
 }
 
+func TestSQLiteStore_MessageSchedules(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	created, err := store.CreateCronSetting(&models.CreateCronRequest{
+		Name: "telegram-morning", Schedule: "0 9 * * *", IsActive: true, APIs: []string{"telegram"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, models.JobMessage, created.Job)
+
+	_, err = store.CreateCronSetting(&models.CreateCronRequest{Name: "telegram-morning", Schedule: "0 10 * * *"})
+	assert.Error(t, err, "names are unique")
+
+	setting, err := store.GetCronSetting("telegram-morning")
+	require.NoError(t, err)
+	require.NotNil(t, setting)
+	assert.Equal(t, models.JobMessage, setting.Job)
+	assert.Equal(t, []string{"telegram"}, setting.APIs)
+	assert.True(t, setting.IsActive)
+
+	// Changing the schedule keeps the job and connectors.
+	_, err = store.UpdateCronSetting("telegram-morning", "30 9 * * *", false)
+	require.NoError(t, err)
+	updated, err := store.UpdateCronAPIs("telegram-morning", []string{"telegram", "bluesky"})
+	require.NoError(t, err)
+	assert.Equal(t, "30 9 * * *", updated.Schedule)
+	assert.Equal(t, models.JobMessage, updated.Job)
+	assert.Equal(t, []string{"telegram", "bluesky"}, updated.APIs)
+
+	_, err = store.UpdateCronAPIs("missing", nil)
+	assert.Error(t, err)
+
+	require.NoError(t, store.DeleteCronSetting("telegram-morning"))
+	assert.Error(t, store.DeleteCronSetting("telegram-morning"))
+
+	defaults, err := store.GetAllCronSettings()
+	require.NoError(t, err)
+	require.Len(t, defaults, 2)
+	for _, setting := range defaults {
+		assert.Equal(t, setting.Name, setting.Job)
+		assert.Empty(t, setting.APIs)
+	}
+}
+
+func TestSQLiteStore_CronTimezone(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	setting, err := store.GetCronSetting("message")
+	require.NoError(t, err)
+	assert.Equal(t, models.DefaultTimezone, setting.Timezone)
+
+	require.NoError(t, store.UpdateCronSchedule("message", "0 9 * * *", "Europe/Kyiv", 0))
+	// A status change leaves the zone alone.
+	require.NoError(t, store.UpdateCronStatus("message", true))
+
+	setting, err = store.GetCronSetting("message")
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Kyiv", setting.Timezone)
+	assert.Equal(t, "0 9 * * *", setting.Schedule)
+	assert.Error(t, store.UpdateCronSchedule("missing", "0 9 * * *", "UTC", 0))
+
+	created, err := store.CreateCronSetting(&models.CreateCronRequest{Name: "x-evening", Schedule: "0 18 * * *"})
+	require.NoError(t, err)
+	assert.Equal(t, models.DefaultTimezone, created.Timezone)
+}
+
+func TestSQLiteStore_CronPause(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	setting, err := store.GetCronSetting("message")
+	require.NoError(t, err)
+	assert.Nil(t, setting.PausedUntil)
+
+	until := time.Date(2025, 6, 1, 18, 0, 0, 0, time.FixedZone("EEST", 3*60*60))
+	require.NoError(t, store.UpdateCronPause("message", &until, "incident"))
+
+	setting, err = store.GetCronSetting("message")
+	require.NoError(t, err)
+	require.NotNil(t, setting.PausedUntil)
+	assert.True(t, setting.PausedUntil.Equal(until))
+	assert.Equal(t, "incident", setting.PauseReason)
+
+	// Lifting the pause drops the reason with it.
+	require.NoError(t, store.UpdateCronPause("message", nil, "ignored"))
+	setting, err = store.GetCronSetting("message")
+	require.NoError(t, err)
+	assert.Nil(t, setting.PausedUntil)
+	assert.Empty(t, setting.PauseReason)
+
+	assert.Error(t, store.UpdateCronPause("missing", &until, ""))
+
+	// Switching a cron off lifts its pause with it.
+	require.NoError(t, store.UpdateCronPause("message", &until, "incident"))
+	require.NoError(t, store.UpdateCronStatus("message", false))
+	setting, err = store.GetCronSetting("message")
+	require.NoError(t, err)
+	assert.False(t, setting.IsActive)
+	assert.Nil(t, setting.PausedUntil)
+	assert.Empty(t, setting.PauseReason)
+	assert.Error(t, store.UpdateCronStatus("missing", true))
+}
+
+func TestSQLiteStore_CronJitter(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	setting, err := store.GetCronSetting("message")
+	require.NoError(t, err)
+	assert.Zero(t, setting.JitterSeconds)
+
+	require.NoError(t, store.UpdateCronSchedule("message", setting.Schedule, setting.Timezone, 1200))
+	setting, err = store.GetCronSetting("message")
+	require.NoError(t, err)
+	assert.Equal(t, 1200, setting.JitterSeconds)
+	assert.Error(t, store.UpdateCronSchedule("missing", setting.Schedule, setting.Timezone, 60))
+
+	created, err := store.CreateCronSetting(&models.CreateCronRequest{Name: "x-evening", Schedule: "0 18 * * *", JitterSeconds: 600})
+	require.NoError(t, err)
+	assert.Equal(t, 600, created.JitterSeconds)
+	setting, err = store.GetCronSetting("x-evening")
+	require.NoError(t, err)
+	assert.Equal(t, 600, setting.JitterSeconds)
+}
+
+func TestSQLiteStore_CronHistoryFireTimes(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	scheduledAt := time.Date(2025, 6, 1, 12, 12, 0, 0, time.UTC)
+	firedAt := scheduledAt.Add(-7 * time.Minute)
+	require.NoError(t, store.LogCronHistory(&models.CronHistory{
+		Name: "message", Success: models.CronStatusSuccess, Output: "Jittered", ScheduledAt: &scheduledAt, FiredAt: &firedAt,
+	}))
+	require.NoError(t, store.LogCronExecution("message", models.CronStatusSuccess, "Older writer"))
+
+	history, err := store.GetCronHistory("message", nil, 0, 10, "asc", nil, nil)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.NotNil(t, history[0].ScheduledAt)
+	require.NotNil(t, history[0].FiredAt)
+	assert.True(t, history[0].ScheduledAt.Equal(scheduledAt))
+	assert.True(t, history[0].FiredAt.Equal(firedAt))
+	assert.Nil(t, history[1].ScheduledAt)
+	assert.Nil(t, history[1].FiredAt)
+}
+
+func TestSQLiteStore_CronSettingsJobBackfilled(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "old.db")
+	db, err := sql.Open("sqlite", dbPath)
+	require.NoError(t, err)
+	_, err = db.Exec(`
+		CREATE TABLE cron_settings (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL UNIQUE,
+			schedule TEXT NOT NULL,
+			is_active INTEGER NOT NULL DEFAULT 1,
+			updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO cron_settings (name, schedule, is_active) VALUES ('message', '0 8 * * *', 1)`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	store, err := NewSQLiteStore(dbPath)
+	require.NoError(t, err)
+	defer store.Close()
+
+	setting, err := store.GetCronSetting("message")
+	require.NoError(t, err)
+	require.NotNil(t, setting)
+	assert.Equal(t, "0 8 * * *", setting.Schedule)
+	assert.Equal(t, models.JobMessage, setting.Job)
+}
+
 func TestSQLiteStore_InitializeDefaultSettings(t *testing.T) {
 	store := setupTestStore(t)
 	defer store.Close()
@@ -157,6 +335,65 @@ func TestSQLiteStore_LogCronExecutionDetails(t *testing.T) {
 	assert.Nil(t, history[1].Details)
 }
 
+func TestSQLiteStore_LogCronHistory_Run(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	require.NoError(t, store.LogCronHistory(&models.CronHistory{
+		Name: "collect", Success: 1, Output: "Manual run", RunID: "3f2a9c", Manual: true,
+	}))
+	require.NoError(t, store.LogCronExecution("collect", 1, "Scheduled run"))
+
+	history, err := store.GetCronHistory("collect", nil, 0, 10, "asc", nil, nil)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+
+	assert.Equal(t, "3f2a9c", history[0].RunID)
+	assert.True(t, history[0].Manual)
+	assert.Empty(t, history[1].RunID)
+	assert.False(t, history[1].Manual)
+}
+
+func TestSQLiteStore_LogCronExecution_Status(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	require.NoError(t, store.LogCronExecution("message", models.CronStatusSkipped, "Skipped: message run 1 was still in progress"))
+	require.NoError(t, store.LogCronExecution("message", models.CronStatusBlackout, "Skipped: blackout window 'Christmas'"))
+	assert.Error(t, store.LogCronExecution("message", 5, "Unknown"))
+	assert.Error(t, store.LogCronExecution("message", -1, "Unknown"))
+
+	skipped := models.CronStatusSkipped
+	count, err := store.GetCronHistoryCount("message", &skipped, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestSQLiteStore_GetLastCronRun(t *testing.T) {
+	store := setupTestStore(t)
+	defer store.Close()
+
+	run, err := store.GetLastCronRun("collect")
+	require.NoError(t, err)
+	assert.Nil(t, run)
+
+	require.NoError(t, store.LogCronExecution("collect", models.CronStatusSuccess, "First"))
+	require.NoError(t, store.LogCronHistory(&models.CronHistory{
+		Name: "collect", Success: models.CronStatusFailure, Output: "Second", RunID: "b7", Manual: true,
+	}))
+	require.NoError(t, store.LogCronExecution("collect", models.CronStatusSkipped, "Skipped"))
+	require.NoError(t, store.LogCronExecution("collect", models.CronStatusBlackout, "Blackout"))
+	require.NoError(t, store.LogCronExecution("message", models.CronStatusSuccess, "Other job"))
+
+	run, err = store.GetLastCronRun("collect")
+	require.NoError(t, err)
+	require.NotNil(t, run)
+	assert.Equal(t, models.CronStatusFailure, run.Status)
+	assert.Equal(t, "b7", run.RunID)
+	assert.True(t, run.Manual)
+	assert.False(t, run.Timestamp.IsZero())
+}
+
 func TestSQLiteStore_LogCronExecution_EmptyName(t *testing.T) {
 	store := setupTestStore(t)
 	defer store.Close()
@@ -341,7 +578,7 @@ func TestSQLiteStore_UpdatePromptSettings(t *testing.T) {
 		Temperature: &newTemp,
 	}
 
-	err := store.UpdatePromptSettings(updateReq)
+	err := store.UpdatePromptSettings(updateReq, "api_token")
 	require.NoError(t, err)
 
 	settings, err := store.GetPromptSettings()
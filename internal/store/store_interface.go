@@ -11,17 +11,60 @@ type StoreInterface interface {
 	GetCronSetting(name string) (*models.CronSetting, error)
 	GetAllCronSettings() ([]models.CronSetting, error)
 	UpdateCronSetting(name string, schedule string, isActive bool) (*models.CronSetting, error)
+	CreateCronSetting(req *models.CreateCronRequest) (*models.CronSetting, error)
+	UpdateCronAPIs(name string, apis []string) (*models.CronSetting, error)
+	UpdateCronSchedule(name, schedule, timezone string, jitterSeconds int) error
+	UpdateCronStatus(name string, isActive bool) error
+	UpdateCronPause(name string, until *time.Time, reason string) error
+	DeleteCronSetting(name string) error
 	LogCronExecution(name string, status int, output string) error
 	LogCronExecutionDetails(name string, status int, output string, details *models.MessageRunDetails) error
+	LogCronHistory(entry *models.CronHistory) error
+	GetLastCronRun(name string) (*models.CronRunSummary, error)
 	GetCronHistoryCount(name string, status *int, startDate, endDate *time.Time) (int, error)
 	GetCronHistory(name string, status *int, offset, limit int, sortOrder string, startDate, endDate *time.Time) ([]models.CronHistory, error)
 	GetCollectSettings() (*CollectSettings, error)
 	UpdateCollectSettings(settings *CollectSettings) error
+	GetCollectProfiles() ([]models.CollectProfile, error)
+	GetCollectProfile(name string) (*models.CollectProfile, error)
+	CreateCollectProfile(req *models.CreateCollectProfileRequest) (*models.CollectProfile, error)
+	UpdateCollectProfile(name string, req *models.UpdateCollectProfileRequest) (*models.CollectProfile, error)
+	DeleteCollectProfile(name string) error
+	GetPromptVersions() ([]models.PromptVersion, error)
+	GetPromptVersion(name string) (*models.PromptVersion, error)
+	CreatePromptVersion(req *models.CreatePromptVersionRequest) (*models.PromptVersion, error)
+	DeletePromptVersion(name string) error
 	GetPromptSettings() (*models.PromptSettings, error)
-	UpdatePromptSettings(settings *models.UpdatePromptSettingsRequest) error
+	UpdatePromptSettings(settings *models.UpdatePromptSettingsRequest, actor string) error
+	GetPromptRevisionCount() (int, error)
+	GetPromptRevisions(offset, limit int) ([]models.PromptRevision, error)
+	GetPromptRevision(rev int64) (*models.PromptRevision, error)
+	RollbackPromptSettings(rev int64, actor string) (*models.PromptRevision, error)
+	RecordAuditEntry(entry *models.AuditEntry) error
+	GetAuditLogCount(actor, endpoint string, startDate, endDate *time.Time) (int, error)
+	GetAuditLog(actor, endpoint string, offset, limit int, startDate, endDate *time.Time) ([]models.AuditEntry, error)
+	GetAPITokens() ([]models.APIToken, error)
+	GetAPIToken(name string) (*models.APIToken, error)
+	GetAPITokenByHash(hash string) (*models.APIToken, error)
+	CreateAPIToken(req *models.CreateAPITokenRequest, hash string) (*models.APIToken, error)
+	DeleteAPIToken(name string) error
 	GetAPIConfig(name string) (*models.APIConfigModel, error)
 	GetAllAPIConfigs() ([]models.APIConfigModel, error)
 	CreateAPIConfig(config *models.CreateAPIConfigRequest) (*models.APIConfigModel, error)
 	UpdateAPIConfig(name string, config *models.UpdateAPIConfigRequest) (*models.APIConfigModel, error)
 	DeleteAPIConfig(name string) error
+	RecordDeliveryAttempt(attempt *models.DeliveryAttempt) error
+	GetDelivery(url, apiName string) (*models.Delivery, error)
+	GetDeliveryCount(url, apiName, status string) (int, error)
+	GetDeliveries(url, apiName, status string, offset, limit int) ([]models.Delivery, error)
+	ScheduleDeliveryRetry(url, apiName string, at time.Time) error
+	AbandonDelivery(url, apiName, reason string) error
+	GetDueDeliveryRetries(now time.Time, limit int) ([]models.Delivery, error)
+	GetBlackoutWindows() ([]models.BlackoutWindow, error)
+	GetBlackoutWindow(id int64) (*models.BlackoutWindow, error)
+	CreateBlackoutWindow(req *models.BlackoutWindowRequest) (*models.BlackoutWindow, error)
+	UpdateBlackoutWindow(id int64, req *models.BlackoutWindowRequest) (*models.BlackoutWindow, error)
+	DeleteBlackoutWindow(id int64) error
+	GetImageSettings() (*models.ImageSettings, error)
+	UpdateImageSettings(settings *models.ImageOverrides) (*models.ImageSettings, error)
 }
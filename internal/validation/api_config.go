@@ -1,11 +1,13 @@
 package validation
 
 import (
+	"content-maestro/internal/api"
 	"content-maestro/internal/models"
 	"encoding/json"
 	"fmt"
 	"net/url"
 	"regexp"
+	"strings"
 )
 
 var validMethods = map[string]bool{
@@ -40,6 +42,76 @@ func validateDefaultJSONBody(defaultJSONBody string) error {
 	return nil
 }
 
+// headerNamePattern is the token syntax RFC 9110 allows in a field name.
+var headerNamePattern = regexp.MustCompile("^[!#$%&'*+.^_`|~0-9A-Za-z-]+$")
+
+func validateHeaders(headers string) error {
+	if headers == "" {
+		return nil
+	}
+
+	var headerMap map[string]string
+	if err := json.Unmarshal([]byte(headers), &headerMap); err != nil {
+		return fmt.Errorf("headers must be valid JSON representing an object with string values: %w", err)
+	}
+
+	for name, value := range headerMap {
+		if !headerNamePattern.MatchString(name) {
+			return fmt.Errorf("headers: invalid header name %q", name)
+		}
+		// The request body sets its own Content-Type, and a multipart body is
+		// unreadable without the boundary it carries.
+		if strings.EqualFold(name, "Content-Type") {
+			return fmt.Errorf("headers: Content-Type is set from content_type and cannot be overridden")
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("headers: value of %s must not contain line breaks", name)
+		}
+	}
+
+	return nil
+}
+
+func validateSuccessCodes(codes []int) error {
+	for _, code := range codes {
+		if code < 100 || code > 599 {
+			return fmt.Errorf("success_codes must contain valid HTTP status codes (100-599), got %d", code)
+		}
+	}
+	return nil
+}
+
+// validateResponseFieldPath checks an optional JSON path into a connector's
+// response.
+func validateResponseFieldPath(name, path string) error {
+	if path == "" {
+		return nil
+	}
+	if err := api.ValidateJSONPath(path); err != nil {
+		return fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return nil
+}
+
+// maxRetryMaxAttempts bounds the automatic retry budget. With exponential
+// backoff a larger budget would keep retrying for weeks, long after the post
+// stopped being relevant.
+const maxRetryMaxAttempts = 10
+
+func validateRetryMaxAttempts(attempts int) error {
+	if attempts < 0 || attempts > maxRetryMaxAttempts {
+		return fmt.Errorf("retry_max_attempts must be between 0 and %d", maxRetryMaxAttempts)
+	}
+	return nil
+}
+
+func validateBodyTemplate(bodyTemplate string) error {
+	if err := api.ValidateBodyTemplate(bodyTemplate); err != nil {
+		return fmt.Errorf("invalid body_template: %w", err)
+	}
+	return nil
+}
+
 func ValidateAPIConfig(config *models.CreateAPIConfigRequest) error {
 	if config.Name == "" {
 		return fmt.Errorf("name cannot be empty")
@@ -82,6 +154,46 @@ func ValidateAPIConfig(config *models.CreateAPIConfigRequest) error {
 		return err
 	}
 
+	if err := validateHeaders(config.Headers); err != nil {
+		return err
+	}
+
+	if err := validateSuccessCodes(config.SuccessCodes); err != nil {
+		return err
+	}
+
+	if err := validateResponseFieldPath("success_field", config.SuccessField); err != nil {
+		return err
+	}
+
+	if config.SuccessValue != "" && config.SuccessField == "" {
+		return fmt.Errorf("success_value requires success_field")
+	}
+
+	if err := validateResponseFieldPath("post_id_field", config.PostIDField); err != nil {
+		return err
+	}
+
+	if err := validateResponseFieldPath("post_url_field", config.PostURLField); err != nil {
+		return err
+	}
+
+	if config.RetryMaxAttempts != nil {
+		if err := validateRetryMaxAttempts(*config.RetryMaxAttempts); err != nil {
+			return err
+		}
+	}
+
+	if err := validateBodyTemplate(config.BodyTemplate); err != nil {
+		return err
+	}
+
+	if config.ImageSettings != nil {
+		if err := validateConnectorImageSettings(config.ImageSettings); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -121,5 +233,60 @@ func ValidateAPIConfigUpdate(config *models.UpdateAPIConfigRequest) error {
 		}
 	}
 
+	if config.Headers != nil {
+		if err := validateHeaders(*config.Headers); err != nil {
+			return err
+		}
+	}
+
+	if config.SuccessCodes != nil {
+		if err := validateSuccessCodes(*config.SuccessCodes); err != nil {
+			return err
+		}
+	}
+
+	if config.SuccessField != nil {
+		if err := validateResponseFieldPath("success_field", *config.SuccessField); err != nil {
+			return err
+		}
+	}
+
+	if config.PostIDField != nil {
+		if err := validateResponseFieldPath("post_id_field", *config.PostIDField); err != nil {
+			return err
+		}
+	}
+
+	if config.PostURLField != nil {
+		if err := validateResponseFieldPath("post_url_field", *config.PostURLField); err != nil {
+			return err
+		}
+	}
+
+	if config.RetryMaxAttempts != nil {
+		if err := validateRetryMaxAttempts(*config.RetryMaxAttempts); err != nil {
+			return err
+		}
+	}
+
+	if config.BodyTemplate != nil {
+		if err := validateBodyTemplate(*config.BodyTemplate); err != nil {
+			return err
+		}
+	}
+
+	if config.ImageSettings != nil {
+		if err := validateConnectorImageSettings(config.ImageSettings); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateConnectorImageSettings(settings *models.ImageOverrides) error {
+	if err := ValidateImageOverrides(settings); err != nil {
+		return fmt.Errorf("invalid image_settings: %w", err)
+	}
 	return nil
 }
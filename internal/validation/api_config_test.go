@@ -71,6 +71,34 @@ func TestValidateDefaultJSONBody(t *testing.T) {
 	}
 }
 
+func TestValidateHeaders(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		shouldError bool
+	}{
+		{name: "empty string is valid", input: ""},
+		{name: "valid headers", input: `{"X-Source": "maestro", "Idempotency-Key": "{env.KEY}"}`},
+		{name: "invalid JSON", input: `{invalid}`, shouldError: true},
+		{name: "non-string value", input: `{"X-Retries": 3}`, shouldError: true},
+		{name: "invalid header name", input: `{"X Source": "maestro"}`, shouldError: true},
+		{name: "content type override", input: `{"content-type": "text/plain"}`, shouldError: true},
+		{name: "line break in value", input: `{"X-Source": "a\r\nX-Injected: b"}`, shouldError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateHeaders(tt.input)
+			if tt.shouldError && err == nil {
+				t.Errorf("expected error but got nil")
+			}
+			if !tt.shouldError && err != nil {
+				t.Errorf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
 func TestValidateAPIConfig(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -136,6 +164,74 @@ func TestValidateAPIConfig(t *testing.T) {
 			shouldError: true,
 			errorMsg:    "default_json_body must be valid JSON",
 		},
+		{
+			name: "invalid config with body_template not rendering an object",
+			config: &models.CreateAPIConfigRequest{
+				Name:         "discord",
+				URL:          "https://example.com",
+				Method:       "POST",
+				ContentType:  "json",
+				Timeout:      30,
+				SuccessCode:  204,
+				BodyTemplate: `[{{json .Item.URL}}]`,
+			},
+			shouldError: true,
+			errorMsg:    "invalid body_template",
+		},
+		{
+			name: "valid config with success rule",
+			config: &models.CreateAPIConfigRequest{
+				Name:         "telegram",
+				URL:          "https://example.com",
+				Method:       "POST",
+				ContentType:  "json",
+				Timeout:      30,
+				SuccessCode:  200,
+				SuccessCodes: []int{200, 201},
+				SuccessField: "$.result.ok",
+				SuccessValue: "true",
+			},
+			shouldError: false,
+		},
+		{
+			name: "invalid config with out of range success_codes",
+			config: &models.CreateAPIConfigRequest{
+				Name:         "telegram",
+				URL:          "https://example.com",
+				Method:       "POST",
+				ContentType:  "json",
+				Timeout:      30,
+				SuccessCode:  200,
+				SuccessCodes: []int{200, 2001},
+			},
+			shouldError: true,
+		},
+		{
+			name: "invalid config with malformed success_field",
+			config: &models.CreateAPIConfigRequest{
+				Name:         "telegram",
+				URL:          "https://example.com",
+				Method:       "POST",
+				ContentType:  "json",
+				Timeout:      30,
+				SuccessCode:  200,
+				SuccessField: "result..ok",
+			},
+			shouldError: true,
+		},
+		{
+			name: "invalid config with success_value but no success_field",
+			config: &models.CreateAPIConfigRequest{
+				Name:         "telegram",
+				URL:          "https://example.com",
+				Method:       "POST",
+				ContentType:  "json",
+				Timeout:      30,
+				SuccessCode:  200,
+				SuccessValue: "ok",
+			},
+			shouldError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -155,6 +251,9 @@ func TestValidateAPIConfigUpdate(t *testing.T) {
 	validJSON := `{"key": "value"}`
 	invalidJSON := `{invalid}`
 	nonStringJSON := `{"key": 123}`
+	validTemplate := `{"content": {{json .Item.Text}}}`
+	unescapedTemplate := `{"content": "{{.Item.Text}}"}`
+	emptyTemplate := ""
 
 	tests := []struct {
 		name        string
@@ -189,6 +288,27 @@ func TestValidateAPIConfigUpdate(t *testing.T) {
 			},
 			shouldError: true,
 		},
+		{
+			name: "valid update with body_template",
+			config: &models.UpdateAPIConfigRequest{
+				BodyTemplate: &validTemplate,
+			},
+			shouldError: false,
+		},
+		{
+			name: "invalid update with unescaped body_template",
+			config: &models.UpdateAPIConfigRequest{
+				BodyTemplate: &unescapedTemplate,
+			},
+			shouldError: true,
+		},
+		{
+			name: "valid update clearing body_template",
+			config: &models.UpdateAPIConfigRequest{
+				BodyTemplate: &emptyTemplate,
+			},
+			shouldError: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,38 @@
+package validation
+
+import (
+	"content-maestro/internal/models"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ValidateAPITokenCreate checks a new named token. The name is what the audit
+// log and request logs record the token's requests under, so it cannot be the
+// one API_TOKEN is recorded under.
+func ValidateAPITokenCreate(req *models.CreateAPITokenRequest) error {
+	if req.Name == "" {
+		return fmt.Errorf("name cannot be empty")
+	}
+	if !cronNamePattern.MatchString(req.Name) {
+		return fmt.Errorf("name must contain only alphanumeric characters, hyphens, and underscores")
+	}
+	if req.Name == models.APITokenActor {
+		return fmt.Errorf("name '%s' is reserved", req.Name)
+	}
+
+	if len(req.Scopes) == 0 {
+		return fmt.Errorf("scopes cannot be empty")
+	}
+	seen := make(map[string]bool, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		if !slices.Contains(models.TokenScopes, scope) {
+			return fmt.Errorf("invalid scope '%s': must be one of %s", scope, strings.Join(models.TokenScopes, ", "))
+		}
+		if seen[scope] {
+			return fmt.Errorf("scope '%s' is listed more than once", scope)
+		}
+		seen[scope] = true
+	}
+	return nil
+}
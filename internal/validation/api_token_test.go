@@ -0,0 +1,36 @@
+package validation
+
+import (
+	"content-maestro/internal/models"
+	"testing"
+)
+
+func TestValidateAPITokenCreate(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       models.CreateAPITokenRequest
+		shouldError bool
+	}{
+		{name: "single scope", input: models.CreateAPITokenRequest{Name: "dashboard", Scopes: []string{"read-only"}}},
+		{name: "several scopes", input: models.CreateAPITokenRequest{Name: "ops-bot", Scopes: []string{"crons", "retry"}}},
+		{name: "empty name", input: models.CreateAPITokenRequest{Scopes: []string{"crons"}}, shouldError: true},
+		{name: "name with spaces", input: models.CreateAPITokenRequest{Name: "ops bot", Scopes: []string{"crons"}}, shouldError: true},
+		{name: "reserved name", input: models.CreateAPITokenRequest{Name: "api_token", Scopes: []string{"crons"}}, shouldError: true},
+		{name: "no scopes", input: models.CreateAPITokenRequest{Name: "dashboard"}, shouldError: true},
+		{name: "unknown scope", input: models.CreateAPITokenRequest{Name: "dashboard", Scopes: []string{"write"}}, shouldError: true},
+		{name: "admin scope", input: models.CreateAPITokenRequest{Name: "dashboard", Scopes: []string{"admin"}}, shouldError: true},
+		{name: "duplicate scope", input: models.CreateAPITokenRequest{Name: "dashboard", Scopes: []string{"crons", "crons"}}, shouldError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAPITokenCreate(&tt.input)
+			if tt.shouldError && err == nil {
+				t.Errorf("expected error but got nil")
+			}
+			if !tt.shouldError && err != nil {
+				t.Errorf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package validation
+
+import (
+	"content-maestro/internal/models"
+	"fmt"
+	"time"
+)
+
+var blackoutWeekdays = map[string]bool{
+	"mon": true, "tue": true, "wed": true, "thu": true, "fri": true, "sat": true, "sun": true,
+}
+
+// ValidateBlackoutWindow checks a blackout window for its kind: a range takes
+// two dates or two RFC 3339 times, a weekly window a list of weekdays and a
+// yearly window two month-days such as "12-24".
+func ValidateBlackoutWindow(req *models.BlackoutWindowRequest) error {
+	if req.Name == "" {
+		return fmt.Errorf("name cannot be empty")
+	}
+
+	if req.Timezone != "" {
+		if err := ValidateTimezone(req.Timezone); err != nil {
+			return err
+		}
+	}
+
+	switch req.Kind {
+	case models.BlackoutKindRange:
+		return validateBlackoutRange(req.Start, req.End)
+	case models.BlackoutKindWeekly:
+		if len(req.Weekdays) == 0 {
+			return fmt.Errorf("weekly blackout needs at least one weekday")
+		}
+		seen := make(map[string]bool, len(req.Weekdays))
+		for _, day := range req.Weekdays {
+			if !blackoutWeekdays[day] {
+				return fmt.Errorf("invalid weekday: %q (use mon, tue, wed, thu, fri, sat or sun)", day)
+			}
+			if seen[day] {
+				return fmt.Errorf("weekday '%s' is listed more than once", day)
+			}
+			seen[day] = true
+		}
+		return nil
+	case models.BlackoutKindYearly:
+		for _, value := range []string{req.Start, req.End} {
+			day, err := time.Parse(models.BlackoutMonthDayLayout, value)
+			// Formatting back rejects "1-5": yearly windows are compared as text.
+			if err != nil || day.Format(models.BlackoutMonthDayLayout) != value {
+				return fmt.Errorf("invalid month-day: %q (use MM-DD)", value)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("kind must be %s, %s or %s", models.BlackoutKindRange, models.BlackoutKindWeekly, models.BlackoutKindYearly)
+	}
+}
+
+func validateBlackoutRange(start, end string) error {
+	layout := models.BlackoutDateLayout
+	startTime, err := time.Parse(layout, start)
+	if err != nil {
+		layout = time.RFC3339
+		if startTime, err = time.Parse(layout, start); err != nil {
+			return fmt.Errorf("invalid start: %q (use YYYY-MM-DD or RFC 3339)", start)
+		}
+	}
+
+	endTime, err := time.Parse(layout, end)
+	if err != nil {
+		return fmt.Errorf("invalid end: %q (use the same format as start)", end)
+	}
+
+	if layout == models.BlackoutDateLayout && endTime.Before(startTime) {
+		return fmt.Errorf("end cannot be before start")
+	}
+	if layout == time.RFC3339 && !endTime.After(startTime) {
+		return fmt.Errorf("end must be after start")
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package validation
+
+import (
+	"content-maestro/internal/models"
+	"testing"
+)
+
+func TestValidateBlackoutWindow(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       models.BlackoutWindowRequest
+		shouldError bool
+	}{
+		{name: "date range", input: models.BlackoutWindowRequest{Name: "Christmas", Kind: "range", Start: "2025-12-24", End: "2025-12-26"}},
+		{name: "single day", input: models.BlackoutWindowRequest{Name: "x", Kind: "range", Start: "2025-12-24", End: "2025-12-24"}},
+		{name: "time range", input: models.BlackoutWindowRequest{Name: "incident", Kind: "range", Start: "2025-06-01T10:00:00Z", End: "2025-06-01T14:00:00+02:00", Timezone: "Europe/Kyiv"}},
+		{name: "weekly", input: models.BlackoutWindowRequest{Name: "weekend", Kind: "weekly", Weekdays: []string{"sat", "sun"}}},
+		{name: "yearly wrapping", input: models.BlackoutWindowRequest{Name: "new year", Kind: "yearly", Start: "12-31", End: "01-01"}},
+		{name: "leap day", input: models.BlackoutWindowRequest{Name: "x", Kind: "yearly", Start: "02-29", End: "02-29"}},
+		{name: "empty name", input: models.BlackoutWindowRequest{Kind: "weekly", Weekdays: []string{"sun"}}, shouldError: true},
+		{name: "unknown kind", input: models.BlackoutWindowRequest{Name: "x", Kind: "monthly"}, shouldError: true},
+		{name: "end before start", input: models.BlackoutWindowRequest{Name: "x", Kind: "range", Start: "2025-12-26", End: "2025-12-24"}, shouldError: true},
+		{name: "empty time range", input: models.BlackoutWindowRequest{Name: "x", Kind: "range", Start: "2025-06-01T10:00:00Z", End: "2025-06-01T10:00:00Z"}, shouldError: true},
+		{name: "mixed range formats", input: models.BlackoutWindowRequest{Name: "x", Kind: "range", Start: "2025-06-01", End: "2025-06-02T10:00:00Z"}, shouldError: true},
+		{name: "no weekdays", input: models.BlackoutWindowRequest{Name: "x", Kind: "weekly"}, shouldError: true},
+		{name: "unknown weekday", input: models.BlackoutWindowRequest{Name: "x", Kind: "weekly", Weekdays: []string{"Saturday"}}, shouldError: true},
+		{name: "duplicate weekday", input: models.BlackoutWindowRequest{Name: "x", Kind: "weekly", Weekdays: []string{"sun", "sun"}}, shouldError: true},
+		{name: "unpadded month-day", input: models.BlackoutWindowRequest{Name: "x", Kind: "yearly", Start: "1-5", End: "01-06"}, shouldError: true},
+		{name: "impossible month-day", input: models.BlackoutWindowRequest{Name: "x", Kind: "yearly", Start: "02-30", End: "03-01"}, shouldError: true},
+		{name: "unknown timezone", input: models.BlackoutWindowRequest{Name: "x", Kind: "weekly", Weekdays: []string{"sun"}, Timezone: "Mars/Base"}, shouldError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBlackoutWindow(&tt.input)
+			if tt.shouldError && err == nil {
+				t.Errorf("expected error but got nil")
+			}
+			if !tt.shouldError && err != nil {
+				t.Errorf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
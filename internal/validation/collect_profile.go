@@ -0,0 +1,64 @@
+package validation
+
+import (
+	"content-maestro/internal/models"
+	"fmt"
+)
+
+// ValidateCollectProfileCreate checks a new collect profile and the cron that
+// runs it. The profile is named after its cron, so the name follows cron names.
+func ValidateCollectProfileCreate(req *models.CreateCollectProfileRequest) error {
+	if req.Name == "" {
+		return fmt.Errorf("name cannot be empty")
+	}
+	if !cronNamePattern.MatchString(req.Name) {
+		return fmt.Errorf("name must contain only alphanumeric characters, hyphens, and underscores")
+	}
+
+	if err := ValidateCronExpression(req.Schedule); err != nil {
+		return err
+	}
+
+	if req.Timezone != "" {
+		if err := ValidateTimezone(req.Timezone); err != nil {
+			return err
+		}
+	}
+
+	if err := ValidateCronJitter(req.Schedule, req.JitterSeconds); err != nil {
+		return err
+	}
+
+	return ValidateCollectProfileUpdate(&models.UpdateCollectProfileRequest{
+		MaxRepos:           &req.MaxRepos,
+		Since:              &req.Since,
+		SpokenLanguageCode: &req.SpokenLanguageCode,
+		Prompt:             req.Prompt,
+		PromptVersions:     &req.PromptVersions,
+	})
+}
+
+// ValidateCollectProfileUpdate checks the fields a profile update sets. They
+// follow the rules of the collect settings.
+func ValidateCollectProfileUpdate(req *models.UpdateCollectProfileRequest) error {
+	if req.MaxRepos != nil && *req.MaxRepos < 1 {
+		return fmt.Errorf("max_repos must be greater than 0")
+	}
+	if req.Since != nil && *req.Since == "" {
+		return fmt.Errorf("since cannot be empty")
+	}
+	if req.SpokenLanguageCode != nil && *req.SpokenLanguageCode == "" {
+		return fmt.Errorf("spoken_language_code cannot be empty")
+	}
+	if req.Prompt != nil {
+		if err := ValidatePromptSettings(req.Prompt); err != nil {
+			return fmt.Errorf("prompt: %w", err)
+		}
+	}
+	if req.PromptVersions != nil {
+		if err := ValidatePromptVersionWeights(*req.PromptVersions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package validation
+
+import (
+	"content-maestro/internal/models"
+	"testing"
+)
+
+func TestValidateCollectProfileCreate(t *testing.T) {
+	valid := func() models.CreateCollectProfileRequest {
+		return models.CreateCollectProfileRequest{
+			Name: "go-weekly", Schedule: "0 9 * * 1", MaxRepos: 5, Since: "weekly", SpokenLanguageCode: "en", Language: "Go",
+		}
+	}
+	with := func(change func(*models.CreateCollectProfileRequest)) models.CreateCollectProfileRequest {
+		req := valid()
+		change(&req)
+		return req
+	}
+	provider := "openai"
+	badProvider := "gpt"
+	temperature := 0.5
+	empty := ""
+
+	tests := []struct {
+		name        string
+		input       models.CreateCollectProfileRequest
+		shouldError bool
+	}{
+		{name: "valid", input: valid()},
+		{name: "timezone and jitter", input: with(func(r *models.CreateCollectProfileRequest) { r.Timezone = "Europe/Kyiv"; r.JitterSeconds = 300 })},
+		{name: "prompt override", input: with(func(r *models.CreateCollectProfileRequest) {
+			r.Prompt = &models.UpdatePromptSettingsRequest{LlmProvider: &provider, Temperature: &temperature}
+		})},
+		{name: "empty name", input: with(func(r *models.CreateCollectProfileRequest) { r.Name = "" }), shouldError: true},
+		{name: "name with spaces", input: with(func(r *models.CreateCollectProfileRequest) { r.Name = "Go weekly" }), shouldError: true},
+		{name: "invalid schedule", input: with(func(r *models.CreateCollectProfileRequest) { r.Schedule = "every monday" }), shouldError: true},
+		{name: "unknown timezone", input: with(func(r *models.CreateCollectProfileRequest) { r.Timezone = "Mars/Base" }), shouldError: true},
+		{name: "negative jitter", input: with(func(r *models.CreateCollectProfileRequest) { r.JitterSeconds = -1 }), shouldError: true},
+		{name: "no max_repos", input: with(func(r *models.CreateCollectProfileRequest) { r.MaxRepos = 0 }), shouldError: true},
+		{name: "empty since", input: with(func(r *models.CreateCollectProfileRequest) { r.Since = "" }), shouldError: true},
+		{name: "empty spoken language", input: with(func(r *models.CreateCollectProfileRequest) { r.SpokenLanguageCode = "" }), shouldError: true},
+		{name: "invalid prompt provider", input: with(func(r *models.CreateCollectProfileRequest) {
+			r.Prompt = &models.UpdatePromptSettingsRequest{LlmProvider: &badProvider}
+		}), shouldError: true},
+		{name: "empty prompt content", input: with(func(r *models.CreateCollectProfileRequest) {
+			r.Prompt = &models.UpdatePromptSettingsRequest{Content: &empty}
+		}), shouldError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCollectProfileCreate(&tt.input)
+			if tt.shouldError && err == nil {
+				t.Errorf("expected error but got nil")
+			}
+			if !tt.shouldError && err != nil {
+				t.Errorf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateCollectProfileUpdate(t *testing.T) {
+	zero := 0
+	ten := 10
+	empty := ""
+	model := "gpt-4o"
+
+	tests := []struct {
+		name        string
+		input       models.UpdateCollectProfileRequest
+		shouldError bool
+	}{
+		{name: "nothing set", input: models.UpdateCollectProfileRequest{}},
+		{name: "max_repos", input: models.UpdateCollectProfileRequest{MaxRepos: &ten}},
+		{name: "prompt override", input: models.UpdateCollectProfileRequest{Prompt: &models.UpdatePromptSettingsRequest{Model: &model}}},
+		{name: "removing the prompt override", input: models.UpdateCollectProfileRequest{Prompt: &models.UpdatePromptSettingsRequest{}}},
+		{name: "zero max_repos", input: models.UpdateCollectProfileRequest{MaxRepos: &zero}, shouldError: true},
+		{name: "empty since", input: models.UpdateCollectProfileRequest{Since: &empty}, shouldError: true},
+		{name: "empty prompt model", input: models.UpdateCollectProfileRequest{Prompt: &models.UpdatePromptSettingsRequest{Model: &empty}}, shouldError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCollectProfileUpdate(&tt.input)
+			if tt.shouldError && err == nil {
+				t.Errorf("expected error but got nil")
+			}
+			if !tt.shouldError && err != nil {
+				t.Errorf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
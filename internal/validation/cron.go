@@ -1,10 +1,14 @@
 package validation
 
 import (
+	"content-maestro/internal/models"
 	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
 )
 
 func ValidateCronExpression(expr string) error {
@@ -107,3 +111,108 @@ func isInRange(field string, min, max int) bool {
 	}
 	return num >= min && num <= max
 }
+
+var cronNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// ValidateCronCreate checks a new message schedule. Whether the named
+// connectors exist is checked against the store by the caller.
+func ValidateCronCreate(req *models.CreateCronRequest) error {
+	if req.Name == "" {
+		return fmt.Errorf("name cannot be empty")
+	}
+	if !cronNamePattern.MatchString(req.Name) {
+		return fmt.Errorf("name must contain only alphanumeric characters, hyphens, and underscores")
+	}
+
+	if err := ValidateCronExpression(req.Schedule); err != nil {
+		return err
+	}
+
+	if req.Timezone != "" {
+		if err := ValidateTimezone(req.Timezone); err != nil {
+			return err
+		}
+	}
+
+	if err := ValidateCronJitter(req.Schedule, req.JitterSeconds); err != nil {
+		return err
+	}
+
+	return ValidateCronAPIs(req.APIs)
+}
+
+// ValidateTimezone accepts IANA zone names such as "Europe/Kyiv" or "UTC".
+// "Local" is refused: it would follow whatever zone the server happens to run in.
+func ValidateTimezone(timezone string) error {
+	if timezone == "" || timezone == "Local" {
+		return fmt.Errorf("timezone must be an IANA zone name such as Europe/Kyiv")
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return fmt.Errorf("unknown timezone: %s", timezone)
+	}
+	return nil
+}
+
+// ValidateCronAPIs checks the connector list of a message schedule.
+func ValidateCronAPIs(apis []string) error {
+	seen := make(map[string]bool, len(apis))
+	for _, name := range apis {
+		if !cronNamePattern.MatchString(name) {
+			return fmt.Errorf("invalid api name: %q", name)
+		}
+		if seen[name] {
+			return fmt.Errorf("api '%s' is listed more than once", name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// maxPauseReasonLength keeps a pause reason to a note rather than a document.
+const maxPauseReasonLength = 500
+
+// ValidateCronPause checks that a pause ends in the future.
+func ValidateCronPause(req *models.PauseCronRequest, now time.Time) error {
+	if req.Until.IsZero() {
+		return fmt.Errorf("until is required")
+	}
+	if !req.Until.After(now) {
+		return fmt.Errorf("until must be in the future")
+	}
+	if len(req.Reason) > maxPauseReasonLength {
+		return fmt.Errorf("reason cannot be longer than %d characters", maxPauseReasonLength)
+	}
+	return nil
+}
+
+// jitterSampleRuns is how many upcoming runs ValidateCronJitter measures the
+// gaps of. It covers at least a week of any schedule that runs daily or less.
+const jitterSampleRuns = 100
+
+// ValidateCronJitter checks a jitter window against the schedule it applies to.
+// The window must stay under half the shortest gap between two runs, so
+// jittered runs can neither swap places nor overlap.
+func ValidateCronJitter(schedule string, seconds int) error {
+	if seconds < 0 {
+		return fmt.Errorf("jitter_seconds cannot be negative")
+	}
+	if seconds == 0 {
+		return nil
+	}
+
+	parsed, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return fmt.Errorf("invalid schedule: %w", err)
+	}
+
+	window := time.Duration(seconds) * time.Second
+	previous := parsed.Next(time.Now().UTC())
+	for i := 0; i < jitterSampleRuns; i++ {
+		next := parsed.Next(previous)
+		if gap := next.Sub(previous); 2*window >= gap {
+			return fmt.Errorf("jitter_seconds must be less than half the time between runs (%s)", gap)
+		}
+		previous = next
+	}
+	return nil
+}
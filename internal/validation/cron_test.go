@@ -0,0 +1,111 @@
+package validation
+
+import (
+	"content-maestro/internal/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateCronCreate(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       models.CreateCronRequest
+		shouldError bool
+	}{
+		{name: "valid schedule", input: models.CreateCronRequest{Name: "telegram-morning", Schedule: "0 9 * * *", APIs: []string{"telegram"}}},
+		{name: "no connectors", input: models.CreateCronRequest{Name: "all", Schedule: "0 9 * * *"}},
+		{name: "empty name", input: models.CreateCronRequest{Schedule: "0 9 * * *"}, shouldError: true},
+		{name: "name with slash", input: models.CreateCronRequest{Name: "a/b", Schedule: "0 9 * * *"}, shouldError: true},
+		{name: "invalid schedule", input: models.CreateCronRequest{Name: "x", Schedule: "0 25 * * *"}, shouldError: true},
+		{name: "blank connector", input: models.CreateCronRequest{Name: "x", Schedule: "0 9 * * *", APIs: []string{""}}, shouldError: true},
+		{name: "with timezone", input: models.CreateCronRequest{Name: "x", Schedule: "0 9 * * *", Timezone: "Europe/Kyiv"}},
+		{name: "unknown timezone", input: models.CreateCronRequest{Name: "x", Schedule: "0 9 * * *", Timezone: "Europe/Atlantis"}, shouldError: true},
+		{name: "with jitter", input: models.CreateCronRequest{Name: "x", Schedule: "0 9 * * *", JitterSeconds: 1200}},
+		{name: "jitter wider than schedule", input: models.CreateCronRequest{Name: "x", Schedule: "*/5 * * * *", JitterSeconds: 300}, shouldError: true},
+		{name: "duplicate connector", input: models.CreateCronRequest{Name: "x", Schedule: "0 9 * * *", APIs: []string{"x", "x"}}, shouldError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCronCreate(&tt.input)
+			if tt.shouldError && err == nil {
+				t.Errorf("expected error but got nil")
+			}
+			if !tt.shouldError && err != nil {
+				t.Errorf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateTimezone(t *testing.T) {
+	for _, tz := range []string{"UTC", "Europe/Kyiv", "America/New_York"} {
+		if err := ValidateTimezone(tz); err != nil {
+			t.Errorf("ValidateTimezone(%q) error = %v", tz, err)
+		}
+	}
+	for _, tz := range []string{"", "Local", "Kyiv", "+02:00"} {
+		if err := ValidateTimezone(tz); err == nil {
+			t.Errorf("ValidateTimezone(%q) = nil, want error", tz)
+		}
+	}
+}
+
+func TestValidateCronPause(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		input       models.PauseCronRequest
+		shouldError bool
+	}{
+		{name: "future", input: models.PauseCronRequest{Until: now.Add(time.Hour), Reason: "incident"}},
+		{name: "no reason", input: models.PauseCronRequest{Until: now.Add(time.Minute)}},
+		{name: "missing until", input: models.PauseCronRequest{Reason: "incident"}, shouldError: true},
+		{name: "past", input: models.PauseCronRequest{Until: now.Add(-time.Hour)}, shouldError: true},
+		{name: "now", input: models.PauseCronRequest{Until: now}, shouldError: true},
+		{name: "long reason", input: models.PauseCronRequest{Until: now.Add(time.Hour), Reason: strings.Repeat("x", 501)}, shouldError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCronPause(&tt.input, now)
+			if tt.shouldError && err == nil {
+				t.Errorf("expected error but got nil")
+			}
+			if !tt.shouldError && err != nil {
+				t.Errorf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateCronJitter(t *testing.T) {
+	tests := []struct {
+		name        string
+		schedule    string
+		seconds     int
+		shouldError bool
+	}{
+		{name: "no jitter", schedule: "*/5 * * * *", seconds: 0},
+		{name: "daily twenty minutes", schedule: "12 12 * * *", seconds: 1200},
+		{name: "weekdays", schedule: "0 9 * * 1-5", seconds: 6 * 3600},
+		{name: "negative", schedule: "12 12 * * *", seconds: -1, shouldError: true},
+		{name: "half the gap", schedule: "0 * * * *", seconds: 1800, shouldError: true},
+		{name: "uneven gaps", schedule: "0 9,10 * * *", seconds: 45 * 60, shouldError: true},
+		{name: "uneven gaps fit", schedule: "0 9,10 * * *", seconds: 20 * 60},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCronJitter(tt.schedule, tt.seconds)
+			if tt.shouldError && err == nil {
+				t.Errorf("expected error but got nil")
+			}
+			if !tt.shouldError && err != nil {
+				t.Errorf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
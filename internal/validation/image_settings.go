@@ -0,0 +1,131 @@
+package validation
+
+import (
+	"content-maestro/internal/models"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	minImageWidth = 320
+	maxImageWidth = 2560
+)
+
+// The values socialify accepts.
+var (
+	validImageThemes   = []string{"Light", "Dark", "Auto"}
+	validImageFonts    = []string{"Inter", "Bitter", "Raleway", "Rokkitt", "Source Code Pro", "KoHo", "Jost"}
+	validImagePatterns = []string{"Signal", "Charlie Brown", "Formal Invitation", "Plus", "Circuit Board",
+		"Overlapping Hexagons", "Brick Wall", "Floating Cogs", "Diagonal Stripes", "Solid", "Transparent"}
+	validImageFormats = []string{models.ImageFormatPNG, models.ImageFormatJPEG}
+)
+
+var validImageProviders = []string{models.ImageProviderSocialify, models.ImageProviderLocal,
+	models.ImageProviderOpenGraph, models.ImageProviderStatic}
+
+// ValidateImageOverrides checks the image settings that are set; the rest are
+// left as they are.
+func ValidateImageOverrides(settings *models.ImageOverrides) error {
+	if settings.Provider != nil && !slices.Contains(validImageProviders, *settings.Provider) {
+		return fmt.Errorf("invalid provider: must be one of %s", strings.Join(validImageProviders, ", "))
+	}
+
+	if settings.SocialifyURL != nil && *settings.SocialifyURL != "" {
+		base, err := url.Parse(*settings.SocialifyURL)
+		if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" {
+			return fmt.Errorf("socialify_url must be an http or https URL")
+		}
+	}
+
+	if settings.StaticFile != nil && *settings.StaticFile != "" {
+		if err := validateStaticFile(*settings.StaticFile); err != nil {
+			return err
+		}
+	}
+
+	if settings.Theme != nil && !slices.Contains(validImageThemes, *settings.Theme) {
+		return fmt.Errorf("invalid theme: must be one of %s", strings.Join(validImageThemes, ", "))
+	}
+
+	if settings.Font != nil && !slices.Contains(validImageFonts, *settings.Font) {
+		return fmt.Errorf("invalid font: must be one of %s", strings.Join(validImageFonts, ", "))
+	}
+
+	if settings.Patterns != nil {
+		if err := validateImageList("patterns", *settings.Patterns, validImagePatterns); err != nil {
+			return err
+		}
+	}
+
+	if settings.Logo != nil && *settings.Logo != "" {
+		logo, err := url.Parse(*settings.Logo)
+		if err != nil || (logo.Scheme != "http" && logo.Scheme != "https") || logo.Host == "" {
+			return fmt.Errorf("logo must be an http or https URL")
+		}
+	}
+
+	if settings.Fields != nil {
+		if err := validateImageList("fields", *settings.Fields, models.ImageFields); err != nil {
+			return err
+		}
+	}
+
+	if settings.Format != nil && !slices.Contains(validImageFormats, *settings.Format) {
+		return fmt.Errorf("invalid format: must be one of %s", strings.Join(validImageFormats, ", "))
+	}
+
+	if settings.Width != nil && (*settings.Width < minImageWidth || *settings.Width > maxImageWidth) {
+		return fmt.Errorf("width must be between %d and %d", minImageWidth, maxImageWidth)
+	}
+
+	return nil
+}
+
+// ValidateImageSettings checks image settings as they take effect, once
+// overrides are applied: ValidateImageOverrides only sees the fields a request
+// sets.
+func ValidateImageSettings(settings models.ImageSettings) error {
+	if settings.Provider == models.ImageProviderStatic && settings.StaticFile == "" {
+		return fmt.Errorf("provider %s requires a static_file", models.ImageProviderStatic)
+	}
+	return nil
+}
+
+// validateStaticFile keeps the static image inside the working directory: the
+// path comes from an API request, and the file ends up in public posts.
+func validateStaticFile(path string) error {
+	if filepath.IsAbs(path) || !filepath.IsLocal(path) {
+		return fmt.Errorf("static_file must be a path inside the working directory")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("static_file %s cannot be read: %v", path, err)
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("static_file %s is not a regular file", path)
+	}
+	return nil
+}
+
+func validateImageList(name string, values, valid []string) error {
+	if len(values) == 0 {
+		return fmt.Errorf("%s cannot be empty", name)
+	}
+
+	seen := make(map[string]bool, len(values))
+	for _, value := range values {
+		if !slices.Contains(valid, value) {
+			return fmt.Errorf("invalid %s value %q: must be one of %s", name, value, strings.Join(valid, ", "))
+		}
+		if seen[value] {
+			return fmt.Errorf("%s value '%s' is listed more than once", name, value)
+		}
+		seen[value] = true
+	}
+	return nil
+}
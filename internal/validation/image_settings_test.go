@@ -0,0 +1,95 @@
+package validation
+
+import (
+	"content-maestro/internal/models"
+	"testing"
+)
+
+func TestValidateImageOverrides(t *testing.T) {
+	text := func(s string) *string { return &s }
+	list := func(s ...string) *[]string { return &s }
+	number := func(n int) *int { return &n }
+
+	tests := []struct {
+		name        string
+		input       models.ImageOverrides
+		shouldError bool
+	}{
+		{name: "nothing set", input: models.ImageOverrides{}},
+		{name: "all set", input: models.ImageOverrides{
+			Theme: text("Dark"), Font: text("Source Code Pro"), Patterns: list("Plus", "Solid"),
+			Logo: text("https://example.com/logo.svg"), Fields: list("name", "stargazers"),
+			Format: text("jpeg"), Width: number(640),
+		}},
+		{name: "logo removed", input: models.ImageOverrides{Logo: text("")}},
+		{name: "self-hosted socialify", input: models.ImageOverrides{
+			Provider: text("socialify"), SocialifyURL: text("https://socialify.example.com"),
+		}},
+		{name: "socialify url removed", input: models.ImageOverrides{SocialifyURL: text("")}},
+		{name: "static file", input: models.ImageOverrides{Provider: text("static"), StaticFile: text("image_settings_test.go")}},
+		{name: "local provider", input: models.ImageOverrides{Provider: text("local")}},
+		{name: "opengraph provider", input: models.ImageOverrides{Provider: text("github_opengraph")}},
+		{name: "unknown provider", input: models.ImageOverrides{Provider: text("imgur")}, shouldError: true},
+		{name: "socialify url without scheme", input: models.ImageOverrides{SocialifyURL: text("socialify.example.com")}, shouldError: true},
+		{name: "absolute static file", input: models.ImageOverrides{StaticFile: text("/etc/passwd")}, shouldError: true},
+		{name: "static file outside working directory", input: models.ImageOverrides{StaticFile: text("../validation/image_settings.go")}, shouldError: true},
+		{name: "missing static file", input: models.ImageOverrides{StaticFile: text("missing.png")}, shouldError: true},
+		{name: "static file is a directory", input: models.ImageOverrides{StaticFile: text(".")}, shouldError: true},
+		{name: "unknown theme", input: models.ImageOverrides{Theme: text("light")}, shouldError: true},
+		{name: "unknown font", input: models.ImageOverrides{Font: text("Comic Sans")}, shouldError: true},
+		{name: "no patterns", input: models.ImageOverrides{Patterns: list()}, shouldError: true},
+		{name: "unknown pattern", input: models.ImageOverrides{Patterns: list("Plus", "Polka Dots")}, shouldError: true},
+		{name: "duplicate pattern", input: models.ImageOverrides{Patterns: list("Plus", "Plus")}, shouldError: true},
+		{name: "relative logo", input: models.ImageOverrides{Logo: text("/logo.svg")}, shouldError: true},
+		{name: "file logo", input: models.ImageOverrides{Logo: text("file:///etc/passwd")}, shouldError: true},
+		{name: "no fields", input: models.ImageOverrides{Fields: list()}, shouldError: true},
+		{name: "unknown field", input: models.ImageOverrides{Fields: list("name", "watchers")}, shouldError: true},
+		{name: "svg format", input: models.ImageOverrides{Format: text("svg")}, shouldError: true},
+		{name: "too narrow", input: models.ImageOverrides{Width: number(100)}, shouldError: true},
+		{name: "too wide", input: models.ImageOverrides{Width: number(4096)}, shouldError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateImageOverrides(&tt.input)
+			if tt.shouldError && err == nil {
+				t.Errorf("expected error but got nil")
+			}
+			if !tt.shouldError && err != nil {
+				t.Errorf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateImageSettings(t *testing.T) {
+	withProvider := func(provider, staticFile string) models.ImageSettings {
+		settings := models.DefaultImageSettings()
+		settings.Provider = provider
+		settings.StaticFile = staticFile
+		return settings
+	}
+
+	tests := []struct {
+		name        string
+		input       models.ImageSettings
+		shouldError bool
+	}{
+		{name: "defaults", input: models.DefaultImageSettings()},
+		{name: "static with a file", input: withProvider("static", "card.png")},
+		{name: "file left over from static", input: withProvider("local", "card.png")},
+		{name: "static without a file", input: withProvider("static", ""), shouldError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateImageSettings(tt.input)
+			if tt.shouldError && err == nil {
+				t.Errorf("expected error but got nil")
+			}
+			if !tt.shouldError && err != nil {
+				t.Errorf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
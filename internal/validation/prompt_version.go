@@ -0,0 +1,44 @@
+package validation
+
+import (
+	"content-maestro/internal/models"
+	"fmt"
+)
+
+// ValidatePromptVersionCreate checks a new prompt version. Its name is used in
+// URLs and in the cron history, so it follows cron names.
+func ValidatePromptVersionCreate(req *models.CreatePromptVersionRequest) error {
+	if req.Name == "" {
+		return fmt.Errorf("name cannot be empty")
+	}
+	if !cronNamePattern.MatchString(req.Name) {
+		return fmt.Errorf("name must contain only alphanumeric characters, hyphens, and underscores")
+	}
+
+	content := req.Content
+	settings := &models.UpdatePromptSettingsRequest{Content: &content, Temperature: req.Temperature}
+	if req.Model != "" {
+		settings.Model = &req.Model
+	}
+	return ValidatePromptSettings(settings)
+}
+
+// ValidatePromptVersionWeights checks the prompt versions assigned to a collect
+// profile.
+func ValidatePromptVersionWeights(versions []models.PromptVersionWeight) error {
+	seen := make(map[string]bool, len(versions))
+	for _, version := range versions {
+		if version.Name == "" {
+			return fmt.Errorf("prompt version name cannot be empty")
+		}
+		if seen[version.Name] {
+			return fmt.Errorf("duplicate prompt version: %s", version.Name)
+		}
+		seen[version.Name] = true
+
+		if version.Weight < 0 {
+			return fmt.Errorf("weight of prompt version %s cannot be negative", version.Name)
+		}
+	}
+	return nil
+}
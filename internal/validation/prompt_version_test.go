@@ -0,0 +1,63 @@
+package validation
+
+import (
+	"content-maestro/internal/models"
+	"testing"
+)
+
+func TestValidatePromptVersionCreate(t *testing.T) {
+	temperature := 0.7
+	tooHot := 2.5
+
+	tests := []struct {
+		name        string
+		input       models.CreatePromptVersionRequest
+		shouldError bool
+	}{
+		{name: "content only", input: models.CreatePromptVersionRequest{Name: "concise", Content: "Be brief."}},
+		{name: "model and temperature", input: models.CreatePromptVersionRequest{Name: "concise_v2", Content: "Be brief.", Model: "gpt-4o", Temperature: &temperature}},
+		{name: "empty name", input: models.CreatePromptVersionRequest{Content: "Be brief."}, shouldError: true},
+		{name: "name with spaces", input: models.CreatePromptVersionRequest{Name: "very concise", Content: "Be brief."}, shouldError: true},
+		{name: "empty content", input: models.CreatePromptVersionRequest{Name: "concise"}, shouldError: true},
+		{name: "temperature out of range", input: models.CreatePromptVersionRequest{Name: "concise", Content: "Be brief.", Temperature: &tooHot}, shouldError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePromptVersionCreate(&tt.input)
+			if tt.shouldError && err == nil {
+				t.Errorf("expected error but got nil")
+			}
+			if !tt.shouldError && err != nil {
+				t.Errorf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidatePromptVersionWeights(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       []models.PromptVersionWeight
+		shouldError bool
+	}{
+		{name: "none", input: nil},
+		{name: "single version", input: []models.PromptVersionWeight{{Name: "concise"}}},
+		{name: "weighted", input: []models.PromptVersionWeight{{Name: "concise", Weight: 70}, {Name: "detailed", Weight: 30}}},
+		{name: "empty name", input: []models.PromptVersionWeight{{Weight: 1}}, shouldError: true},
+		{name: "duplicate", input: []models.PromptVersionWeight{{Name: "concise"}, {Name: "concise"}}, shouldError: true},
+		{name: "negative weight", input: []models.PromptVersionWeight{{Name: "concise", Weight: -1}}, shouldError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePromptVersionWeights(tt.input)
+			if tt.shouldError && err == nil {
+				t.Errorf("expected error but got nil")
+			}
+			if !tt.shouldError && err != nil {
+				t.Errorf("expected no error but got: %v", err)
+			}
+		})
+	}
+}